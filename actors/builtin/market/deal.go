@@ -213,6 +213,18 @@ type ClientDealProposal struct {
 	ClientSignature acrypto.Signature
 }
 
+// DealExtension extends the term of an active deal to a later end epoch, at the deal's existing price.
+type DealExtension struct {
+	DealID      abi.DealID
+	NewEndEpoch abi.ChainEpoch
+}
+
+// ClientDealExtension is a DealExtension signed by the deal's client
+type ClientDealExtension struct {
+	Extension       DealExtension
+	ClientSignature acrypto.Signature
+}
+
 func (p *DealProposal) Duration() abi.ChainEpoch {
 	return p.EndEpoch - p.StartEpoch
 }
@@ -1,6 +1,8 @@
 package market
 
 import (
+	"bytes"
+	"fmt"
 	"sort"
 
 	addr "github.com/filecoin-project/go-address"
@@ -13,7 +15,6 @@ import (
 	market0 "github.com/filecoin-project/specs-actors/actors/builtin/market"
 	market3 "github.com/filecoin-project/specs-actors/v3/actors/builtin/market"
 	market5 "github.com/filecoin-project/specs-actors/v5/actors/builtin/market"
-	market6 "github.com/filecoin-project/specs-actors/v6/actors/builtin/market"
 	"github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	"golang.org/x/xerrors"
@@ -41,6 +42,24 @@ func (a Actor) Exports() []interface{} {
 		7:                         a.OnMinerSectorsTerminate,
 		8:                         a.ComputeDataCommitment,
 		9:                         a.CronTick,
+		10:                        a.SettleDealPayments,
+		11:                        a.GetBalance,
+		12:                        a.GetDealDataCommitment,
+		13:                        a.GetDealClient,
+		14:                        a.GetDealProvider,
+		15:                        a.GetDealLabel,
+		16:                        a.GetDealTerm,
+		17:                        a.GetDealPricePerEpoch,
+		18:                        a.GetDealVerified,
+		19:                        a.GetDealActivation,
+		20:                        a.CancelDeals,
+		21:                        a.ExtendDeals,
+		22:                        a.GetDealsForPiece,
+		23:                        a.SetAutoWithdraw,
+		24:                        a.GetEscrowUnlockSchedule,
+		25:                        a.BatchActivateDeals,
+		26:                        a.OnMinerSectorsPreCommit,
+		27:                        a.OnMinerPreCommitsRemoved,
 	}
 }
 
@@ -140,15 +159,62 @@ func (a Actor) AddBalance(rt Runtime, providerOrClientAddress *addr.Address) *ab
 	return nil
 }
 
+type SetAutoWithdrawParams struct {
+	ProviderOrClientAddress addr.Address
+	// Account to which released escrow is withdrawn, or nil to disable automatic withdrawal.
+	Recipient *addr.Address
+}
+
+// Sets or clears the recipient to which escrow released by SettleDealPayments is automatically withdrawn.
+// Only the address to which the balance would be withdrawn may set it: the owner of a provider, or the client.
+// The recipient must be an account actor, and is stored as its ID address.
+func (a Actor) SetAutoWithdraw(rt Runtime, params *SetAutoWithdrawParams) *abi.EmptyValue {
+	nominal, recipient, _ := escrowAddress(rt, params.ProviderOrClientAddress)
+	rt.ValidateImmediateCallerIs(recipient)
+
+	var withdrawTo *addr.Address
+	if params.Recipient != nil {
+		resolved := resolveAutoWithdrawRecipient(rt, *params.Recipient)
+		withdrawTo = &resolved
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withAutoWithdrawRecipients(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		if withdrawTo == nil {
+			_, err = msm.autoWithdrawRecipients.TryDelete(abi.AddrKey(nominal))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to clear auto-withdraw recipient for %v", nominal)
+		} else {
+			err = msm.autoWithdrawRecipients.Put(abi.AddrKey(nominal), withdrawTo)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set auto-withdraw recipient for %v", nominal)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
+// Changed in v8:
+// - Added AllocationIDs, encoded only if some deal references a client allocation (see MarshalCBOR)
 type PublishStorageDealsParams struct {
 	Deals []ClientDealProposal
+	// Allocations created by the clients of verified deals for the deals to claim, in the order of Deals.
+	// A deal with no entry, or verifreg.NoAllocationID, is allocated DataCap from its client when published.
+	AllocationIDs []verifreg.AllocationID
 }
 
-//type PublishStorageDealsReturn struct {
-//	IDs        []abi.DealID
-//	ValidDeals bitfield.BitField
-//}
-type PublishStorageDealsReturn = market6.PublishStorageDealsReturn
+// Changed in v8:
+// - Added DealErrors, encoded only if some deal is invalid (see MarshalCBOR)
+type PublishStorageDealsReturn struct {
+	IDs        []abi.DealID
+	ValidDeals bitfield.BitField
+	// The exit code for each deal in the parameters, in order, which is Ok for each valid deal.
+	// Empty if all deals are valid.
+	DealErrors []exitcode.ExitCode
+}
 
 // Publish a new set of storage deals (not yet included in a sector).
 func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams) *PublishStorageDealsReturn {
@@ -158,6 +224,8 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	if len(params.Deals) == 0 {
 		rt.Abortf(exitcode.ErrIllegalArgument, "empty deals parameter")
 	}
+	builtin.RequireParam(rt, len(params.AllocationIDs) <= len(params.Deals),
+		"%d allocation IDs for %d deals", len(params.AllocationIDs), len(params.Deals))
 
 	// All deals should have the same provider so get worker once
 	providerRaw := params.Deals[0].Proposal.Provider
@@ -193,10 +261,18 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	proposalCidLookup := make(map[cid.Cid]struct{})
 	validProposalCids := make([]cid.Cid, 0)
 	validDeals := make([]ClientDealProposal, 0, len(params.Deals))
+	validAllocationIDs := make([]verifreg.AllocationID, 0, len(params.Deals))
 	totalClientLockup := make(map[addr.Address]abi.TokenAmount)
 	totalProviderLockup := abi.NewTokenAmount(0)
 
 	validInputBf := bitfield.New()
+	dealErrors := make([]exitcode.ExitCode, len(params.Deals))
+	anyInvalid := false
+	reject := func(di int, code exitcode.ExitCode, format string, args ...interface{}) {
+		rt.Log(rtt.INFO, "invalid deal %d: %s", di, fmt.Sprintf(format, args...))
+		dealErrors[di] = code
+		anyInvalid = true
+	}
 	rt.StateReadonly(&st)
 	msm, err := st.mutator(adt.AsStore(rt)).withPendingProposals(ReadOnlyPermission).
 		withEscrowTable(ReadOnlyPermission).withLockedTable(ReadOnlyPermission).build()
@@ -205,17 +281,21 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		/*
 			drop malformed deals
 		*/
+		if err := dealProposalIsInternallyValid(rt, deal); err != nil {
+			reject(di, exitcode.ErrIllegalArgument, "%s", err)
+			continue
+		}
 		if err := validateDeal(rt, deal, networkRawPower, networkQAPower, baselinePower); err != nil {
-			rt.Log(rtt.INFO, "invalid deal %d: %s", di, err)
+			reject(di, exitcode.ErrIllegalArgument, "%s", err)
 			continue
 		}
 		if deal.Proposal.Provider != provider && deal.Proposal.Provider != providerRaw {
-			rt.Log(rtt.INFO, "invalid deal %d: cannot publish deals from multiple providers in one batch", di)
+			reject(di, exitcode.ErrIllegalArgument, "cannot publish deals from multiple providers in one batch")
 			continue
 		}
 		client, ok := rt.ResolveAddress(deal.Proposal.Client)
 		if !ok {
-			rt.Log(rtt.INFO, "invalid deal %d: failed to resolve proposal.Client address %v for deal ", di, deal.Proposal.Client)
+			reject(di, exitcode.ErrNotFound, "failed to resolve proposal.Client address %v", deal.Proposal.Client)
 			continue
 		}
 
@@ -229,14 +309,14 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		clientBalanceOk, err := msm.balanceCovered(client, totalClientLockup[client])
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check client balance coverage")
 		if !clientBalanceOk {
-			rt.Log(rtt.INFO, "invalid deal: %d: insufficient client funds to cover proposal cost", di)
+			reject(di, exitcode.ErrInsufficientFunds, "insufficient client funds to cover proposal cost")
 			continue
 		}
 		totalProviderLockup = big.Sum(totalProviderLockup, deal.Proposal.ProviderCollateral)
 		providerBalanceOk, err := msm.balanceCovered(provider, totalProviderLockup)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check provider balance coverage")
 		if !providerBalanceOk {
-			rt.Log(rtt.INFO, "invalid deal: %d: insufficient provider funds to cover proposal cost", di)
+			reject(di, exitcode.ErrInsufficientFunds, "insufficient provider funds to cover proposal cost")
 			continue
 		}
 
@@ -258,7 +338,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check for existence of deal proposal")
 		_, duplicateInMessage := proposalCidLookup[pcid]
 		if duplicateInState || duplicateInMessage {
-			rt.Log(rtt.INFO, "invalid deal %d: cannot publish duplicate deal proposal %s", di)
+			reject(di, exitcode.ErrIllegalArgument, "cannot publish duplicate deal proposal %s", pcid)
 			continue
 		}
 
@@ -266,7 +346,48 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			check VerifiedClient allowed cap and deduct PieceSize from cap
 			drop deals with a DealSize that cannot be fully covered by VerifiedClient's available DataCap
 		*/
-		if deal.Proposal.VerifiedDeal {
+		claimsAllocation := deal.Proposal.VerifiedDeal && rt.NetworkVersion() >= DealAllocationClaimsVersion
+		clientAllocationID := verifreg.NoAllocationID
+		if di < len(params.AllocationIDs) {
+			clientAllocationID = params.AllocationIDs[di]
+		}
+		if clientAllocationID != verifreg.NoAllocationID && !claimsAllocation {
+			reject(di, exitcode.ErrIllegalArgument, "deal cannot claim client allocation %d", clientAllocationID)
+			continue
+		}
+
+		var allocationID verifreg.AllocationID
+		if claimsAllocation {
+			// Allocate the datacap to the deal's provider and piece, to be claimed when the deal is activated,
+			// or transfer the client's allocation for the deal to it.
+			var out builtin.CBORBytes
+			code := rt.Send(
+				builtin.VerifiedRegistryActorAddr,
+				builtin.MethodsVerifiedRegistry.AllocateDealDataCap,
+				&verifreg.AllocateDealDataCapParams{
+					Client: client,
+					Allocation: verifreg.AllocationRequest{
+						Provider:   provider,
+						Data:       deal.Proposal.PieceCID,
+						Size:       deal.Proposal.PieceSize,
+						TermMin:    deal.Proposal.Duration(),
+						TermMax:    deal.Proposal.EndEpoch - rt.CurrEpoch(),
+						Expiration: deal.Proposal.StartEpoch,
+					},
+					ClientAllocationID: clientAllocationID,
+				},
+				abi.NewTokenAmount(0),
+				&out,
+			)
+			if code.IsError() {
+				reject(di, code, "failed to allocate datacap exitcode: %d", code)
+				continue
+			}
+			var ret verifreg.AllocateDealDataCapReturn
+			err = ret.UnmarshalCBOR(bytes.NewReader(out))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to decode allocation of deal %d", di)
+			allocationID = ret.AllocationID
+		} else if deal.Proposal.VerifiedDeal {
 			code := rt.Send(
 				builtin.VerifiedRegistryActorAddr,
 				builtin.MethodsVerifiedRegistry.UseBytes,
@@ -278,7 +399,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 				&builtin.Discard{},
 			)
 			if code.IsError() {
-				rt.Log(rtt.INFO, "invalid deal %d: failed to acquire datacap exitcode: %d", di, code)
+				reject(di, code, "failed to acquire datacap exitcode: %d", code)
 				continue
 			}
 		}
@@ -287,6 +408,7 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		proposalCidLookup[pcid] = struct{}{}
 		validProposalCids = append(validProposalCids, pcid)
 		validDeals = append(validDeals, deal)
+		validAllocationIDs = append(validAllocationIDs, allocationID)
 		validInputBf.Set(uint64(di))
 	}
 
@@ -302,7 +424,8 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 	rt.StateTransaction(&st, func() {
 		msm, err := st.mutator(adt.AsStore(rt)).withPendingProposals(WritePermission).
 			withDealProposals(WritePermission).withDealsByEpoch(WritePermission).withEscrowTable(WritePermission).
-			withLockedTable(WritePermission).build()
+			withLockedTable(WritePermission).withDealsByPiece(WritePermission).
+			withDealsByParty(WritePermission).withDealAllocationIds(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
 		// All storage dealProposals will be added in an atomic transaction; this operation will be unrolled if any of them fails.
@@ -320,6 +443,19 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 			err = msm.dealProposals.Set(id, &validDeal.Proposal)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal")
 
+			if PieceDealIndexEnabled {
+				err = msm.dealsByPiece.Put(validDeal.Proposal.PieceCID, id)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to index deal %d by piece", id)
+			}
+			err = msm.dealsByParty.Put(&validDeal.Proposal, id)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to index deal %d by party", id)
+
+			if allocationID := validAllocationIDs[vdi]; allocationID != 0 {
+				allocationIDValue := cbg.CborInt(allocationID)
+				err = msm.dealAllocationIds.Put(abi.UIntKey(uint64(id)), &allocationIDValue)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record allocation of deal %d", id)
+			}
+
 			// We randomize the first epoch for when the deal will be processed so an attacker isn't able to
 			// schedule too many deals for the same tick.
 			processEpoch := GenRandNextEpoch(validDeal.Proposal.StartEpoch, id)
@@ -333,10 +469,14 @@ func (a Actor) PublishStorageDeals(rt Runtime, params *PublishStorageDealsParams
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
 
-	return &PublishStorageDealsReturn{
+	ret := PublishStorageDealsReturn{
 		IDs:        newDealIds,
 		ValidDeals: validInputBf,
 	}
+	if anyInvalid {
+		ret.DealErrors = dealErrors
+	}
+	return &ret
 }
 
 // Changed in v3:
@@ -405,6 +545,80 @@ func (a Actor) VerifyDealsForActivation(rt Runtime, params *VerifyDealsForActiva
 	}
 }
 
+type OnMinerSectorsPreCommitParams struct {
+	DealIDs []abi.DealID
+}
+
+// Records deals as included in the calling provider's sector pre-commitments, after which their clients
+// may no longer cancel them.
+func (a Actor) OnMinerSectorsPreCommit(rt Runtime, params *OnMinerSectorsPreCommitParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
+	minerAddr := rt.Caller()
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(ReadOnlyPermission).
+			withDealStates(ReadOnlyPermission).withPreCommittedDeals(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, dealID := range params.DealIDs {
+			deal, err := getDealProposal(msm.dealProposals, dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+			if deal.Provider != minerAddr {
+				rt.Abortf(exitcode.ErrForbidden, "deal %d has provider %v, not caller %v", dealID, deal.Provider, minerAddr)
+			}
+			_, activated, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", dealID)
+			if activated {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d has already been activated", dealID)
+			}
+		}
+
+		err = msm.recordDealsPreCommitted(params.DealIDs)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record pre-committed deals")
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
+type OnMinerPreCommitsRemovedParams struct {
+	DealIDs []abi.DealID
+}
+
+// Clears the pre-commitment of deals whose sector pre-commitments the calling provider has cancelled
+// or which have expired unproven, after which their clients may again cancel them.
+// Deals which have since been activated or deleted are ignored.
+func (a Actor) OnMinerPreCommitsRemoved(rt Runtime, params *OnMinerPreCommitsRemovedParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
+	minerAddr := rt.Caller()
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(ReadOnlyPermission).
+			withPreCommittedDeals(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, dealID := range params.DealIDs {
+			deal, found, err := msm.dealProposals.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+			if !found {
+				continue
+			}
+			if deal.Provider != minerAddr {
+				rt.Abortf(exitcode.ErrForbidden, "deal %d has provider %v, not caller %v", dealID, deal.Provider, minerAddr)
+			}
+			_, err = msm.preCommittedDeals.TryDelete(abi.UIntKey(uint64(dealID)))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove pre-committed deal %d", dealID)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+	return nil
+}
+
 //type ActivateDealsParams struct {
 //	DealIDs      []abi.DealID
 //	SectorExpiry abi.ChainEpoch
@@ -420,51 +634,151 @@ func (a Actor) ActivateDeals(rt Runtime, params *ActivateDealsParams) *abi.Empty
 
 	var st State
 	store := adt.AsStore(rt)
+	var claims []verifreg.ClaimAllocationRequest
 
 	// Update deal dealStates.
 	rt.StateTransaction(&st, func() {
-		_, _, _, err := ValidateDealsForActivation(&st, store, params.DealIDs, minerAddr, params.SectorExpiry, currEpoch)
+		msm, err := st.mutator(store).withDealStates(WritePermission).
+			withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).
+			withPreCommittedDeals(WritePermission).withDealAllocationIds(ReadOnlyPermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		_, err = msm.validateSectorDealsActivation(params.DealIDs, minerAddr, params.SectorExpiry, currEpoch)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to validate dealProposals for activation")
 
-		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
-			withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).build()
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+		err = msm.recordDealsActivated(params.DealIDs, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to activate deals")
 
-		for _, dealID := range params.DealIDs {
-			// This construction could be replaced with a single "update deal state" state method, possibly batched
-			// over all deal ids at once.
-			_, found, err := msm.dealStates.Get(dealID)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get state for dealId %d", dealID)
-			if found {
-				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d already included in another sector", dealID)
-			}
+		claims, err = msm.allocationClaims(params.DealIDs)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to collect allocation claims")
 
-			proposal, err := getDealProposal(msm.dealProposals, dealID)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
 
-			propc, err := proposal.Cid()
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate proposal CID")
+	code := claimAllocations(rt, claims)
+	builtin.RequireSuccess(rt, code, "failed to claim allocations for %d activated verified deals", len(claims))
+	return nil
+}
+
+type SectorDealsActivation struct {
+	SectorType   abi.RegisteredSealProof
+	SectorExpiry abi.ChainEpoch
+	DealIDs      []abi.DealID
+}
 
-			has, err := msm.pendingDeals.Has(abi.CidKey(propc))
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get pending proposal %v", propc)
+type BatchActivateDealsParams struct {
+	Sectors []SectorDealsActivation
+	// Whether to compute the unsealed CID of each activated sector's deals.
+	ComputeCID bool
+}
 
-			if !has {
-				rt.Abortf(exitcode.ErrIllegalState, "tried to activate deal that was not in the pending set (%s)", propc)
+type SectorDealsActivated struct {
+	DealSpace          uint64         // Total space in bytes of the sector's deals.
+	DealWeight         abi.DealWeight // Total space*time of the sector's deals.
+	VerifiedDealWeight abi.DealWeight // Total space*time of the sector's verified deals.
+	// The unsealed CID of the sector's deals, if requested.
+	UnsealedCID *cid.Cid
+}
+
+type BatchActivateDealsReturn struct {
+	// Indices of the sectors whose deals were activated.
+	ActivatedSectors bitfield.BitField
+	// The weights of each activated sector, in the order of ActivatedSectors.
+	Sectors []SectorDealsActivated
+}
+
+// Activates the deals of a number of sectors currently being ProveCommitted or updated.
+// Each sector's deals are activated together, or not at all: a sector with any deal which cannot be activated,
+// or whose verified deals cannot all claim their allocations, is dropped from the batch without affecting the others.
+// Failures other than invalid deals abort the batch.
+func (a Actor) BatchActivateDeals(rt Runtime, params *BatchActivateDealsParams) *BatchActivateDealsReturn {
+	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
+	minerAddr := rt.Caller()
+	currEpoch := rt.CurrEpoch()
+	store := adt.AsStore(rt)
+
+	// Validate each sector's deals, recording them as activated so that no later sector of the batch may
+	// activate them. The recorded activations are not committed until the sectors' allocations are claimed.
+	var validIdxs []int
+	var validResults []SectorDealsActivated
+	var validClaims [][]verifreg.ClaimAllocationRequest
+	var st State
+	rt.StateReadonly(&st)
+	msm, err := st.mutator(store).withDealStates(WritePermission).
+		withPendingProposals(ReadOnlyPermission).withDealProposals(ReadOnlyPermission).
+		withPreCommittedDeals(WritePermission).withDealAllocationIds(ReadOnlyPermission).build()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+	for i, sector := range params.Sectors {
+		weights, err := msm.validateSectorDealsActivation(sector.DealIDs, minerAddr, sector.SectorExpiry, currEpoch)
+		if err != nil {
+			// A sector whose deals cannot be activated is dropped, but any other failure aborts the batch.
+			code := exitcode.Unwrap(err, exitcode.ErrIllegalState)
+			if code != exitcode.ErrIllegalArgument && code != exitcode.ErrForbidden && code != exitcode.ErrNotFound {
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to validate deals of sector %d for activation", i)
 			}
+			rt.Log(rtt.INFO, "failed to validate deals of sector %d for activation: %s", i, err)
+			continue
+		}
 
-			err = msm.dealStates.Set(dealID, &DealState{
-				SectorStartEpoch: currEpoch,
-				LastUpdatedEpoch: EpochUndefined,
-				SlashEpoch:       EpochUndefined,
-			})
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %d", dealID)
+		result := SectorDealsActivated{
+			DealSpace:          weights.DealSpace,
+			DealWeight:         weights.DealWeight,
+			VerifiedDealWeight: weights.VerifiedDealWeight,
+		}
+		if params.ComputeCID {
+			pieces, err := sectorPieces(msm.dealProposals, sector.DealIDs)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get pieces of sector %d", i)
+			commD, err := rt.ComputeUnsealedSectorCID(sector.SectorType, pieces)
+			if err != nil {
+				rt.Log(rtt.INFO, "failed to compute unsealed CID of sector %d: %s", i, err)
+				continue
+			}
+			result.UnsealedCID = &commD
 		}
 
-		err = msm.commitState()
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
-	})
+		err = msm.recordDealsActivated(sector.DealIDs, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to activate deals of sector %d", i)
+		sectorClaims, err := msm.allocationClaims(sector.DealIDs)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to collect allocation claims of sector %d", i)
 
-	return nil
+		validIdxs = append(validIdxs, i)
+		validResults = append(validResults, result)
+		validClaims = append(validClaims, sectorClaims)
+	}
+
+	// Claim each sector's allocations together, dropping the sectors whose claims are rejected.
+	var activatedIdxs []uint64
+	var activated []SectorDealsActivated
+	for j, i := range validIdxs {
+		if code := claimAllocations(rt, validClaims[j]); code.IsError() {
+			rt.Log(rtt.INFO, "failed to claim allocations of sector %d exitcode: %d", i, code)
+			continue
+		}
+		activatedIdxs = append(activatedIdxs, uint64(i))
+		activated = append(activated, validResults[j])
+	}
+
+	if len(activatedIdxs) > 0 {
+		rt.StateTransaction(&st, func() {
+			msm, err := st.mutator(store).withDealStates(WritePermission).
+				withPreCommittedDeals(WritePermission).build()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+			for _, i := range activatedIdxs {
+				err = msm.recordDealsActivated(params.Sectors[i].DealIDs, currEpoch)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to activate deals of sector %d", i)
+			}
+
+			err = msm.commitState()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+		})
+	}
+	return &BatchActivateDealsReturn{
+		ActivatedSectors: bitfield.NewFromSet(activatedIdxs),
+		Sectors:          activated,
+	}
 }
 
 //type SectorDataSpec struct {
@@ -492,16 +806,8 @@ func (a Actor) ComputeDataCommitment(rt Runtime, params *ComputeDataCommitmentPa
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal dealProposals")
 	commDs := make([]cbg.CborCid, len(params.Inputs))
 	for i, commInput := range params.Inputs {
-		pieces := make([]abi.PieceInfo, 0)
-		for _, dealID := range commInput.DealIDs {
-			deal, err := getDealProposal(proposals, dealID)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get dealId %d", dealID)
-
-			pieces = append(pieces, abi.PieceInfo{
-				PieceCID: deal.PieceCID,
-				Size:     deal.PieceSize,
-			})
-		}
+		pieces, err := sectorPieces(proposals, commInput.DealIDs)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get sector pieces")
 		commD, err := rt.ComputeUnsealedSectorCID(commInput.SectorType, pieces)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to compute unsealed sectorCID: %s", err)
 		commDs[i] = (cbg.CborCid)(commD)
@@ -520,14 +826,17 @@ type OnMinerSectorsTerminateParams = market0.OnMinerSectorsTerminateParams
 // Terminate a set of deals in response to their containing sector being terminated.
 // Slash provider collateral, refund client collateral, and refund partial unpaid escrow
 // amount to client.
+// The client of a verified deal has datacap restored in proportion to the deal's remaining term.
 func (a Actor) OnMinerSectorsTerminate(rt Runtime, params *OnMinerSectorsTerminateParams) *abi.EmptyValue {
 	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
 	minerAddr := rt.Caller()
 
+	var dataCapRefunds []verifreg.RestoreBytesParams
 	var st State
 	rt.StateTransaction(&st, func() {
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
-			withDealProposals(ReadOnlyPermission).build()
+			withDealProposals(ReadOnlyPermission).withDealsByEpoch(WritePermission).
+			withDealsByPiece(WritePermission).withDealAllocationIds(ReadOnlyPermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal state")
 
 		for _, dealID := range params.DealIDs {
@@ -563,19 +872,339 @@ func (a Actor) OnMinerSectorsTerminate(rt Runtime, params *OnMinerSectorsTermina
 			}
 
 			// mark the deal for slashing here.
-			// actual releasing of locked funds for the client and slashing of provider collateral happens in CronTick.
+			// actual releasing of locked funds for the client and slashing of provider collateral happens in CronTick,
+			// or earlier if the deal is settled.
+			prevOpEpoch := scheduledDealOpEpoch(dealID, deal, state, st.LastCron)
 			state.SlashEpoch = params.Epoch
 
 			err = msm.dealStates.Set(dealID, state)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %v", dealID)
+
+			// Reschedule the slashing for processing in cron rather than waiting for the deal's expiration.
+			// A deal yet to be processed at its start is already scheduled for the next opportunity.
+			if opEpoch := scheduledDealOpEpoch(dealID, deal, state, st.LastCron); opEpoch != prevOpEpoch {
+				err = msm.dealsByEpoch.Remove(prevOpEpoch, dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unschedule slashed deal %v", dealID)
+				err = msm.dealsByEpoch.Put(opEpoch, dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to schedule slashed deal %v", dealID)
+			}
+
+			// A terminated deal no longer stores its piece.
+			if PieceDealIndexEnabled {
+				err = msm.dealsByPiece.Remove(deal.PieceCID, dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unindex slashed deal %v", dealID)
+			}
+
+			// Datacap for the deal's unused term is restored, unless too small for the registry to accept.
+			// The datacap of a deal which claimed an allocation is held by the claim, and is not restored.
+			_, allocated, err := msm.dealAllocationID(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get allocation of deal %d", dealID)
+			if deal.VerifiedDeal && !allocated {
+				refund := DataCapRefundForDealTermination(deal.PieceSize, deal.StartEpoch, deal.EndEpoch, params.Epoch)
+				if refund.GreaterThanEqual(verifreg.MinVerifiedDealSize) {
+					dataCapRefunds = append(dataCapRefunds, verifreg.RestoreBytesParams{Address: deal.Client, DealSize: refund})
+				}
+			}
 		}
 
 		err = msm.commitState()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
 	})
+
+	for i := range dataCapRefunds {
+		refund := &dataCapRefunds[i]
+		code := rt.Send(
+			builtin.VerifiedRegistryActorAddr,
+			builtin.MethodsVerifiedRegistry.RestoreBytes,
+			refund,
+			abi.NewTokenAmount(0),
+			&builtin.Discard{},
+		)
+
+		if !code.IsSuccess() {
+			rt.Log(rtt.ERROR, "failed to send RestoreBytes call to the VerifReg actor for terminated verified deal, client: %s, "+
+				"refund: %v, provider: %v, got code %v", refund.Address, refund.DealSize, minerAddr, code)
+		}
+	}
 	return nil
 }
 
+type ExtendDealsParams struct {
+	// Expiration of the sector containing the deals, beyond which no deal may be extended.
+	SectorExpiry abi.ChainEpoch
+	Extensions   []ClientDealExtension
+}
+
+type ExtendDealsReturn struct {
+	// Weight added to the sector by the extensions, split into regular and verified deal weight.
+	DealWeight         abi.DealWeight
+	VerifiedDealWeight abi.DealWeight
+}
+
+// Extends the term of active deals, with the consent of their client as a signature over each extension.
+// The deals continue at their existing price, and the client's storage fee for the additional term is locked.
+// Extending a verified deal consumes the client's datacap in proportion to the added term,
+// since the extended term earns verified deal weight (see DataCapForDealExtension).
+// A deal may be extended beyond the maximum duration of a new deal, up to the expiration of its sector,
+// which bounds the provider's commitment to store it.
+// Called by the provider when extending the deals in one of its sectors.
+func (a Actor) ExtendDeals(rt Runtime, params *ExtendDealsParams) *ExtendDealsReturn {
+	rt.ValidateImmediateCallerType(builtin.StorageMinerActorCodeID)
+	minerAddr := rt.Caller()
+	currEpoch := rt.CurrEpoch()
+
+	ret := ExtendDealsReturn{
+		DealWeight:         big.Zero(),
+		VerifiedDealWeight: big.Zero(),
+	}
+	var dataCapCharges []verifreg.UseBytesParams
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(ReadOnlyPermission).
+			withLockedTable(WritePermission).withEscrowTable(ReadOnlyPermission).
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).
+			withDealsByEpoch(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, extension := range params.Extensions {
+			dealID := extension.Extension.DealID
+			newEnd := extension.Extension.NewEndEpoch
+
+			deal, err := getDealProposal(msm.dealProposals, dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+			if deal.Provider != minerAddr {
+				rt.Abortf(exitcode.ErrForbidden, "caller %v is not the provider %v of deal %d", minerAddr, deal.Provider, dealID)
+			}
+
+			state, found, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", dealID)
+			if !found {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d has not been activated", dealID)
+			}
+			if state.SlashEpoch != EpochUndefined {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d was terminated at %d", dealID, state.SlashEpoch)
+			}
+			if deal.EndEpoch <= currEpoch {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d expired at %d", dealID, deal.EndEpoch)
+			}
+			if newEnd <= deal.EndEpoch {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d new end epoch %d must be after %d", dealID, newEnd, deal.EndEpoch)
+			}
+			if newEnd > params.SectorExpiry {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d new end epoch %d after sector expiry %d", dealID, newEnd, params.SectorExpiry)
+			}
+			err = dealExtensionIsSigned(rt, extension, deal.Client)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid extension for deal %d", dealID)
+
+			oldCid, err := deal.Cid()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate CID for proposal %v", dealID)
+			oldOpEpoch := scheduledDealOpEpoch(dealID, deal, state, st.LastCron)
+
+			addedTerm := newEnd - deal.EndEpoch
+			if deal.VerifiedDeal {
+				dataCapCharges = append(dataCapCharges, verifreg.UseBytesParams{
+					Address:  deal.Client,
+					DealSize: DataCapForDealExtension(deal.PieceSize, deal.StartEpoch, deal.EndEpoch, newEnd),
+				})
+			}
+			addedFee := big.Mul(big.NewInt(int64(addedTerm)), deal.StoragePricePerEpoch)
+			err = msm.maybeLockBalance(deal.Client, addedFee)
+			builtin.RequireNoErr(rt, err, exitcode.ErrInsufficientFunds, "failed to lock client storage fee for deal %d", dealID)
+			msm.totalClientStorageFee = big.Add(msm.totalClientStorageFee, addedFee)
+
+			deal.EndEpoch = newEnd
+			err = msm.dealProposals.Set(dealID, deal)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal proposal %d", dealID)
+
+			// A deal awaiting its first update is still pending, keyed by its proposal CID.
+			if state.LastUpdatedEpoch == EpochUndefined {
+				newCid, err := deal.Cid()
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate CID for proposal %v", dealID)
+				err = msm.pendingDeals.Delete(abi.CidKey(oldCid))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %v", oldCid)
+				err = msm.pendingDeals.Put(abi.CidKey(newCid))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set pending proposal %v", newCid)
+			}
+			// A deal already processed at its start is rescheduled for processing at its new expiration.
+			if opEpoch := scheduledDealOpEpoch(dealID, deal, state, st.LastCron); opEpoch != oldOpEpoch {
+				err = msm.dealsByEpoch.Remove(oldOpEpoch, dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unschedule deal %d", dealID)
+				err = msm.dealsByEpoch.Put(opEpoch, dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to reschedule deal %d", dealID)
+			}
+
+			addedWeight := big.Mul(big.NewIntUnsigned(uint64(deal.PieceSize)), big.NewInt(int64(addedTerm)))
+			if deal.VerifiedDeal {
+				ret.VerifiedDealWeight = big.Add(ret.VerifiedDealWeight, addedWeight)
+			} else {
+				ret.DealWeight = big.Add(ret.DealWeight, addedWeight)
+			}
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+
+	for i := range dataCapCharges {
+		charge := &dataCapCharges[i]
+		code := rt.Send(
+			builtin.VerifiedRegistryActorAddr,
+			builtin.MethodsVerifiedRegistry.UseBytes,
+			charge,
+			abi.NewTokenAmount(0),
+			&builtin.Discard{},
+		)
+		builtin.RequireSuccess(rt, code, "failed to acquire datacap to extend verified deal with client %v", charge.Address)
+	}
+	return &ret
+}
+
+type SettleDealPaymentsParams struct {
+	DealIDs []abi.DealID
+}
+
+// The outcome of settling a single deal.
+type DealSettlementSummary struct {
+	DealID abi.DealID
+	// Payment transferred from client to provider by this settlement.
+	Payment abi.TokenAmount
+	// Whether the deal has expired or been slashed, and so been removed from state.
+	Completed bool
+}
+
+// A deal which could not be settled, and the reason.
+type DealSettlementError struct {
+	DealID abi.DealID
+	// ErrNotFound if the deal does not exist or has already completed,
+	// ErrIllegalArgument if the deal has not been activated.
+	Code exitcode.ExitCode
+}
+
+type SettleDealPaymentsReturn struct {
+	// Deals which were settled, in the order given.
+	Settlements []DealSettlementSummary
+	// Deals which could not be settled, in the order given.
+	Errors []DealSettlementError
+}
+
+// Settles payment for activated deals up to the current epoch, and completes any deals which
+// have expired or been slashed.
+// Any party, including a contract actor such as a multisig, may settle a deal, since funds move only
+// according to the deal's terms.
+// Escrow released to a party with an auto-withdrawal recipient is sent to that recipient.
+// If that send fails, the amount is returned to the party's escrow balance.
+// Deals which cannot be settled are reported in the return value rather than failing the message.
+func (a Actor) SettleDealPayments(rt Runtime, params *SettleDealPaymentsParams) *SettleDealPaymentsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	amountSlashed := big.Zero()
+	var ret SettleDealPaymentsReturn
+	var withdrawals []escrowWithdrawal
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
+			withLockedTable(WritePermission).withEscrowTable(WritePermission).
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).withDealsByEpoch(WritePermission).
+			withDealsByPiece(WritePermission).withDealsByParty(WritePermission).withPreCommittedDeals(WritePermission).
+			withDealAllocationIds(WritePermission).withAutoWithdrawRecipients(ReadOnlyPermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+		released := msm.trackReleasedEscrow()
+
+		for _, dealID := range params.DealIDs {
+			deal, found, err := msm.dealProposals.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal proposal %v", dealID)
+			if !found {
+				ret.Errors = append(ret.Errors, DealSettlementError{DealID: dealID, Code: exitcode.ErrNotFound})
+				continue
+			}
+
+			state, found, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %v", dealID)
+			if !found {
+				ret.Errors = append(ret.Errors, DealSettlementError{DealID: dealID, Code: exitcode.ErrIllegalArgument})
+				continue
+			}
+
+			// A deal activated ahead of its start epoch has nothing to settle yet.
+			if deal.StartEpoch > rt.CurrEpoch() {
+				ret.Settlements = append(ret.Settlements, DealSettlementSummary{DealID: dealID, Payment: big.Zero()})
+				continue
+			}
+
+			// The pending proposal is removed at the deal's first update, whether by cron or settlement.
+			if state.LastUpdatedEpoch == EpochUndefined {
+				dcid, err := deal.Cid()
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate CID for proposal %v", dealID)
+				err = msm.pendingDeals.Delete(abi.CidKey(dcid))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %v", dcid)
+			}
+
+			err = released.track(deal.Client)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to track client %v", deal.Client)
+			err = released.track(deal.Provider)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to track provider %v", deal.Provider)
+
+			slashAmount, payment, removeDeal := msm.updatePendingDealState(rt, state, deal, rt.CurrEpoch())
+			builtin.RequireState(rt, slashAmount.GreaterThanEqual(big.Zero()), "computed negative slash amount %v for deal %d", slashAmount, dealID)
+
+			if removeDeal {
+				amountSlashed = big.Add(amountSlashed, slashAmount)
+
+				// Delete proposal, state and the deal's remaining scheduled operation simultaneously.
+				err = msm.dealsByEpoch.Remove(scheduledDealOpEpoch(dealID, deal, state, st.LastCron), dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to unschedule deal %d", dealID)
+				err = msm.dealStates.Delete(dealID)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal state %d", dealID)
+				err = msm.deleteDealProposal(dealID, deal)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", dealID)
+			} else {
+				state.LastUpdatedEpoch = rt.CurrEpoch()
+				err = msm.dealStates.Set(dealID, state)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to set deal state %d", dealID)
+			}
+
+			ret.Settlements = append(ret.Settlements, DealSettlementSummary{
+				DealID:    dealID,
+				Payment:   payment,
+				Completed: removeDeal,
+			})
+		}
+
+		withdrawals, err = released.withdraw()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to withdraw released escrow")
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+
+	if !amountSlashed.IsZero() {
+		e := rt.Send(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, amountSlashed, &builtin.Discard{})
+		builtin.RequireSuccess(rt, e, "expected send to burnt funds actor to succeed")
+	}
+	var failedWithdrawals []escrowWithdrawal
+	for _, w := range withdrawals {
+		code := rt.Send(w.recipient, builtin.MethodSend, nil, w.amount, &builtin.Discard{})
+		if !code.IsSuccess() {
+			rt.Log(rtt.WARN, "failed to send released escrow %v for %v to %v: %v", w.amount, w.party, w.recipient, code)
+			failedWithdrawals = append(failedWithdrawals, w)
+		}
+	}
+	if len(failedWithdrawals) > 0 {
+		rt.StateTransaction(&st, func() {
+			msm, err := st.mutator(adt.AsStore(rt)).withEscrowTable(WritePermission).build()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+			for _, w := range failedWithdrawals {
+				err = msm.escrowTable.Add(w.party, w.amount)
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to return released escrow to %v", w.party)
+			}
+			err = msm.commitState()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+		})
+	}
+
+	return &ret
+}
+
 func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	rt.ValidateImmediateCallerIs(builtin.CronActorAddr)
 	amountSlashed := big.Zero()
@@ -588,7 +1217,9 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 
 		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(WritePermission).
 			withLockedTable(WritePermission).withEscrowTable(WritePermission).withDealsByEpoch(WritePermission).
-			withDealProposals(WritePermission).withPendingProposals(WritePermission).build()
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).
+			withDealsByPiece(WritePermission).withDealsByParty(WritePermission).
+			withPreCommittedDeals(WritePermission).withDealAllocationIds(WritePermission).build()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
 
 		for i := st.LastCron + 1; i <= rt.CurrEpoch(); i++ {
@@ -612,12 +1243,15 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					if !slashed.IsZero() {
 						amountSlashed = big.Add(amountSlashed, slashed)
 					}
-					if deal.VerifiedDeal {
+					// The datacap of a deal with an allocation is reclaimed from the verified registry once it expires.
+					_, allocated, err := msm.dealAllocationID(dealID)
+					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get allocation of deal %d", dealID)
+					if deal.VerifiedDeal && !allocated {
 						timedOutVerifiedDeals = append(timedOutVerifiedDeals, deal)
 					}
 
 					// Delete the proposal (but not state, which doesn't exist).
-					err = msm.dealProposals.Delete(dealID)
+					err = msm.deleteDealProposal(dealID, deal)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", dealID)
 
 					err = msm.pendingDeals.Delete(abi.CidKey(dcid))
@@ -631,19 +1265,22 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 					builtin.RequireNoErr(rt, pdErr, exitcode.ErrIllegalState, "failed to delete pending proposal %v", dcid)
 				}
 
-				slashAmount, nextEpoch, removeDeal := msm.updatePendingDealState(rt, state, deal, rt.CurrEpoch())
+				slashAmount, _, removeDeal := msm.updatePendingDealState(rt, state, deal, rt.CurrEpoch())
 				builtin.RequireState(rt, slashAmount.GreaterThanEqual(big.Zero()), "computed negative slash amount %v for deal %d", slashAmount, dealID)
 
 				if removeDeal {
-					builtin.RequireState(rt, nextEpoch == EpochUndefined, "removed deal %d should have no scheduled epoch (got %d)", dealID, nextEpoch)
 					amountSlashed = big.Add(amountSlashed, slashAmount)
 
 					// Delete proposal and state simultaneously.
 					err = msm.dealStates.Delete(dealID)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal state %d", dealID)
-					err = msm.dealProposals.Delete(dealID)
+					err = msm.deleteDealProposal(dealID, deal)
 					builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", dealID)
 				} else {
+					// Payment is settled on demand by SettleDealPayments, so cron next processes the deal at its expiration.
+					// We're explicitly not processing the expiration exactly at the end epoch, in order to prevent an outsider
+					// from loading a cron tick by activating too many deals with the same end epoch.
+					nextEpoch := GenRandNextEpoch(deal.EndEpoch, dealID)
 					builtin.RequireState(rt, nextEpoch > rt.CurrEpoch(), "continuing deal %d next epoch %d should be in future", dealID, nextEpoch)
 					builtin.RequireState(rt, slashAmount.IsZero(), "continuing deal %d should not be slashed", dealID)
 
@@ -710,6 +1347,459 @@ func (a Actor) CronTick(rt Runtime, _ *abi.EmptyValue) *abi.EmptyValue {
 	return nil
 }
 
+type CancelDealsParams struct {
+	DealIDs []abi.DealID
+}
+
+// Cancels published deals which have not been activated, at the request of their client.
+// The client's funds for the deal are unlocked, and it pays the cancellation penalty to the provider.
+// The provider's collateral is unlocked.
+// A deal cannot be cancelled once its provider has included it in a sector pre-commitment, nor from its
+// start epoch, after which an unactivated deal is timed out by cron.
+func (a Actor) CancelDeals(rt Runtime, params *CancelDealsParams) *abi.EmptyValue {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	client := rt.Caller()
+
+	var cancelledVerifiedDeals []*DealProposal
+	var st State
+	rt.StateTransaction(&st, func() {
+		msm, err := st.mutator(adt.AsStore(rt)).withDealStates(ReadOnlyPermission).
+			withLockedTable(WritePermission).withEscrowTable(WritePermission).withDealsByEpoch(WritePermission).
+			withDealProposals(WritePermission).withPendingProposals(WritePermission).
+			withDealsByPiece(WritePermission).withDealsByParty(WritePermission).
+			withPreCommittedDeals(WritePermission).withDealAllocationIds(WritePermission).build()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+		for _, dealID := range params.DealIDs {
+			deal, err := getDealProposal(msm.dealProposals, dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+			if deal.Client != client {
+				rt.Abortf(exitcode.ErrForbidden, "caller %v is not the client %v of deal %d", client, deal.Client, dealID)
+			}
+			if rt.CurrEpoch() >= deal.StartEpoch {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d cannot be cancelled from its start epoch %d", dealID, deal.StartEpoch)
+			}
+
+			_, found, err := msm.dealStates.Get(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", dealID)
+			if found {
+				rt.Abortf(exitcode.ErrIllegalArgument, "deal %d has been activated", dealID)
+			}
+			preCommitted, err := msm.preCommittedDeals.Has(abi.UIntKey(uint64(dealID)))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check pre-commitment of deal %d", dealID)
+			if preCommitted {
+				rt.Abortf(exitcode.ErrForbidden, "deal %d has been pre-committed by its provider", dealID)
+			}
+
+			msm.processDealCancelled(rt, deal)
+			// The datacap of a deal with an allocation is reclaimed from the verified registry once it expires.
+			_, allocated, err := msm.dealAllocationID(dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get allocation of deal %d", dealID)
+			if deal.VerifiedDeal && !allocated {
+				cancelledVerifiedDeals = append(cancelledVerifiedDeals, deal)
+			}
+
+			dcid, err := deal.Cid()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate CID for proposal %v", dealID)
+
+			err = msm.deleteDealProposal(dealID, deal)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete deal proposal %d", dealID)
+			err = msm.pendingDeals.Delete(abi.CidKey(dcid))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pending proposal %d (%v)", dealID, dcid)
+			err = msm.dealsByEpoch.Remove(GenRandNextEpoch(deal.StartEpoch, dealID), dealID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove deal op for deal %d", dealID)
+		}
+
+		err = msm.commitState()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush state")
+	})
+
+	for _, d := range cancelledVerifiedDeals {
+		code := rt.Send(
+			builtin.VerifiedRegistryActorAddr,
+			builtin.MethodsVerifiedRegistry.RestoreBytes,
+			&verifreg.RestoreBytesParams{
+				Address:  d.Client,
+				DealSize: big.NewIntUnsigned(uint64(d.PieceSize)),
+			},
+			abi.NewTokenAmount(0),
+			&builtin.Discard{},
+		)
+
+		if !code.IsSuccess() {
+			rt.Log(rtt.ERROR, "failed to send RestoreBytes call to the VerifReg actor for cancelled verified deal, client: %s, dealSize: %v, "+
+				"provider: %v, got code %v", d.Client, d.PieceSize, d.Provider, code)
+		}
+	}
+
+	return nil
+}
+
+//
+// Deal and balance queries
+//
+
+type GetBalanceReturn struct {
+	// Funds held in escrow for the address, including locked funds.
+	Balance abi.TokenAmount
+	// Funds locked for deal payments and collateral, a subset of the escrow balance.
+	Locked abi.TokenAmount
+}
+
+// Returns the escrow and locked balances of a deal client or provider.
+func (a Actor) GetBalance(rt Runtime, address *addr.Address) *GetBalanceReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	nominal, ok := rt.ResolveAddress(*address)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve address %v", *address)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	escrowTable, err := adt.AsBalanceTable(store, st.EscrowTable)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load escrow table")
+	lockedTable, err := adt.AsBalanceTable(store, st.LockedTable)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load locked table")
+
+	balance, err := escrowTable.Get(nominal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get escrow balance for %v", nominal)
+	locked, err := lockedTable.Get(nominal)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get locked balance for %v", nominal)
+
+	return &GetBalanceReturn{
+		Balance: balance,
+		Locked:  locked,
+	}
+}
+
+type EscrowUnlock struct {
+	Epoch  abi.ChainEpoch
+	Amount abi.TokenAmount
+}
+
+type GetEscrowUnlockScheduleParams struct {
+	// Client or provider whose escrow unlocks are returned.
+	Address addr.Address
+	// Lowest ID of the deals to account for, to resume a previous query.
+	StartDealID abi.DealID
+	// Maximum number of deals to account for. Zero, or a limit above GetEscrowUnlockScheduleMax,
+	// means GetEscrowUnlockScheduleMax.
+	Limit uint64
+}
+
+type GetEscrowUnlockScheduleReturn struct {
+	// Expected releases of locked escrow for the deals accounted for, in ascending order of epoch.
+	Unlocks []EscrowUnlock
+	// Whether further deals follow the last one accounted for, to be queried from NextDealID.
+	More       bool
+	NextDealID abi.DealID
+}
+
+// Returns the earliest schedule on which the locked escrow of a deal client or provider may be released
+// by settlement of its deals, for a page of its deals in ascending order of deal ID. The schedules of
+// successive pages are to be merged by the caller.
+// Payment accrued to a provider but not yet settled, and the funds of terminated deals, may be released by
+// settlement at the current epoch. The payment for the remainder of a deal's term is shown at its end epoch,
+// though settlement releases it progressively. Collateral is released at the deal's end epoch.
+// The schedule assumes that deals yet to be activated will be. Storage fees are paid to the provider
+// rather than released to the client, and the provider collateral of a terminated deal is slashed.
+func (a Actor) GetEscrowUnlockSchedule(rt Runtime, params *GetEscrowUnlockScheduleParams) *GetEscrowUnlockScheduleReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	nominal, ok := rt.ResolveAddress(params.Address)
+	if !ok {
+		rt.Abortf(exitcode.ErrIllegalArgument, "failed to resolve address %v", params.Address)
+	}
+	limit := params.Limit
+	if limit == 0 || limit > GetEscrowUnlockScheduleMax {
+		limit = GetEscrowUnlockScheduleMax
+	}
+	currEpoch := rt.CurrEpoch()
+
+	var st State
+	rt.StateReadonly(&st)
+	msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(ReadOnlyPermission).
+		withDealStates(ReadOnlyPermission).withDealsByParty(ReadOnlyPermission).build()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+	var dealIDs []abi.DealID
+	err = msm.dealsByParty.ForEach(nominal, func(dealID abi.DealID) error {
+		if dealID >= params.StartDealID {
+			dealIDs = append(dealIDs, dealID)
+		}
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deals of %v", nominal)
+	sort.Slice(dealIDs, func(i, j int) bool {
+		return dealIDs[i] < dealIDs[j]
+	})
+
+	var ret GetEscrowUnlockScheduleReturn
+	if uint64(len(dealIDs)) > limit {
+		ret.More = true
+		ret.NextDealID = dealIDs[limit]
+		dealIDs = dealIDs[:limit]
+	}
+
+	unlocks := make(map[abi.ChainEpoch]abi.TokenAmount)
+	var epochs []abi.ChainEpoch
+	addUnlock := func(epoch abi.ChainEpoch, amount abi.TokenAmount) {
+		if amount.IsZero() {
+			return
+		}
+		if _, ok := unlocks[epoch]; !ok {
+			unlocks[epoch] = big.Zero()
+			epochs = append(epochs, epoch)
+		}
+		unlocks[epoch] = big.Add(unlocks[epoch], amount)
+	}
+
+	for _, dealID := range dealIDs {
+		deal, err := getDealProposal(msm.dealProposals, dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+		state, found, err := msm.dealStates.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", dealID)
+
+		// The epoch from which payment is outstanding.
+		paidTo := deal.StartEpoch
+		if found && state.LastUpdatedEpoch > paidTo {
+			paidTo = state.LastUpdatedEpoch
+		}
+
+		if found && state.SlashEpoch != EpochUndefined {
+			// Settlement pays the provider up to the slash epoch, and releases the unpaid storage fee
+			// along with the client's collateral.
+			remaining, err := dealGetPaymentRemaining(deal, state.SlashEpoch)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to compute remaining payment for deal %d", dealID)
+			if deal.Client == nominal {
+				addUnlock(currEpoch, big.Add(deal.ClientCollateral, remaining))
+			}
+			if deal.Provider == nominal && state.SlashEpoch > paidTo {
+				addUnlock(currEpoch, big.Mul(big.NewInt(int64(state.SlashEpoch-paidTo)), deal.StoragePricePerEpoch))
+			}
+			continue
+		}
+
+		if deal.Client == nominal {
+			addUnlock(deal.EndEpoch, deal.ClientCollateral)
+		}
+		if deal.Provider == nominal {
+			addUnlock(deal.EndEpoch, deal.ProviderCollateral)
+			// Payment accrued by an activated deal may be settled now, and the remainder by the end epoch.
+			settleable := paidTo
+			if found && currEpoch > paidTo {
+				settleable = currEpoch
+				if settleable > deal.EndEpoch {
+					settleable = deal.EndEpoch
+				}
+				addUnlock(currEpoch, big.Mul(big.NewInt(int64(settleable-paidTo)), deal.StoragePricePerEpoch))
+			}
+			addUnlock(deal.EndEpoch, big.Mul(big.NewInt(int64(deal.EndEpoch-settleable)), deal.StoragePricePerEpoch))
+		}
+	}
+
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+	ret.Unlocks = make([]EscrowUnlock, len(epochs))
+	for i, epoch := range epochs {
+		ret.Unlocks[i] = EscrowUnlock{Epoch: epoch, Amount: unlocks[epoch]}
+	}
+	return &ret
+}
+
+type DealQueryParams struct {
+	DealID abi.DealID
+}
+
+type GetDealDataCommitmentReturn struct {
+	PieceCID  cid.Cid `checked:"true"`
+	PieceSize abi.PaddedPieceSize
+}
+
+// Returns the data commitment and size of a deal's piece.
+func (a Actor) GetDealDataCommitment(rt Runtime, params *DealQueryParams) *GetDealDataCommitmentReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	return &GetDealDataCommitmentReturn{
+		PieceCID:  deal.PieceCID,
+		PieceSize: deal.PieceSize,
+	}
+}
+
+// Returns the client of a deal.
+func (a Actor) GetDealClient(rt Runtime, params *DealQueryParams) *addr.Address {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	return &deal.Client
+}
+
+// Returns the provider of a deal.
+func (a Actor) GetDealProvider(rt Runtime, params *DealQueryParams) *addr.Address {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	return &deal.Provider
+}
+
+// Returns the label of a deal.
+func (a Actor) GetDealLabel(rt Runtime, params *DealQueryParams) *DealLabel {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	return &deal.Label
+}
+
+type GetDealTermReturn struct {
+	StartEpoch abi.ChainEpoch
+	EndEpoch   abi.ChainEpoch
+}
+
+// Returns the epochs at which a deal starts and ends.
+func (a Actor) GetDealTerm(rt Runtime, params *DealQueryParams) *GetDealTermReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	return &GetDealTermReturn{
+		StartEpoch: deal.StartEpoch,
+		EndEpoch:   deal.EndEpoch,
+	}
+}
+
+// Returns the price per epoch paid by a deal's client to its provider.
+func (a Actor) GetDealPricePerEpoch(rt Runtime, params *DealQueryParams) *abi.TokenAmount {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	return &deal.StoragePricePerEpoch
+}
+
+// Returns whether a deal is verified.
+func (a Actor) GetDealVerified(rt Runtime, params *DealQueryParams) *cbg.CborBool {
+	rt.ValidateImmediateCallerAcceptAny()
+	deal := loadDealProposal(rt, params.DealID)
+	verified := cbg.CborBool(deal.VerifiedDeal)
+	return &verified
+}
+
+type GetDealActivationReturn struct {
+	// Epoch at which the deal's sector was activated, or EpochUndefined if not yet activated.
+	Activated abi.ChainEpoch
+	// Epoch at which the deal was terminated, or EpochUndefined if not terminated.
+	Terminated abi.ChainEpoch
+}
+
+// Returns the activation state of a deal.
+func (a Actor) GetDealActivation(rt Runtime, params *DealQueryParams) *GetDealActivationReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+
+	proposals, err := AsDealProposalArray(store, st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	_, err = getDealProposal(proposals, params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", params.DealID)
+
+	states, err := AsDealStateArray(store, st.States)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal states")
+	state, found, err := states.Get(params.DealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", params.DealID)
+	if !found {
+		return &GetDealActivationReturn{
+			Activated:  EpochUndefined,
+			Terminated: EpochUndefined,
+		}
+	}
+	return &GetDealActivationReturn{
+		Activated:  state.SectorStartEpoch,
+		Terminated: state.SlashEpoch,
+	}
+}
+
+type GetDealsForPieceParams struct {
+	PieceCID cid.Cid `checked:"true"` // CommP
+	// Lowest ID of the deals to return, to resume a previous query.
+	StartDealID abi.DealID
+	// Maximum number of deals to return. Zero, or a limit above GetDealsForPieceMax, means GetDealsForPieceMax.
+	Limit uint64
+}
+
+type PieceDeal struct {
+	DealID   abi.DealID
+	Provider addr.Address
+	// Epoch at which the deal's sector was activated, or EpochUndefined if not yet activated.
+	Activated abi.ChainEpoch
+}
+
+type GetDealsForPieceReturn struct {
+	// Deals storing the piece which have not been terminated, from StartDealID in ascending order of deal ID.
+	Deals []PieceDeal
+	// Whether further deals follow the last one returned, to be queried from the next deal ID.
+	More bool
+}
+
+// Returns a page of the deals storing a piece, with their providers.
+// The piece's deals are read from StartDealID until the page is full, and one beyond to determine whether more follow.
+// Fails if the piece index is not enabled.
+func (a Actor) GetDealsForPiece(rt Runtime, params *GetDealsForPieceParams) *GetDealsForPieceReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	if !PieceDealIndexEnabled {
+		rt.Abortf(exitcode.ErrForbidden, "deals are not indexed by piece")
+	}
+	limit := params.Limit
+	if limit == 0 || limit > GetDealsForPieceMax {
+		limit = GetDealsForPieceMax
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	msm, err := st.mutator(adt.AsStore(rt)).withDealProposals(ReadOnlyPermission).
+		withDealStates(ReadOnlyPermission).withDealsByPiece(ReadOnlyPermission).build()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load state")
+
+	ret := GetDealsForPieceReturn{Deals: []PieceDeal{}}
+	var dealIDs []abi.DealID
+	stopErr := xerrors.New("stop")
+	err = msm.dealsByPiece.ForEachFrom(params.PieceCID, params.StartDealID, func(dealID abi.DealID) error {
+		if uint64(len(dealIDs)) == limit {
+			ret.More = true
+			return stopErr
+		}
+		dealIDs = append(dealIDs, dealID)
+		return nil
+	})
+	if err != stopErr {
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deals for piece %v", params.PieceCID)
+	}
+	for _, dealID := range dealIDs {
+		deal, err := getDealProposal(msm.dealProposals, dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+		activated := EpochUndefined
+		state, found, err := msm.dealStates.Get(dealID)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal state %d", dealID)
+		if found {
+			activated = state.SectorStartEpoch
+		}
+		ret.Deals = append(ret.Deals, PieceDeal{DealID: dealID, Provider: deal.Provider, Activated: activated})
+	}
+	return &ret
+}
+
+// Computes the epoch of the single operation scheduled for an activated deal, given the last epoch processed by cron.
+// A deal is first processed at its start, then at its expiration, unless it is slashed first,
+// in which case it is processed at the next opportunity.
+func scheduledDealOpEpoch(dealID abi.DealID, deal *DealProposal, state *DealState, lastCron abi.ChainEpoch) abi.ChainEpoch {
+	startOp := GenRandNextEpoch(deal.StartEpoch, dealID)
+	nextEpoch := lastCron + 1
+	if deal.StartEpoch > nextEpoch {
+		nextEpoch = deal.StartEpoch
+	}
+	nextOp := GenRandNextEpoch(nextEpoch, dealID)
+	if state.SlashEpoch != EpochUndefined || nextOp == startOp {
+		return nextOp
+	}
+	return GenRandNextEpoch(deal.EndEpoch, dealID)
+}
+
 func GenRandNextEpoch(startEpoch abi.ChainEpoch, dealID abi.DealID) abi.ChainEpoch {
 	offset := abi.ChainEpoch(uint64(dealID) % uint64(DealUpdatesInterval))
 	q := builtin.NewQuantSpec(DealUpdatesInterval, 0)
@@ -792,11 +1882,8 @@ func validateDealCanActivate(proposal *DealProposal, minerAddr addr.Address, sec
 	return nil
 }
 
+// Validates a deal proposal's parameters. The client's signature is verified separately.
 func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQAPower, baselinePower abi.StoragePower) error {
-	if err := dealProposalIsInternallyValid(rt, deal); err != nil {
-		return xerrors.Errorf("Invalid deal proposal %w", err)
-	}
-
 	proposal := deal.Proposal
 
 	if proposal.Label.Length() > DealMaxLabelSize {
@@ -823,12 +1910,13 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 		return xerrors.Errorf("Deal start epoch has already elapsed")
 	}
 
-	minDuration, maxDuration := DealDurationBounds(proposal.PieceSize)
+	nv := rt.NetworkVersion()
+	minDuration, maxDuration := dealDurationBounds(nv, proposal.PieceSize)
 	if proposal.Duration() < minDuration || proposal.Duration() > maxDuration {
 		return xerrors.Errorf("Deal duration out of bounds")
 	}
 
-	minPrice, maxPrice := DealPricePerEpochBounds(proposal.PieceSize, proposal.Duration())
+	minPrice, maxPrice := dealPricePerEpochBounds(nv, proposal.PieceSize, proposal.Duration())
 	if proposal.StoragePricePerEpoch.LessThan(minPrice) || proposal.StoragePricePerEpoch.GreaterThan(maxPrice) {
 		return xerrors.Errorf("Storage price out of bounds")
 	}
@@ -839,7 +1927,7 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 		return xerrors.Errorf("Provider collateral out of bounds")
 	}
 
-	minClientCollateral, maxClientCollateral := DealClientCollateralBounds(proposal.PieceSize, proposal.Duration())
+	minClientCollateral, maxClientCollateral := dealClientCollateralBounds(nv, proposal.PieceSize, proposal.Duration(), proposal.StoragePricePerEpoch)
 	if proposal.ClientCollateral.LessThan(minClientCollateral) || proposal.ClientCollateral.GreaterThan(maxClientCollateral) {
 		return xerrors.Errorf("Client collateral out of bounds")
 	}
@@ -852,6 +1940,23 @@ func validateDeal(rt Runtime, deal ClientDealProposal, networkRawPower, networkQ
 
 // Resolves a provider or client address to the canonical form against which a balance should be held, and
 // the designated recipient address of withdrawals (which is the same, for simple account parties).
+// Resolves an address to an ID address and verifies that it is the address of an account actor,
+// which cannot fail to receive a plain value transfer.
+func resolveAutoWithdrawRecipient(rt Runtime, raw addr.Address) addr.Address {
+	resolved, ok := rt.ResolveAddress(raw)
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "unable to resolve auto-withdraw recipient %v", raw)
+	}
+	code, ok := rt.GetActorCodeCID(resolved)
+	if !ok {
+		rt.Abortf(exitcode.ErrNotFound, "no code for auto-withdraw recipient %v", resolved)
+	}
+	if code != builtin.AccountActorCodeID {
+		rt.Abortf(exitcode.ErrIllegalArgument, "auto-withdraw recipient must be an account, was %v", code)
+	}
+	return resolved
+}
+
 func escrowAddress(rt Runtime, address addr.Address) (nominal addr.Address, recipient addr.Address, approved []addr.Address) {
 	// Resolve the provided address to the canonical form against which the balance is held.
 	nominal, ok := rt.ResolveAddress(address)
@@ -873,6 +1978,73 @@ func escrowAddress(rt Runtime, address addr.Address) (nominal addr.Address, reci
 	return nominal, nominal, []addr.Address{nominal}
 }
 
+// Returns the requests to claim the verified registry allocations made for activated deals when they were
+// published.
+// Verified deals published before allocations drew on their clients' DataCap directly, so claim no allocations.
+func (m *marketStateMutation) allocationClaims(dealIDs []abi.DealID) ([]verifreg.ClaimAllocationRequest, error) {
+	var claims []verifreg.ClaimAllocationRequest
+	for _, dealID := range dealIDs {
+		deal, err := getDealProposal(m.dealProposals, dealID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get dealId %d: %w", dealID, err)
+		}
+		if !deal.VerifiedDeal {
+			continue
+		}
+		allocationID, found, err := m.dealAllocationID(dealID)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+
+		claims = append(claims, verifreg.ClaimAllocationRequest{
+			AllocationID: allocationID,
+			Client:       deal.Client,
+			Provider:     deal.Provider,
+			Data:         deal.PieceCID,
+			Size:         deal.PieceSize,
+			TermEnd:      deal.EndEpoch,
+			DealID:       dealID,
+		})
+	}
+	return claims, nil
+}
+
+// Claims the verified registry allocations made for a sector's activated verified deals.
+// A verified deal earns its verified weight by claiming its allocation, so the claims succeed together or not at all.
+func claimAllocations(rt Runtime, claims []verifreg.ClaimAllocationRequest) exitcode.ExitCode {
+	if len(claims) == 0 {
+		return exitcode.Ok
+	}
+
+	return rt.Send(
+		builtin.VerifiedRegistryActorAddr,
+		builtin.MethodsVerifiedRegistry.ClaimAllocations,
+		&verifreg.ClaimAllocationsParams{Claims: claims, AllOrNothing: true},
+		abi.NewTokenAmount(0),
+		&builtin.Discard{},
+	)
+}
+
+// Returns the pieces of a sector's deals, in order.
+func sectorPieces(proposals *DealArray, dealIDs []abi.DealID) ([]abi.PieceInfo, error) {
+	pieces := make([]abi.PieceInfo, 0, len(dealIDs))
+	for _, dealID := range dealIDs {
+		deal, err := getDealProposal(proposals, dealID)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to get dealId %d: %w", dealID, err)
+		}
+
+		pieces = append(pieces, abi.PieceInfo{
+			PieceCID: deal.PieceCID,
+			Size:     deal.PieceSize,
+		})
+	}
+	return pieces, nil
+}
+
 func getDealProposal(proposals *DealArray, dealID abi.DealID) (*DealProposal, error) {
 	proposal, found, err := proposals.Get(dealID)
 	if err != nil {
@@ -885,6 +2057,17 @@ func getDealProposal(proposals *DealArray, dealID abi.DealID) (*DealProposal, er
 	return proposal, nil
 }
 
+// Loads a deal proposal, aborting with ErrNotFound if there is no such deal.
+func loadDealProposal(rt Runtime, dealID abi.DealID) *DealProposal {
+	var st State
+	rt.StateReadonly(&st)
+	proposals, err := AsDealProposalArray(adt.AsStore(rt), st.Proposals)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deal proposals")
+	deal, err := getDealProposal(proposals, dealID)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get deal %d", dealID)
+	return deal
+}
+
 // Requests the current epoch target block reward from the reward actor.
 func requestCurrentBaselinePower(rt Runtime) abi.StoragePower {
 	var ret reward.ThisEpochRewardReturn
@@ -111,3 +111,87 @@ func (m *marketStateMutation) balanceCovered(addr addr.Address, amountToLock abi
 	}
 	return big.Add(prevLocked, amountToLock).LessThanEqual(escrowBalance), nil
 }
+
+// Returns the portion of an address's escrow balance which is not locked.
+func (m *marketStateMutation) availableBalance(addr addr.Address) (abi.TokenAmount, error) {
+	locked, err := m.lockedTable.Get(addr)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to get locked balance: %w", err)
+	}
+	escrowBalance, err := m.escrowTable.Get(addr)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to get escrow balance: %w", err)
+	}
+	return big.Sub(escrowBalance, locked), nil
+}
+
+type escrowWithdrawal struct {
+	party     addr.Address
+	recipient addr.Address
+	amount    abi.TokenAmount
+}
+
+// Tracks the escrow released to deal parties which have designated an auto-withdrawal recipient,
+// so that it can be withdrawn once deals are settled.
+type releasedEscrowTracker struct {
+	m               *marketStateMutation
+	seen            map[addr.Address]struct{}
+	parties         []addr.Address // Parties with a recipient, in the order first tracked
+	recipients      map[addr.Address]addr.Address
+	availableBefore map[addr.Address]abi.TokenAmount
+}
+
+func (m *marketStateMutation) trackReleasedEscrow() *releasedEscrowTracker {
+	return &releasedEscrowTracker{
+		m:               m,
+		seen:            make(map[addr.Address]struct{}),
+		recipients:      make(map[addr.Address]addr.Address),
+		availableBefore: make(map[addr.Address]abi.TokenAmount),
+	}
+}
+
+// Records a party's available balance, if the party has a recipient and is not already tracked.
+func (t *releasedEscrowTracker) track(party addr.Address) error {
+	if _, ok := t.seen[party]; ok {
+		return nil
+	}
+	t.seen[party] = struct{}{}
+
+	var recipient addr.Address
+	found, err := t.m.autoWithdrawRecipients.Get(abi.AddrKey(party), &recipient)
+	if err != nil {
+		return xerrors.Errorf("failed to get auto-withdraw recipient for %v: %w", party, err)
+	}
+	if !found {
+		return nil
+	}
+	available, err := t.m.availableBalance(party)
+	if err != nil {
+		return err
+	}
+	t.parties = append(t.parties, party)
+	t.recipients[party] = recipient
+	t.availableBefore[party] = available
+	return nil
+}
+
+// Removes from escrow the balance released to each tracked party since it was tracked,
+// returning the withdrawals to be sent.
+func (t *releasedEscrowTracker) withdraw() ([]escrowWithdrawal, error) {
+	var withdrawals []escrowWithdrawal
+	for _, party := range t.parties {
+		available, err := t.m.availableBalance(party)
+		if err != nil {
+			return nil, err
+		}
+		released := big.Sub(available, t.availableBefore[party])
+		if !released.GreaterThan(big.Zero()) {
+			continue
+		}
+		if err := t.m.escrowTable.MustSubtract(party, released); err != nil {
+			return nil, xerrors.Errorf("failed to withdraw released escrow for %v: %w", party, err)
+		}
+		withdrawals = append(withdrawals, escrowWithdrawal{party: party, recipient: t.recipients[party], amount: released})
+	}
+	return withdrawals, nil
+}
@@ -3,13 +3,16 @@ package market
 import (
 	"bytes"
 
+	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
 
@@ -54,6 +57,30 @@ type State struct {
 	DealOpsByEpoch cid.Cid // SetMultimap, HAMT[epoch]Set
 	LastCron       abi.ChainEpoch
 
+	// Index of the deals storing each piece, from publication until termination or removal.
+	// Empty unless PieceDealIndexEnabled.
+	DealsByPiece cid.Cid // PieceDealIndex, HAMT[PieceCID]AMT[DealID]
+
+	// Index of the deals of each client and provider, from publication until the proposal is removed.
+	DealsByParty cid.Cid // PartyDealIndex, HAMT[address]Set[DealID]
+
+	// Recipients to which escrow released by deal settlement is automatically withdrawn,
+	// indexed by client or provider address.
+	AutoWithdrawRecipients cid.Cid // HAMT[address]address
+
+	// Deals included in a sector pre-commitment and not yet activated. The client may no longer
+	// cancel these deals, since the provider has committed to sealing them.
+	// Invariant: PreCommittedDeals ⊆ keys(Proposals) \ keys(States).
+	PreCommittedDeals cid.Cid // Set[DealID]
+
+	// Verified registry allocations of datacap made for verified deals when they were published.
+	// An allocation is claimed by the deal's activation, after which its datacap is held by the claim.
+	// An allocation of a deal which is cancelled or times out expires at the deal's start epoch, after which
+	// its client may reclaim the datacap from the verified registry.
+	// Verified deals published before allocations drew on their clients' datacap directly, and have no entry.
+	// Invariant: keys(DealAllocationIds) ⊆ keys(Proposals).
+	DealAllocationIds cid.Cid // HAMT[DealID]AllocationID
+
 	// Total Client Collateral that is locked -> unlocked when deal is terminated
 	TotalClientLockedCollateral abi.TokenAmount
 	// Total Provider Collateral that is locked -> unlocked when deal is terminated
@@ -80,6 +107,26 @@ func ConstructState(store adt.Store) (*State, error) {
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create empty multiset: %w", err)
 	}
+	emptyDealsByPieceHamtCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty piece index: %w", err)
+	}
+	emptyDealsByPartyHamtCid, err := StoreEmptySetMultimap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty party index: %w", err)
+	}
+	emptyAutoWithdrawMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty map: %w", err)
+	}
+	emptyPreCommittedDealsSetCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty set: %w", err)
+	}
+	emptyDealAllocationsMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty map: %w", err)
+	}
 	emptyBalanceTableCid, err := adt.StoreEmptyMap(store, adt.BalanceTableBitwidth)
 	if err != nil {
 		return nil, xerrors.Errorf("failed to create empty balance table: %w", err)
@@ -94,6 +141,12 @@ func ConstructState(store adt.Store) (*State, error) {
 		NextID:           abi.DealID(0),
 		DealOpsByEpoch:   emptyDealOpsHamtCid,
 		LastCron:         abi.ChainEpoch(-1),
+		DealsByPiece:     emptyDealsByPieceHamtCid,
+		DealsByParty:     emptyDealsByPartyHamtCid,
+
+		AutoWithdrawRecipients: emptyAutoWithdrawMapCid,
+		PreCommittedDeals:      emptyPreCommittedDealsSetCid,
+		DealAllocationIds:      emptyDealAllocationsMapCid,
 
 		TotalClientLockedCollateral:   abi.NewTokenAmount(0),
 		TotalProviderLockedCollateral: abi.NewTokenAmount(0),
@@ -105,8 +158,12 @@ func ConstructState(store adt.Store) (*State, error) {
 // Deal state operations
 ////////////////////////////////////////////////////////////////////////////////
 
-func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) (amountSlashed abi.TokenAmount, nextEpoch abi.ChainEpoch, removeDeal bool) {
+// Settles payment for an activated deal up to the given epoch, and processes its slashing or expiry if due.
+// Returns the amount of provider collateral slashed, the payment transferred from client to provider,
+// and whether the deal is complete and should be removed from state.
+func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealState, deal *DealProposal, epoch abi.ChainEpoch) (amountSlashed abi.TokenAmount, payment abi.TokenAmount, removeDeal bool) {
 	amountSlashed = abi.NewTokenAmount(0)
+	payment = abi.NewTokenAmount(0)
 
 	everUpdated := state.LastUpdatedEpoch != EpochUndefined
 	everSlashed := state.SlashEpoch != EpochUndefined
@@ -116,7 +173,7 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 	// This would be the case that the first callback somehow triggers before it is scheduled to
 	// This is expected not to be able to happen
 	if deal.StartEpoch > epoch {
-		return amountSlashed, EpochUndefined, false
+		return amountSlashed, payment, false
 	}
 
 	paymentEndEpoch := deal.EndEpoch
@@ -144,6 +201,7 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 			err := m.transferBalance(deal.Client, deal.Provider, totalPayment)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to transfer %v from %v to %v",
 				totalPayment, deal.Client, deal.Provider)
+			payment = totalPayment
 		}
 	}
 
@@ -164,19 +222,15 @@ func (m *marketStateMutation) updatePendingDealState(rt Runtime, state *DealStat
 		amountSlashed = deal.ProviderCollateral
 		err = m.slashBalance(deal.Provider, amountSlashed, ProviderCollateral)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "slashing balance")
-		return amountSlashed, EpochUndefined, true
+		return amountSlashed, payment, true
 	}
 
 	if epoch >= deal.EndEpoch {
 		m.processDealExpired(rt, deal, state)
-		return amountSlashed, EpochUndefined, true
+		return amountSlashed, payment, true
 	}
 
-	// We're explicitly not inspecting the end epoch and may process a deal's expiration late, in order to prevent an outsider
-	// from loading a cron tick by activating too many deals with the same end epoch.
-	nextEpoch = epoch + DealUpdatesInterval
-
-	return amountSlashed, nextEpoch, false
+	return amountSlashed, payment, false
 }
 
 // Deal start deadline elapsed without appearing in a proven sector.
@@ -204,6 +258,30 @@ func (m *marketStateMutation) processDealInitTimedOut(rt Runtime, deal *DealProp
 	return amountSlashed
 }
 
+// Deal cancelled by its client before activation.
+// Unlock the client's storage fee and collateral and the provider's collateral, and pay the
+// client's cancellation penalty to the provider.
+func (m *marketStateMutation) processDealCancelled(rt Runtime, deal *DealProposal) abi.TokenAmount {
+	if err := m.unlockBalance(deal.Client, deal.TotalStorageFee(), ClientStorageFee); err != nil {
+		rt.Abortf(exitcode.ErrIllegalState, "failure unlocking client storage fee: %s", err)
+	}
+	if err := m.unlockBalance(deal.Client, deal.ClientCollateral, ClientCollateral); err != nil {
+		rt.Abortf(exitcode.ErrIllegalState, "failure unlocking client collateral: %s", err)
+	}
+	if err := m.unlockBalance(deal.Provider, deal.ProviderCollateral, ProviderCollateral); err != nil {
+		rt.Abortf(exitcode.ErrIllegalState, "failure unlocking provider collateral: %s", err)
+	}
+
+	penalty := DealCancellationPenalty(deal.TotalStorageFee(), deal.ClientCollateral, deal.ProviderCollateral)
+	if err := m.escrowTable.MustSubtract(deal.Client, penalty); err != nil {
+		rt.Abortf(exitcode.ErrIllegalState, "failed to deduct cancellation penalty from client: %s", err)
+	}
+	if err := m.escrowTable.Add(deal.Provider, penalty); err != nil {
+		rt.Abortf(exitcode.ErrIllegalState, "failed to pay cancellation penalty to provider: %s", err)
+	}
+	return penalty
+}
+
 // Normal expiration. Unlock collaterals for both provider and client.
 func (m *marketStateMutation) processDealExpired(rt Runtime, deal *DealProposal, state *DealState) {
 	builtin.RequireState(rt, state.SectorStartEpoch != EpochUndefined, "sector start epoch undefined")
@@ -222,6 +300,86 @@ func (m *marketStateMutation) generateStorageDealID() abi.DealID {
 	return ret
 }
 
+// Validates that a sector's deals may all be activated at currEpoch, and returns their combined weights.
+// Deals must be published and pending, and not yet included in any sector.
+func (m *marketStateMutation) validateSectorDealsActivation(dealIDs []abi.DealID, minerAddr addr.Address,
+	sectorExpiry, currEpoch abi.ChainEpoch) (SectorWeights, error) {
+	dealWeight, verifiedWeight, dealSpace, err := validateAndComputeDealWeight(m.dealProposals, dealIDs, minerAddr, sectorExpiry, currEpoch)
+	if err != nil {
+		return SectorWeights{}, err
+	}
+
+	for _, dealID := range dealIDs {
+		_, found, err := m.dealStates.Get(dealID)
+		if err != nil {
+			return SectorWeights{}, xerrors.Errorf("failed to get state for dealId %d: %w", dealID, err)
+		}
+		if found {
+			return SectorWeights{}, exitcode.ErrIllegalArgument.Wrapf("deal %d already included in another sector", dealID)
+		}
+
+		proposal, err := getDealProposal(m.dealProposals, dealID)
+		if err != nil {
+			return SectorWeights{}, xerrors.Errorf("failed to get dealId %d: %w", dealID, err)
+		}
+		propc, err := proposal.Cid()
+		if err != nil {
+			return SectorWeights{}, xerrors.Errorf("failed to calculate proposal CID: %w", err)
+		}
+		has, err := m.pendingDeals.Has(abi.CidKey(propc))
+		if err != nil {
+			return SectorWeights{}, xerrors.Errorf("failed to get pending proposal %v: %w", propc, err)
+		}
+		if !has {
+			return SectorWeights{}, exitcode.ErrIllegalState.Wrapf("tried to activate deal that was not in the pending set (%s)", propc)
+		}
+	}
+
+	return SectorWeights{
+		DealSpace:          dealSpace,
+		DealWeight:         dealWeight,
+		VerifiedDealWeight: verifiedWeight,
+	}, nil
+}
+
+// Records deals as activated in a sector at currEpoch.
+func (m *marketStateMutation) recordDealsActivated(dealIDs []abi.DealID, currEpoch abi.ChainEpoch) error {
+	for _, dealID := range dealIDs {
+		err := m.dealStates.Set(dealID, &DealState{
+			SectorStartEpoch: currEpoch,
+			LastUpdatedEpoch: EpochUndefined,
+			SlashEpoch:       EpochUndefined,
+		})
+		if err != nil {
+			return xerrors.Errorf("failed to set deal state %d: %w", dealID, err)
+		}
+		if _, err := m.preCommittedDeals.TryDelete(abi.UIntKey(uint64(dealID))); err != nil {
+			return xerrors.Errorf("failed to remove pre-committed deal %d: %w", dealID, err)
+		}
+	}
+	return nil
+}
+
+// Returns the verified registry allocation made for a deal when it was published, whether or not claimed.
+func (m *marketStateMutation) dealAllocationID(dealID abi.DealID) (verifreg.AllocationID, bool, error) {
+	var id cbg.CborInt
+	found, err := m.dealAllocationIds.Get(abi.UIntKey(uint64(dealID)), &id)
+	if err != nil {
+		return 0, false, xerrors.Errorf("failed to get allocation of deal %d: %w", dealID, err)
+	}
+	return verifreg.AllocationID(id), found, nil
+}
+
+// Records deals as included in a sector pre-commitment, after which the client may no longer cancel them.
+func (m *marketStateMutation) recordDealsPreCommitted(dealIDs []abi.DealID) error {
+	for _, dealID := range dealIDs {
+		if err := m.preCommittedDeals.Put(abi.UIntKey(uint64(dealID))); err != nil {
+			return xerrors.Errorf("failed to record pre-committed deal %d: %w", dealID, err)
+		}
+	}
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // State utility functions
 ////////////////////////////////////////////////////////////////////////////////
@@ -241,6 +399,17 @@ func dealProposalIsInternallyValid(rt Runtime, proposal ClientDealProposal) erro
 	return nil
 }
 
+func dealExtensionIsSigned(rt Runtime, extension ClientDealExtension, client addr.Address) error {
+	buf := bytes.Buffer{}
+	if err := extension.Extension.MarshalCBOR(&buf); err != nil {
+		return xerrors.Errorf("extension signature verification failed to marshal extension: %w", err)
+	}
+	if err := rt.VerifySignature(extension.ClientSignature, client, buf.Bytes()); err != nil {
+		return xerrors.Errorf("extension signature invalid: %w", err)
+	}
+	return nil
+}
+
 func dealGetPaymentRemaining(deal *DealProposal, slashEpoch abi.ChainEpoch) (abi.TokenAmount, error) {
 	if slashEpoch > deal.EndEpoch {
 		return big.Zero(), xerrors.Errorf("deal slash epoch %d after end epoch %d", slashEpoch, deal.EndEpoch)
@@ -259,6 +428,29 @@ func dealGetPaymentRemaining(deal *DealProposal, slashEpoch abi.ChainEpoch) (abi
 	return big.Mul(big.NewInt(int64(durationRemaining)), deal.StoragePricePerEpoch), nil
 }
 
+// Deletes a deal's proposal and removes the deal from the piece and party indexes, pre-committed deals
+// and allocations.
+func (m *marketStateMutation) deleteDealProposal(dealID abi.DealID, deal *DealProposal) error {
+	if err := m.dealProposals.Delete(dealID); err != nil {
+		return xerrors.Errorf("failed to delete deal proposal %d: %w", dealID, err)
+	}
+	if PieceDealIndexEnabled {
+		if err := m.dealsByPiece.Remove(deal.PieceCID, dealID); err != nil {
+			return xerrors.Errorf("failed to unindex deal %d: %w", dealID, err)
+		}
+	}
+	if err := m.dealsByParty.Remove(deal, dealID); err != nil {
+		return xerrors.Errorf("failed to unindex deal %d by party: %w", dealID, err)
+	}
+	if _, err := m.preCommittedDeals.TryDelete(abi.UIntKey(uint64(dealID))); err != nil {
+		return xerrors.Errorf("failed to remove pre-committed deal %d: %w", dealID, err)
+	}
+	if _, err := m.dealAllocationIds.TryDelete(abi.UIntKey(uint64(dealID))); err != nil {
+		return xerrors.Errorf("failed to remove allocation of deal %d: %w", dealID, err)
+	}
+	return nil
+}
+
 // MarketStateMutationPermission is the mutation permission on a state field
 type MarketStateMutationPermission int
 
@@ -290,6 +482,21 @@ type marketStateMutation struct {
 	dpePermit    MarketStateMutationPermission
 	dealsByEpoch *SetMultimap
 
+	dbpPermit    MarketStateMutationPermission
+	dealsByPiece *PieceDealIndex
+
+	partyPermit  MarketStateMutationPermission
+	dealsByParty *PartyDealIndex
+
+	awPermit               MarketStateMutationPermission
+	autoWithdrawRecipients *adt.Map
+
+	pcdPermit         MarketStateMutationPermission
+	preCommittedDeals *adt.Set
+
+	pdaPermit         MarketStateMutationPermission
+	dealAllocationIds *adt.Map
+
 	lockedPermit                  MarketStateMutationPermission
 	lockedTable                   *adt.BalanceTable
 	totalClientLockedCollateral   abi.TokenAmount
@@ -355,6 +562,46 @@ func (m *marketStateMutation) build() (*marketStateMutation, error) {
 		m.dealsByEpoch = dbe
 	}
 
+	if m.dbpPermit != Invalid {
+		dbp, err := AsPieceDealIndex(m.store, m.st.DealsByPiece)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deals by piece: %w", err)
+		}
+		m.dealsByPiece = dbp
+	}
+
+	if m.partyPermit != Invalid {
+		dbp, err := AsPartyDealIndex(m.store, m.st.DealsByParty)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load deals by party: %w", err)
+		}
+		m.dealsByParty = dbp
+	}
+
+	if m.awPermit != Invalid {
+		aw, err := adt.AsMap(m.store, m.st.AutoWithdrawRecipients, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load auto-withdraw recipients: %w", err)
+		}
+		m.autoWithdrawRecipients = aw
+	}
+
+	if m.pcdPermit != Invalid {
+		pcd, err := adt.AsSet(m.store, m.st.PreCommittedDeals, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load pre-committed deals: %w", err)
+		}
+		m.preCommittedDeals = pcd
+	}
+
+	if m.pdaPermit != Invalid {
+		pda, err := adt.AsMap(m.store, m.st.DealAllocationIds, builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to load pending deal allocations: %w", err)
+		}
+		m.dealAllocationIds = pda
+	}
+
 	m.nextDealId = m.st.NextID
 
 	return m, nil
@@ -390,6 +637,31 @@ func (m *marketStateMutation) withDealsByEpoch(permit MarketStateMutationPermiss
 	return m
 }
 
+func (m *marketStateMutation) withDealsByPiece(permit MarketStateMutationPermission) *marketStateMutation {
+	m.dbpPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withDealsByParty(permit MarketStateMutationPermission) *marketStateMutation {
+	m.partyPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withAutoWithdrawRecipients(permit MarketStateMutationPermission) *marketStateMutation {
+	m.awPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withPreCommittedDeals(permit MarketStateMutationPermission) *marketStateMutation {
+	m.pcdPermit = permit
+	return m
+}
+
+func (m *marketStateMutation) withDealAllocationIds(permit MarketStateMutationPermission) *marketStateMutation {
+	m.pdaPermit = permit
+	return m
+}
+
 func (m *marketStateMutation) commitState() error {
 	var err error
 	if m.proposalPermit == WritePermission {
@@ -431,6 +703,36 @@ func (m *marketStateMutation) commitState() error {
 		}
 	}
 
+	if m.dbpPermit == WritePermission {
+		if m.st.DealsByPiece, err = m.dealsByPiece.Root(); err != nil {
+			return xerrors.Errorf("failed to flush deals by piece: %w", err)
+		}
+	}
+
+	if m.partyPermit == WritePermission {
+		if m.st.DealsByParty, err = m.dealsByParty.Root(); err != nil {
+			return xerrors.Errorf("failed to flush deals by party: %w", err)
+		}
+	}
+
+	if m.awPermit == WritePermission {
+		if m.st.AutoWithdrawRecipients, err = m.autoWithdrawRecipients.Root(); err != nil {
+			return xerrors.Errorf("failed to flush auto-withdraw recipients: %w", err)
+		}
+	}
+
+	if m.pcdPermit == WritePermission {
+		if m.st.PreCommittedDeals, err = m.preCommittedDeals.Root(); err != nil {
+			return xerrors.Errorf("failed to flush pre-committed deals: %w", err)
+		}
+	}
+
+	if m.pdaPermit == WritePermission {
+		if m.st.DealAllocationIds, err = m.dealAllocationIds.Root(); err != nil {
+			return xerrors.Errorf("failed to flush pending deal allocations: %w", err)
+		}
+	}
+
 	m.st.NextID = m.nextDealId
 	return nil
 }
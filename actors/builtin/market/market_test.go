@@ -9,12 +9,15 @@ import (
 	"testing"
 
 	address "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/go-state-types/network"
 	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
@@ -57,6 +60,36 @@ func TestRemoveAllError(t *testing.T) {
 	}
 }
 
+func TestSetMultimapRemove(t *testing.T) {
+	marketActor := tutil.NewIDAddr(t, 100)
+	builder := mock.NewBuilder(marketActor)
+	rt := builder.Build(t)
+	store := adt.AsStore(rt)
+
+	smm, err := market.MakeEmptySetMultimap(store, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	emptyRoot, err := smm.Root()
+	require.NoError(t, err)
+
+	// removing from a missing key is a no-op
+	require.NoError(t, smm.Remove(42, 1))
+
+	require.NoError(t, smm.PutMany(42, []abi.DealID{1, 2}))
+	require.NoError(t, smm.Remove(42, 1))
+	var ids []abi.DealID
+	require.NoError(t, smm.ForEach(42, func(id abi.DealID) error {
+		ids = append(ids, id)
+		return nil
+	}))
+	assert.Equal(t, []abi.DealID{2}, ids)
+
+	// removing the last value removes the key
+	require.NoError(t, smm.Remove(42, 2))
+	root, err := smm.Root()
+	require.NoError(t, err)
+	assert.Equal(t, emptyRoot, root)
+}
+
 func TestMarketActor(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -740,6 +773,37 @@ func TestPublishStorageDeals(t *testing.T) {
 		require.EqualValues(t, totalStorageFee, st.TotalClientStorageFee)
 		actor.checkState(rt)
 	})
+
+	t.Run("tiny piece deal may be shorter than the min duration from network version 17", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetNetworkVersion(network.Version17)
+		endEpoch := startEpoch + market.DealMinDurationTinyPiece
+
+		deal := generateDealProposal(client, provider, startEpoch, endEpoch)
+		require.True(t, deal.PieceSize < market.DealMinPricedPieceSize)
+		deal.StoragePricePerEpoch, _ = market.DealPricePerEpochBoundsV17(deal.PieceSize, deal.Duration())
+		deal.ClientCollateral, _ = market.DealClientCollateralBoundsV17(deal.PieceSize, deal.Duration(), deal.StoragePricePerEpoch)
+		actor.addProviderFunds(rt, deal.ProviderCollateral, mAddr)
+		actor.addParticipantFunds(rt, client, deal.ClientBalanceRequirement())
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		actor.publishDeals(rt, mAddr, publishDealReq{deal: deal})
+		actor.checkState(rt)
+	})
+
+	t.Run("verified deal claims a client allocation from network version 17", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetNetworkVersion(network.Version17)
+
+		deal := actor.generateVerifiedDealV17AndAddFunds(rt, client, mAddr, startEpoch, endEpoch)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealID := actor.publishDeals(rt, mAddr, publishDealReq{deal: deal, clientAllocationID: 7})[0]
+
+		// The deal records the ID to which the verified registry transferred the client's allocation.
+		allocationID, found := actor.dealAllocation(rt, dealID)
+		require.True(t, found)
+		assert.Equal(t, actor.lastAllocationID, allocationID)
+		actor.checkState(rt)
+	})
 }
 
 func TestPublishStorageDealsFailures(t *testing.T) {
@@ -892,6 +956,38 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 				},
 				exitCode: exitcode.ErrIllegalArgument,
 			},
+			"tiny deal total price below minimum from network version 17": {
+				setup: func(rt *mock.Runtime, _ *marketActorTestHarness, d *market.DealProposal) {
+					rt.SetNetworkVersion(network.Version17)
+					d.StoragePricePerEpoch = big.Sub(big.Div(market.DealMinTotalPrice, big.NewInt(int64(d.Duration()))), big.NewInt(1))
+					d.ClientCollateral = market.DealMinTotalPrice
+				},
+				exitCode: exitcode.ErrIllegalArgument,
+			},
+			"client collateral less than share of storage fee from network version 17": {
+				setup: func(rt *mock.Runtime, _ *marketActorTestHarness, d *market.DealProposal) {
+					rt.SetNetworkVersion(network.Version17)
+					minCollateral, _ := market.DealClientCollateralBoundsV17(d.PieceSize, d.Duration(), d.StoragePricePerEpoch)
+					d.ClientCollateral = big.Sub(minCollateral, big.NewInt(1))
+				},
+				exitCode: exitcode.ErrIllegalArgument,
+			},
+			"deal duration at the previous max deal duration from network version 17": {
+				setup: func(rt *mock.Runtime, _ *marketActorTestHarness, d *market.DealProposal) {
+					rt.SetNetworkVersion(network.Version17)
+					d.EndEpoch = d.StartEpoch + market.DealMaxDuration
+					d.ClientCollateral, _ = market.DealClientCollateralBoundsV17(d.PieceSize, d.Duration(), d.StoragePricePerEpoch)
+				},
+				exitCode: exitcode.ErrIllegalArgument,
+			},
+			"deal duration greater than max deal duration from network version 17": {
+				setup: func(rt *mock.Runtime, _ *marketActorTestHarness, d *market.DealProposal) {
+					rt.SetNetworkVersion(network.Version17)
+					d.EndEpoch = d.StartEpoch + market.DealMaxDurationV17 + 1
+					d.ClientCollateral, _ = market.DealClientCollateralBoundsV17(d.PieceSize, d.Duration(), d.StoragePricePerEpoch)
+				},
+				exitCode: exitcode.ErrIllegalArgument,
+			},
 		}
 
 		for name, tc := range tcs {
@@ -989,7 +1085,76 @@ func TestPublishStorageDealsFailures(t *testing.T) {
 			valid, err := psdRet.ValidDeals.All(math.MaxUint64)
 			require.NoError(t, err)
 			assert.Equal(t, []uint64{0}, valid)
+			assert.Equal(t, []exitcode.ExitCode{exitcode.Ok, exitcode.ErrIllegalArgument}, psdRet.DealErrors)
+
+			rt.Verify()
+			actor.checkState(rt)
+		})
+
+		t.Run("reports an exit code for each dropped deal", func(t *testing.T) {
+			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+			deal1 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+			deal2 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+			deal3 := generateDealProposal(tutil.NewIDAddr(t, 1001), provider, startEpoch, endEpoch)
+			actor.addProviderFunds(rt, deal3.ProviderCollateral, mAddrs)
+
+			params := mkPublishStorageParams(deal1, deal2, deal3, deal1)
+
+			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+			rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+			expectQueryNetworkInfo(rt, actor)
+			rt.SetCaller(worker, builtin.AccountActorCodeID)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal1.Client, mustCbor(&deal1), nil)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal2.Client, mustCbor(&deal2), errors.New("invalid signature"))
+			rt.ExpectVerifySignature(crypto.Signature{}, deal3.Client, mustCbor(&deal3), nil)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal1.Client, mustCbor(&deal1), nil)
+
+			psdRet := rt.Call(actor.PublishStorageDeals, params).(*market.PublishStorageDealsReturn)
+			rt.Verify()
+			valid, err := psdRet.ValidDeals.All(math.MaxUint64)
+			require.NoError(t, err)
+			assert.Equal(t, []uint64{0}, valid)
+
+			assert.Equal(t, []exitcode.ExitCode{
+				exitcode.Ok,
+				exitcode.ErrIllegalArgument,   // invalid signature
+				exitcode.ErrInsufficientFunds, // insufficient client funds
+				exitcode.ErrIllegalArgument,   // duplicate proposal
+			}, psdRet.DealErrors)
+			actor.checkState(rt)
+		})
+
+		t.Run("rejects a deal which cannot claim its client allocation", func(t *testing.T) {
+			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+			deal1 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+			deal2 := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+
+			params := mkPublishStorageParams(deal1, deal2)
+			// Only verified deals may claim allocations.
+			params.AllocationIDs = []verifreg.AllocationID{verifreg.NoAllocationID, 7}
+
+			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+			rt.ExpectSend(provider, builtin.MethodsMiner.ControlAddresses, nil, abi.NewTokenAmount(0), &miner.GetControlAddressesReturn{Worker: worker, Owner: owner}, 0)
+			expectQueryNetworkInfo(rt, actor)
+			rt.SetCaller(worker, builtin.AccountActorCodeID)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal1.Client, mustCbor(&deal1), nil)
+			rt.ExpectVerifySignature(crypto.Signature{}, deal2.Client, mustCbor(&deal2), nil)
 
+			psdRet := rt.Call(actor.PublishStorageDeals, params).(*market.PublishStorageDealsReturn)
+			rt.Verify()
+			assert.Equal(t, []exitcode.ExitCode{exitcode.Ok, exitcode.ErrIllegalArgument}, psdRet.DealErrors)
+			actor.checkState(rt)
+		})
+
+		t.Run("fail when there are more allocation IDs than deals", func(t *testing.T) {
+			rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+			params := mkPublishStorageParams(generateDealProposal(client, provider, startEpoch, endEpoch))
+			params.AllocationIDs = []verifreg.AllocationID{1, 2}
+			rt.SetCaller(worker, builtin.AccountActorCodeID)
+			rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.PublishStorageDeals, params)
+			})
 			rt.Verify()
 			actor.checkState(rt)
 		})
@@ -1109,6 +1274,73 @@ func TestActivateDeals(t *testing.T) {
 		actor.assertDealsNotActivated(rt, currentEpoch, dealId4)
 		actor.checkState(rt)
 	})
+
+	t.Run("verified deals claim their allocations from network version 17", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.SetNetworkVersion(network.Version17)
+		verified := actor.generateVerifiedDealV17AndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId2 := actor.publishDeals(rt, mAddrs, publishDealReq{deal: verified})[0]
+		allocationID, found := actor.dealAllocation(rt, dealId2)
+		require.True(t, found)
+		assert.Equal(t, actor.lastAllocationID, allocationID)
+		_, found = actor.dealAllocation(rt, dealId1)
+		require.False(t, found)
+
+		// Only the verified deal claims an allocation.
+		claims := &verifreg.ClaimAllocationsParams{Claims: []verifreg.ClaimAllocationRequest{{
+			AllocationID: allocationID,
+			Client:       client,
+			Provider:     provider,
+			Data:         verified.PieceCID,
+			Size:         verified.PieceSize,
+			TermEnd:      verified.EndEpoch,
+			DealID:       dealId2,
+		}}, AllOrNothing: true}
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.ClaimAllocations, claims, big.Zero(),
+			&verifreg.ClaimAllocationsReturn{ClaimedAllocations: bitfield.NewFromSet([]uint64{0}), ClaimIDs: []verifreg.ClaimID{allocationID}}, exitcode.Ok)
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealId1, dealId2)
+
+		// The claimed allocation remains recorded for the deal.
+		claimedID, found := actor.dealAllocation(rt, dealId2)
+		require.True(t, found)
+		assert.Equal(t, allocationID, claimedID)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if a verified deal's allocation is not claimed", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		rt.SetNetworkVersion(network.Version17)
+		mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+		verified := actor.generateVerifiedDealV17AndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: verified})[0]
+
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		claims := &verifreg.ClaimAllocationsParams{Claims: []verifreg.ClaimAllocationRequest{{
+			AllocationID: actor.lastAllocationID,
+			Client:       client,
+			Provider:     provider,
+			Data:         verified.PieceCID,
+			Size:         verified.PieceSize,
+			TermEnd:      verified.EndEpoch,
+			DealID:       dealId,
+		}}, AllOrNothing: true}
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.ClaimAllocations, claims, big.Zero(),
+			nil, exitcode.ErrIllegalArgument)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.ActivateDeals, &market.ActivateDealsParams{DealIDs: []abi.DealID{dealId}, SectorExpiry: sectorExpiry})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
 }
 
 func TestActivateDealFailures(t *testing.T) {
@@ -1259,6 +1491,195 @@ func TestActivateDealFailures(t *testing.T) {
 
 }
 
+func TestBatchActivateDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(10)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	currentEpoch := abi.ChainEpoch(5)
+	sectorExpiry := endEpoch + 100
+
+	t.Run("activates deals of multiple sectors and returns their weights", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		verified := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+		verified.VerifiedDeal = true
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId2 := actor.publishDeals(rt, mAddrs, publishDealReq{deal: verified})[0]
+		dealId3 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+2)
+
+		ret := actor.batchActivateDeals(rt, provider, false,
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1, dealId2}},
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId3}},
+		)
+		actor.assertActivatedSectors(ret, 0, 1)
+
+		d1 := actor.getDealProposal(rt, dealId1)
+		d3 := actor.getDealProposal(rt, dealId3)
+		assert.Equal(t, uint64(d1.PieceSize+verified.PieceSize), ret.Sectors[0].DealSpace)
+		assert.Equal(t, market.DealWeight(d1), ret.Sectors[0].DealWeight)
+		assert.Equal(t, market.DealWeight(&verified), ret.Sectors[0].VerifiedDealWeight)
+		assert.Nil(t, ret.Sectors[0].UnsealedCID)
+		assert.Equal(t, market.DealWeight(d3), ret.Sectors[1].DealWeight)
+		assert.Equal(t, big.Zero(), ret.Sectors[1].VerifiedDealWeight)
+
+		for _, dealID := range []abi.DealID{dealId1, dealId2, dealId3} {
+			assert.Equal(t, currentEpoch, actor.getDealState(rt, dealID).SectorStartEpoch)
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("computes unsealed CID of activated sectors", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		d1 := actor.getDealProposal(rt, dealId1)
+		d2 := actor.getDealProposal(rt, dealId2)
+
+		c1 := tutil.MakeCID("100", &market.PieceCIDPrefix)
+		rt.ExpectComputeUnsealedSectorCID(1, []abi.PieceInfo{{Size: d1.PieceSize, PieceCID: d1.PieceCID}}, c1, nil)
+		// The second sector fails to compute its CID, so its deal is not activated.
+		rt.ExpectComputeUnsealedSectorCID(1, []abi.PieceInfo{{Size: d2.PieceSize, PieceCID: d2.PieceCID}}, cid.Undef, errors.New("error"))
+
+		ret := actor.batchActivateDeals(rt, provider, true,
+			market.SectorDealsActivation{SectorType: 1, SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1}},
+			market.SectorDealsActivation{SectorType: 1, SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId2}},
+		)
+		actor.assertActivatedSectors(ret, 0)
+		require.NotNil(t, ret.Sectors[0].UnsealedCID)
+		assert.Equal(t, c1, *ret.Sectors[0].UnsealedCID)
+
+		assert.Equal(t, currentEpoch, actor.getDealState(rt, dealId1).SectorStartEpoch)
+		actor.assertDealsNotActivated(rt, currentEpoch, dealId2)
+		actor.checkState(rt)
+	})
+
+	t.Run("drops only sectors with deals that cannot be activated", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		dealId3 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+2)
+		provider2 := tutil.NewIDAddr(t, 401)
+		dealId4 := actor.generateAndPublishDeal(rt, client, &minerAddrs{owner, worker, provider2, nil}, startEpoch, endEpoch)
+
+		ret := actor.batchActivateDeals(rt, provider, false,
+			// Deal 2 expires after the sector, so neither deal of this sector is activated.
+			market.SectorDealsActivation{SectorExpiry: endEpoch, DealIDs: []abi.DealID{dealId1, dealId2}},
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId3}},
+			// Deal 3 has been activated by the previous sector of the batch.
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId3}},
+			// Deal 4 belongs to another provider.
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId4}},
+			// Deal 1 may still be activated by a later sector.
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1}},
+			// Deal 42 does not exist.
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{abi.DealID(42)}},
+		)
+		actor.assertActivatedSectors(ret, 1, 4)
+
+		assert.Equal(t, currentEpoch, actor.getDealState(rt, dealId1).SectorStartEpoch)
+		assert.Equal(t, currentEpoch, actor.getDealState(rt, dealId3).SectorStartEpoch)
+		actor.assertDealsNotActivated(rt, currentEpoch, dealId2, dealId4)
+		actor.checkState(rt)
+	})
+
+	t.Run("drops only sectors whose allocations cannot be claimed", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.SetNetworkVersion(network.Version17)
+		verified1 := actor.generateVerifiedDealV17AndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+1)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId2 := actor.publishDeals(rt, mAddrs, publishDealReq{deal: verified1})[0]
+		allocationID1 := actor.lastAllocationID
+		verified2 := actor.generateVerifiedDealV17AndAddFunds(rt, client, mAddrs, startEpoch, endEpoch+2)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId3 := actor.publishDeals(rt, mAddrs, publishDealReq{deal: verified2})[0]
+		allocationID2 := actor.lastAllocationID
+
+		claimRequest := func(allocationID verifreg.AllocationID, deal market.DealProposal, dealID abi.DealID) *verifreg.ClaimAllocationsParams {
+			return &verifreg.ClaimAllocationsParams{Claims: []verifreg.ClaimAllocationRequest{{
+				AllocationID: allocationID,
+				Client:       client,
+				Provider:     provider,
+				Data:         deal.PieceCID,
+				Size:         deal.PieceSize,
+				TermEnd:      deal.EndEpoch,
+				DealID:       dealID,
+			}}, AllOrNothing: true}
+		}
+		// The first sector's allocation is rejected, the second's is claimed.
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.ClaimAllocations,
+			claimRequest(allocationID1, verified1, dealId2), big.Zero(), nil, exitcode.ErrIllegalArgument)
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.ClaimAllocations,
+			claimRequest(allocationID2, verified2, dealId3), big.Zero(),
+			&verifreg.ClaimAllocationsReturn{ClaimedAllocations: bitfield.NewFromSet([]uint64{0}), ClaimIDs: []verifreg.ClaimID{allocationID2}}, exitcode.Ok)
+
+		ret := actor.batchActivateDeals(rt, provider, false,
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1, dealId2}},
+			market.SectorDealsActivation{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId3}},
+		)
+		actor.assertActivatedSectors(ret, 1)
+		assert.Equal(t, market.DealWeight(&verified2), ret.Sectors[0].VerifiedDealWeight)
+
+		assert.Equal(t, currentEpoch, actor.getDealState(rt, dealId3).SectorStartEpoch)
+		actor.assertDealsNotActivated(rt, currentEpoch, dealId1, dealId2)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if state is inconsistent rather than dropping the sector", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+
+		// The second deal's proposal is missing from the pending proposals.
+		var st market.State
+		rt.GetState(&st)
+		pending, err := adt.AsSet(adt.AsStore(rt), st.PendingProposals, builtin.DefaultHamtBitwidth)
+		require.NoError(t, err)
+		pcid, err := actor.getDealProposal(rt, dealId2).Cid()
+		require.NoError(t, err)
+		require.NoError(t, pending.Delete(abi.CidKey(pcid)))
+		st.PendingProposals, err = pending.Root()
+		require.NoError(t, err)
+		rt.ReplaceState(&st)
+
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalState, "not in the pending set", func() {
+			rt.Call(actor.BatchActivateDeals, &market.BatchActivateDealsParams{Sectors: []market.SectorDealsActivation{
+				{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId1}},
+				{SectorExpiry: sectorExpiry, DealIDs: []abi.DealID{dealId2}},
+			}})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fail when caller is not a StorageMinerActor", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetCaller(provider, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.BatchActivateDeals, &market.BatchActivateDealsParams{})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
 func TestOnMinerSectorsTerminate(t *testing.T) {
 	owner := tutil.NewIDAddr(t, 101)
 	provider := tutil.NewIDAddr(t, 102)
@@ -1323,6 +1744,98 @@ func TestOnMinerSectorsTerminate(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	publishVerifiedDeal := func(rt *mock.Runtime, actor *marketActorTestHarness, pieceSize abi.PaddedPieceSize) abi.DealID {
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+		deal.PieceSize = pieceSize
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealId)
+		return dealId
+	}
+	expectRestoreBytes := func(rt *mock.Runtime, dataCap abi.StoragePower, code exitcode.ExitCode) {
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes,
+			&verifreg.RestoreBytesParams{Address: client, DealSize: dataCap}, abi.NewTokenAmount(0), nil, code)
+	}
+	verifiedPieceSize := abi.PaddedPieceSize(1 << 30)
+
+	t.Run("restores pro-rated datacap for a terminated verified deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealId := publishVerifiedDeal(rt, actor, verifiedPieceSize)
+
+		// a quarter of the deal's term has elapsed
+		terminationEpoch := rt.SetEpoch(startEpoch + (endEpoch-startEpoch)/4)
+		expectRestoreBytes(rt, abi.NewStoragePower(int64(verifiedPieceSize)*3/4), exitcode.Ok)
+		actor.terminateDeals(rt, provider, dealId)
+		actor.assertDealsTerminated(rt, terminationEpoch, dealId)
+		actor.checkState(rt)
+	})
+
+	t.Run("restores all datacap for a verified deal terminated before its start", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealId := publishVerifiedDeal(rt, actor, verifiedPieceSize)
+
+		expectRestoreBytes(rt, abi.NewStoragePower(int64(verifiedPieceSize)), exitcode.Ok)
+		actor.terminateDeals(rt, provider, dealId)
+		actor.assertDealsTerminated(rt, currentEpoch, dealId)
+		actor.checkState(rt)
+	})
+
+	t.Run("restores no datacap below the minimum verified deal size", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealId := publishVerifiedDeal(rt, actor, abi.PaddedPieceSize(verifreg.MinVerifiedDealSize.Uint64()))
+
+		// any elapsed term leaves less than the minimum to restore
+		terminationEpoch := rt.SetEpoch(startEpoch + 1)
+		actor.terminateDeals(rt, provider, dealId)
+		actor.assertDealsTerminated(rt, terminationEpoch, dealId)
+		actor.checkState(rt)
+	})
+
+	t.Run("restores no datacap for a terminated deal which claimed an allocation", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		rt.SetNetworkVersion(network.Version17)
+		deal := actor.generateVerifiedDealV17AndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		allocationID, found := actor.dealAllocation(rt, dealId)
+		require.True(t, found)
+
+		claims := &verifreg.ClaimAllocationsParams{Claims: []verifreg.ClaimAllocationRequest{{
+			AllocationID: allocationID,
+			Client:       client,
+			Provider:     provider,
+			Data:         deal.PieceCID,
+			Size:         deal.PieceSize,
+			TermEnd:      deal.EndEpoch,
+			DealID:       dealId,
+		}}, AllOrNothing: true}
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.ClaimAllocations, claims, big.Zero(),
+			&verifreg.ClaimAllocationsReturn{ClaimedAllocations: bitfield.NewFromSet([]uint64{0}), ClaimIDs: []verifreg.ClaimID{allocationID}}, exitcode.Ok)
+		actor.activateDeals(rt, sectorExpiry, provider, currentEpoch, dealId)
+
+		// the claim holds the deal's datacap, so none is restored
+		terminationEpoch := rt.SetEpoch(startEpoch + (endEpoch-startEpoch)/4)
+		actor.terminateDeals(rt, provider, dealId)
+		actor.assertDealsTerminated(rt, terminationEpoch, dealId)
+		actor.checkState(rt)
+	})
+
+	t.Run("termination succeeds if datacap cannot be restored", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetEpoch(currentEpoch)
+		dealId := publishVerifiedDeal(rt, actor, verifiedPieceSize)
+
+		expectRestoreBytes(rt, abi.NewStoragePower(int64(verifiedPieceSize)), exitcode.ErrIllegalArgument)
+		actor.terminateDeals(rt, provider, dealId)
+		actor.assertDealsTerminated(rt, currentEpoch, dealId)
+		actor.checkState(rt)
+	})
+
 	t.Run("terminate valid deals along with just-expired deal", func(t *testing.T) {
 		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
 		rt.SetEpoch(currentEpoch)
@@ -1542,15 +2055,16 @@ func TestCronTick(t *testing.T) {
 		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
 
 		// move the current epoch such that the deal's last updated field is set to the start epoch of the deal
-		// and the next tick for it is scheduled at the endepoch.
+		// and the next tick for it is scheduled at the processing epoch following the end epoch.
 		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
 		actor.cronTick(rt)
 
 		// update last updated to some time in the future (breaks state invariants)
-		actor.updateLastUpdated(rt, dealId, endEpoch+1000)
+		expiryEpoch := processEpoch(t, dealId, endEpoch)
+		actor.updateLastUpdated(rt, dealId, expiryEpoch+1000)
 
-		// set current epoch of the deal to the end epoch so it's picked up for "processing" in the next cron tick.
-		rt.SetEpoch(endEpoch)
+		// set current epoch of the deal to its expiry processing epoch so it's picked up in the next cron tick.
+		rt.SetEpoch(expiryEpoch)
 
 		rt.ExpectAbort(exitcode.ErrIllegalState, func() {
 			actor.cronTick(rt)
@@ -1652,9 +2166,10 @@ func TestRandomCronEpochDuringPublish(t *testing.T) {
 		duration := big.Sub(big.NewInt(int64(processEpoch)), big.NewInt(int64(startEpoch)))
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 
-		// payment at next epoch
+		// cron makes no further payment, which is settled on demand
 		current := rt.SetEpoch(processEpoch + market.DealUpdatesInterval)
-		pay, _ = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		actor.cronTickNoChange(rt, client, provider)
+		pay, _ = actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
 		duration = big.Sub(big.NewInt(int64(current)), big.NewInt(int64(processEpoch)))
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 		actor.checkState(rt)
@@ -1797,17 +2312,16 @@ func TestLockedFundTrackingStates(t *testing.T) {
 	clc = big.Sub(clc, d3.ClientCollateral)
 	actor.assertLockedFundStates(rt, csf, plc, clc)
 
-	// deal1 and deal2 will now be charged at epoch curr + market.DealUpdatesInterval, so nothing changes before that.
-	curr = rt.SetEpoch(curr + market.DealUpdatesInterval - 1)
+	// cron makes no further payment for deal1 and deal2, so nothing changes.
+	curr = rt.SetEpoch(curr + market.DealUpdatesInterval)
 	actor.cronTick(rt)
 	actor.assertLockedFundStates(rt, csf, plc, clc)
 
-	// one more round of payment for deal1 and deal2
-	curr = rt.SetEpoch(curr + 1)
+	// one more round of payment for deal1 and deal2 when settled
 	duration := big.NewInt(market.DealUpdatesInterval)
 	payment = big.Product(big.NewInt(2), d1.StoragePricePerEpoch, duration)
 	csf = big.Sub(csf, payment)
-	actor.cronTick(rt)
+	actor.settleDealPayments(rt, m1.worker, dealId1, dealId2)
 	actor.assertLockedFundStates(rt, csf, plc, clc)
 
 	// slash deal1 at 201
@@ -1815,7 +2329,7 @@ func TestLockedFundTrackingStates(t *testing.T) {
 	actor.terminateDeals(rt, m1.provider, dealId1)
 
 	// cron tick to slash deal1 and expire deal2
-	rt.SetEpoch(endEpoch)
+	rt.SetEpoch(processEpoch(t, dealId2, endEpoch))
 	csf = big.Zero()
 	clc = big.Zero()
 	plc = big.Zero()
@@ -1899,8 +2413,8 @@ func TestCronTickTimedoutDeals(t *testing.T) {
 
 		//  publishing verified deals
 		rt.SetCaller(worker, builtin.AccountActorCodeID)
-		dealIds := actor.publishDeals(rt, mAddrs, publishDealReq{deal1},
-			publishDealReq{deal2}, publishDealReq{deal3})
+		dealIds := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal1},
+			publishDealReq{deal: deal2}, publishDealReq{deal: deal3})
 
 		// do a cron tick for it -> all should time out and get slashed
 		// ONLY deal1 and deal2 should be sent to the Registry actor
@@ -1990,30 +2504,29 @@ func TestCronTickDealExpiry(t *testing.T) {
 		require.EqualValues(t, pay, big.Mul(big.NewInt(5), d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// Setting the current epoch to anything less than next schedule wont make any payment
-		current = rt.SetEpoch(current + market.DealUpdatesInterval - 1)
+		// cron makes no further payment before the deal expires
+		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		actor.cronTickNoChange(rt, client, provider)
 
-		// however setting the current epoch to next schedle will make the payment
-		current = rt.SetEpoch(current + 1)
+		// however settling the deal will make the payment
 		duration := big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay, slashed = actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// a second cron tick for the same epoch should not change anything
+		// a cron tick for the same epoch should not change anything
 		actor.cronTickNoChange(rt, client, provider)
 
-		// next epoch schedule
+		// settling again later pays for the epochs since the last settlement
 		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		duration = big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay, slashed = actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// setting epoch to greater than end will expire the deal, make the payment and unlock all funds
+		// cron processing after the end epoch will expire the deal, make the payment and unlock all funds
 		duration = big.NewInt(int64(endEpoch - current))
-		current = rt.SetEpoch(endEpoch + 300)
+		current = rt.SetEpoch(processEpoch(t, dealId, endEpoch))
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 		require.EqualValues(t, big.Zero(), slashed)
@@ -2219,7 +2732,7 @@ func TestCronTickDealSlashing(t *testing.T) {
 		actor.checkState(rt)
 	})
 
-	t.Run("deal is correctly processed twice in the same crontick and slashed", func(t *testing.T) {
+	t.Run("deal is correctly processed and slashed at the cron schedule following termination", func(t *testing.T) {
 		t.Parallel()
 		// start epoch should equal first processing epoch for logic to work
 		// 2880 + 0 % 2880 = 2880
@@ -2239,7 +2752,7 @@ func TestCronTickDealSlashing(t *testing.T) {
 		actor.terminateDeals(rt, provider, dealId)
 
 		duration := big.NewInt(int64(slashEpoch - current))
-		current = rt.SetEpoch(current + market.DealUpdatesInterval + 2)
+		current = rt.SetEpoch(processEpoch(t, dealId, slashEpoch+1))
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
 		require.EqualValues(t, d.ProviderCollateral, slashed)
@@ -2295,22 +2808,17 @@ func TestCronTickDealSlashing(t *testing.T) {
 		require.EqualValues(t, pay, big.Mul(big.NewInt(int64(5+processStart-startEpoch)), d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// Setting the current epoch to before the next schedule will NOT make any changes as the deal
-		// is still not scheduled
-		current = rt.SetEpoch(current + market.DealUpdatesInterval - 1)
-		actor.cronTickNoChange(rt, client, provider)
-
-		// a second cron tick for the same epoch should not change anything
+		// cron will NOT make any changes as the deal is not scheduled until its expiry
+		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		actor.cronTickNoChange(rt, client, provider)
 
-		//  make another payment
-		current = rt.SetEpoch(current + 1)
+		//  settle to make another payment
 		duration := big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay, slashed = actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// a second cron tick for the same epoch should not change anything
+		// a cron tick for the same epoch should not change anything
 		actor.cronTickNoChange(rt, client, provider)
 
 		// now terminate the deal
@@ -2318,12 +2826,13 @@ func TestCronTickDealSlashing(t *testing.T) {
 		duration = big.NewInt(int64(slashEpoch - current))
 		actor.terminateDeals(rt, provider, dealId)
 
-		// Setting the epoch to anything less than next schedule will not make any change even though the deal is slashed
-		current = rt.SetEpoch(current + market.DealUpdatesInterval - 1)
+		// Setting the epoch to anything less than the scheduled processing will not make any change even though the deal is slashed
+		processSlash := processEpoch(t, dealId, slashEpoch+1)
+		current = rt.SetEpoch(processSlash - 1)
 		actor.cronTickNoChange(rt, client, provider)
 
-		// next epoch for cron schedule  -> payment will be made and deal will be slashed
-		current = rt.SetEpoch(current + 1)
+		// epoch for cron schedule  -> payment will be made and deal will be slashed
+		current = rt.SetEpoch(processSlash)
 		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
 		require.EqualValues(t, d.ProviderCollateral, slashed)
@@ -2347,30 +2856,984 @@ func TestCronTickDealSlashing(t *testing.T) {
 		require.EqualValues(t, pay, big.Mul(big.NewInt(int64(5+processStart-startEpoch)), d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		//  Incrementing the current epoch another update interval will make another payment
+		//  Incrementing the current epoch another update interval and settling will make another payment
 		current = rt.SetEpoch(current + market.DealUpdatesInterval)
 		duration := big.NewInt(market.DealUpdatesInterval)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		pay, slashed = actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
 		require.EqualValues(t, pay, big.Mul(duration, d.StoragePricePerEpoch))
 		require.EqualValues(t, big.Zero(), slashed)
 
-		// set current epoch to deal end epoch and attempt to slash it -> should not be slashed
-		// as deal is considered to be expired.
-		duration = big.NewInt(int64(endEpoch - current))
-		rt.SetEpoch(endEpoch)
+		// set current epoch to deal end epoch and attempt to slash it -> should not be slashed
+		// as deal is considered to be expired.
+		duration = big.NewInt(int64(endEpoch - current))
+		rt.SetEpoch(endEpoch)
+		actor.terminateDeals(rt, provider, dealId)
+
+		// next epoch for cron schedule is the processing epoch following the end epoch ->
+		// setting epoch to that will cause deal to be expired, payment will be made
+		// and deal will NOT be slashed
+		current = rt.SetEpoch(processEpoch(t, dealId, endEpoch))
+		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+
+		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
+		require.EqualValues(t, big.Zero(), slashed)
+
+		// deal should be deleted as it should have expired
+		actor.assertDealDeleted(rt, dealId, d)
+		actor.checkState(rt)
+	})
+}
+
+func TestSettleDealPayments(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	t.Run("settles payment up to the current epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		// the first settlement pays from the start epoch and removes the pending proposal
+		current := rt.SetEpoch(startEpoch + 100)
+		pay, slashed := actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
+		require.EqualValues(t, big.Mul(big.NewInt(100), d.StoragePricePerEpoch), pay)
+		require.EqualValues(t, big.Zero(), slashed)
+		require.EqualValues(t, current, actor.getDealState(rt, dealId).LastUpdatedEpoch)
+
+		// settling again in the same epoch pays nothing
+		ret := actor.settleDealPayments(rt, worker, dealId)
+		require.Equal(t, []market.DealSettlementSummary{{DealID: dealId, Payment: big.Zero(), Completed: false}}, ret.Settlements)
+
+		// the first cron tick pays only for the epochs since settlement
+		current = rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		pay, _ = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		require.EqualValues(t, big.Mul(big.NewInt(int64(current-startEpoch-100)), d.StoragePricePerEpoch), pay)
+
+		// any party may settle
+		current = rt.SetEpoch(current + 1000)
+		ret = actor.settleDealPayments(rt, tutil.NewIDAddr(t, 1000), dealId)
+		require.Equal(t, []market.DealSettlementSummary{{
+			DealID:    dealId,
+			Payment:   big.Mul(big.NewInt(1000), d.StoragePricePerEpoch),
+			Completed: false,
+		}}, ret.Settlements)
+
+		// including a contract actor
+		current = rt.SetEpoch(current + 1000)
+		rt.SetCaller(tutil.NewIDAddr(t, 1001), builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAny()
+		ret = rt.Call(actor.SettleDealPayments, &market.SettleDealPaymentsParams{DealIDs: []abi.DealID{dealId}}).(*market.SettleDealPaymentsReturn)
+		rt.Verify()
+		require.Equal(t, []market.DealSettlementSummary{{
+			DealID:    dealId,
+			Payment:   big.Mul(big.NewInt(1000), d.StoragePricePerEpoch),
+			Completed: false,
+		}}, ret.Settlements)
+		actor.checkState(rt)
+	})
+
+	t.Run("deal not yet started is not settled", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.SetEpoch(startEpoch - 1)
+		ret := actor.settleDealPayments(rt, worker, dealId)
+		require.Equal(t, []market.DealSettlementSummary{{DealID: dealId, Payment: big.Zero(), Completed: false}}, ret.Settlements)
+		require.EqualValues(t, market.EpochUndefined, actor.getDealState(rt, dealId).LastUpdatedEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("reports deals which cannot be settled", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		activeId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		pendingId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		d := actor.getDealProposal(rt, activeId)
+
+		rt.SetEpoch(startEpoch + 10)
+		ret := actor.settleDealPayments(rt, worker, pendingId, activeId, 1000)
+		require.Equal(t, []market.DealSettlementSummary{{
+			DealID:    activeId,
+			Payment:   big.Mul(big.NewInt(10), d.StoragePricePerEpoch),
+			Completed: false,
+		}}, ret.Settlements)
+		require.Equal(t, []market.DealSettlementError{
+			{DealID: pendingId, Code: exitcode.ErrIllegalArgument},
+			{DealID: 1000, Code: exitcode.ErrNotFound},
+		}, ret.Errors)
+		actor.checkState(rt)
+	})
+
+	t.Run("settlement completes an expired deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		current := rt.SetEpoch(endEpoch + 1)
+		pay, slashed := actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
+		require.EqualValues(t, big.Mul(big.NewInt(int64(endEpoch-startEpoch)), d.StoragePricePerEpoch), pay)
+		require.EqualValues(t, big.Zero(), slashed)
+		actor.assertDealDeleted(rt, dealId, d)
+
+		// settling again finds no deal
+		ret := actor.settleDealPayments(rt, worker, dealId)
+		require.Empty(t, ret.Settlements)
+		require.Equal(t, []market.DealSettlementError{{DealID: dealId, Code: exitcode.ErrNotFound}}, ret.Errors)
+
+		// cron skips the deal's remaining scheduled operations
+		rt.SetEpoch(processEpoch(t, dealId, endEpoch))
+		actor.cronTick(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("settlement processes slashing of a terminated deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		current := rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		actor.cronTick(rt)
+
+		slashEpoch := rt.SetEpoch(current + 200)
+		actor.terminateDeals(rt, provider, dealId)
+
+		current = rt.SetEpoch(slashEpoch + 10)
+		pay, slashed := actor.settleAndAssertBalances(rt, client, provider, worker, current, dealId)
+		require.EqualValues(t, big.Mul(big.NewInt(200), d.StoragePricePerEpoch), pay)
+		require.EqualValues(t, d.ProviderCollateral, slashed)
+		actor.assertDealDeleted(rt, dealId, d)
+
+		// cron skips the slashing scheduled by termination, with nothing further to burn
+		rt.SetEpoch(processEpoch(t, dealId, slashEpoch+1))
+		actor.cronTick(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("slashed deals are scheduled for prompt processing by cron", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		current := rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		actor.cronTick(rt)
+
+		slashEpoch := rt.SetEpoch(current + 200)
+		actor.terminateDeals(rt, provider, dealId)
+
+		current = rt.SetEpoch(processEpoch(t, dealId, slashEpoch+1))
+		pay, slashed := actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		require.EqualValues(t, big.Mul(big.NewInt(200), d.StoragePricePerEpoch), pay)
+		require.EqualValues(t, d.ProviderCollateral, slashed)
+		actor.assertDealDeleted(rt, dealId, d)
+		actor.checkState(rt)
+	})
+}
+
+func TestDealQueries(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	caller := tutil.NewIDAddr(t, 105)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	query := func(rt *mock.Runtime, method interface{}, params cbor.Marshaler) interface{} {
+		rt.SetCaller(caller, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		ret := rt.Call(method, params)
+		rt.Verify()
+		return ret
+	}
+
+	t.Run("deal proposal fields", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		d := actor.getDealProposal(rt, dealId)
+		params := &market.DealQueryParams{DealID: dealId}
+
+		commitment := query(rt, actor.GetDealDataCommitment, params).(*market.GetDealDataCommitmentReturn)
+		assert.Equal(t, d.PieceCID, commitment.PieceCID)
+		assert.Equal(t, d.PieceSize, commitment.PieceSize)
+
+		assert.Equal(t, client, *query(rt, actor.GetDealClient, params).(*address.Address))
+		assert.Equal(t, provider, *query(rt, actor.GetDealProvider, params).(*address.Address))
+		assert.Equal(t, d.Label, *query(rt, actor.GetDealLabel, params).(*market.DealLabel))
+		assert.Equal(t, market.GetDealTermReturn{StartEpoch: startEpoch, EndEpoch: endEpoch},
+			*query(rt, actor.GetDealTerm, params).(*market.GetDealTermReturn))
+		assert.Equal(t, d.StoragePricePerEpoch, *query(rt, actor.GetDealPricePerEpoch, params).(*abi.TokenAmount))
+		assert.Equal(t, cbg.CborBool(false), *query(rt, actor.GetDealVerified, params).(*cbg.CborBool))
+		actor.checkState(rt)
+	})
+
+	t.Run("deal activation state", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		params := &market.DealQueryParams{DealID: dealId}
+
+		activation := query(rt, actor.GetDealActivation, params).(*market.GetDealActivationReturn)
+		assert.Equal(t, market.GetDealActivationReturn{Activated: market.EpochUndefined, Terminated: market.EpochUndefined}, *activation)
+
+		activationEpoch := rt.SetEpoch(startEpoch - 1)
+		actor.activateDeals(rt, sectorExpiry, provider, activationEpoch, dealId)
+		activation = query(rt, actor.GetDealActivation, params).(*market.GetDealActivationReturn)
+		assert.Equal(t, market.GetDealActivationReturn{Activated: activationEpoch, Terminated: market.EpochUndefined}, *activation)
+
+		terminationEpoch := rt.SetEpoch(startEpoch + 100)
+		actor.terminateDeals(rt, provider, dealId)
+		activation = query(rt, actor.GetDealActivation, params).(*market.GetDealActivationReturn)
+		assert.Equal(t, market.GetDealActivationReturn{Activated: activationEpoch, Terminated: terminationEpoch}, *activation)
+		actor.checkState(rt)
+	})
+
+	t.Run("balances", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		d := actor.getDealProposal(rt, dealId)
+
+		balance := query(rt, actor.GetBalance, &client).(*market.GetBalanceReturn)
+		assert.Equal(t, actor.getEscrowBalance(rt, client), balance.Balance)
+		assert.Equal(t, d.ClientBalanceRequirement(), balance.Locked)
+
+		balance = query(rt, actor.GetBalance, &provider).(*market.GetBalanceReturn)
+		assert.Equal(t, actor.getEscrowBalance(rt, provider), balance.Balance)
+		assert.Equal(t, d.ProviderCollateral, balance.Locked)
+
+		// an address with no balance
+		balance = query(rt, actor.GetBalance, &caller).(*market.GetBalanceReturn)
+		assert.Equal(t, market.GetBalanceReturn{Balance: big.Zero(), Locked: big.Zero()}, *balance)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for unknown deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			query(rt, actor.GetDealClient, &market.DealQueryParams{DealID: 1000})
+		})
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			query(rt, actor.GetDealActivation, &market.DealQueryParams{DealID: 1000})
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestCancelDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+	providerCollateral := big.NewInt(100)
+	clientCollateral := big.NewInt(30)
+
+	t.Run("client cancels unactivated deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealWithCollateralAndAddFunds(rt, client, mAddrs, providerCollateral, clientCollateral, startEpoch, endEpoch)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		clientEscrow := actor.getEscrowBalance(rt, client)
+		providerEscrow := actor.getEscrowBalance(rt, provider)
+
+		rt.SetEpoch(startEpoch - 1)
+		actor.cancelDeals(rt, client, dealId)
+
+		// the client pays the cancellation penalty to the provider, and all funds are unlocked
+		penalty := market.DealCancellationPenalty(deal.TotalStorageFee(), clientCollateral, providerCollateral)
+		assert.True(t, penalty.GreaterThan(clientCollateral))
+		assert.Equal(t, big.Sub(clientEscrow, penalty), actor.getEscrowBalance(rt, client))
+		assert.Equal(t, big.Add(providerEscrow, penalty), actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, client))
+		assert.Equal(t, big.Zero(), actor.getLockedBalance(rt, provider))
+		actor.assertLockedFundStates(rt, big.Zero(), big.Zero(), big.Zero())
+		actor.assertDealDeleted(rt, dealId, &deal)
+
+		// the deal is no longer processed by cron
+		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		actor.cronTickNoChange(rt, client, provider)
+		actor.checkState(rt)
+	})
+
+	t.Run("cancelling verified deal restores client data cap", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.RestoreBytes, &verifreg.RestoreBytesParams{
+			Address:  client,
+			DealSize: big.NewIntUnsigned(uint64(deal.PieceSize)),
+		}, abi.NewTokenAmount(0), nil, exitcode.Ok)
+		actor.cancelDeals(rt, client, dealId)
+		actor.assertDealDeleted(rt, dealId, &deal)
+		actor.checkState(rt)
+	})
+
+	t.Run("cancels only the given deals", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId1 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		dealId2 := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch+1)
+		d1 := actor.getDealProposal(rt, dealId1)
+
+		actor.cancelDeals(rt, client, dealId1)
+		actor.assertDealDeleted(rt, dealId1, d1)
+		require.NotNil(t, actor.getDealProposal(rt, dealId2))
+
+		// the remaining deal times out as usual
+		d2 := actor.getDealProposal(rt, dealId2)
+		rt.SetEpoch(processEpoch(t, dealId2, startEpoch))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, d2.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		actor.assertDealDeleted(rt, dealId2, d2)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if caller is not the client", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not the client", func() {
+			actor.cancelDeals(rt, worker, dealId)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for pre-committed deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		actor.onMinerSectorsPreCommit(rt, provider, dealId)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "has been pre-committed", func() {
+			actor.cancelDeals(rt, client, dealId)
+		})
+		actor.checkState(rt)
+
+		// the pre-commitment is cleared when the deal times out
+		d := actor.getDealProposal(rt, dealId)
+		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, d.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+		actor.assertDealDeleted(rt, dealId, d)
+		actor.checkState(rt)
+	})
+
+	t.Run("verifying deals for a pre-commitment does not prevent cancellation", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		actor.verifyDealsForActivation(rt, provider, []market.SectorDeals{{
+			SectorExpiry: sectorExpiry,
+			DealIDs:      []abi.DealID{dealId},
+		}})
+
+		actor.cancelDeals(rt, client, dealId)
+		actor.checkState(rt)
+	})
+
+	t.Run("succeeds once the pre-commitment is removed", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+		actor.onMinerSectorsPreCommit(rt, provider, dealId)
+		actor.onMinerPreCommitsRemoved(rt, provider, dealId)
+
+		d := actor.getDealProposal(rt, dealId)
+		actor.cancelDeals(rt, client, dealId)
+		actor.assertDealDeleted(rt, dealId, d)
+		actor.checkState(rt)
+	})
+
+	t.Run("only the deal's provider may pre-commit it", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "not caller", func() {
+			actor.onMinerSectorsPreCommit(rt, tutil.NewIDAddr(t, 205), dealId)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for activated deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "has been activated", func() {
+			actor.cancelDeals(rt, client, dealId)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails from deal start epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.SetEpoch(startEpoch)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "cannot be cancelled", func() {
+			actor.cancelDeals(rt, client, dealId)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for unknown deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.cancelDeals(rt, client, dealId, dealId+1)
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestExtendDeals(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	newEndEpoch := endEpoch + 100*builtin.EpochsInDay
+	sectorExpiry := newEndEpoch + 100
+
+	extension := func(dealID abi.DealID, newEnd abi.ChainEpoch) market.ClientDealExtension {
+		return market.ClientDealExtension{Extension: market.DealExtension{DealID: dealID, NewEndEpoch: newEnd}}
+	}
+
+	t.Run("extends active deal and locks additional storage fee", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+		addedFee := big.Mul(big.NewInt(int64(newEndEpoch-endEpoch)), d.StoragePricePerEpoch)
+		actor.addParticipantFunds(rt, client, addedFee)
+		clientLocked := actor.getLockedBalance(rt, client)
+
+		ret := actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, newEndEpoch))
+		assert.Equal(t, big.Mul(big.NewIntUnsigned(uint64(d.PieceSize)), big.NewInt(int64(newEndEpoch-endEpoch))), ret.DealWeight)
+		assert.Equal(t, big.Zero(), ret.VerifiedDealWeight)
+		assert.Equal(t, newEndEpoch, actor.getDealProposal(rt, dealId).EndEpoch)
+		assert.Equal(t, big.Add(clientLocked, addedFee), actor.getLockedBalance(rt, client))
+		actor.assertLockedFundStates(rt, big.Add(d.TotalStorageFee(), addedFee), d.ProviderCollateral, d.ClientCollateral)
+		actor.checkState(rt)
+
+		// the deal's original expiry no longer ends it
+		clientEscrow := actor.getEscrowBalance(rt, client)
+		rt.SetEpoch(processEpoch(t, dealId, endEpoch))
+		actor.cronTick(rt)
+		require.NotNil(t, actor.getDealProposal(rt, dealId))
+
+		// the client pays for the full extended term
+		rt.SetEpoch(newEndEpoch)
+		actor.settleDealPayments(rt, worker, dealId)
+		totalPayment := big.Mul(big.NewInt(int64(newEndEpoch-startEpoch)), d.StoragePricePerEpoch)
+		assert.Equal(t, big.Sub(clientEscrow, totalPayment), actor.getEscrowBalance(rt, client))
+		actor.assertDealDeleted(rt, dealId, d)
+		actor.checkState(rt)
+	})
+
+	t.Run("extension reschedules the expiry of a deal processed at its start", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+		actor.addParticipantFunds(rt, client, big.Mul(big.NewInt(int64(newEndEpoch-endEpoch)), d.StoragePricePerEpoch))
+		rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		actor.cronTick(rt)
+		require.True(t, actor.dealOpScheduledAt(rt, dealId, processEpoch(t, dealId, endEpoch)))
+
+		actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, newEndEpoch))
+		assert.False(t, actor.dealOpScheduledAt(rt, dealId, processEpoch(t, dealId, endEpoch)))
+		assert.True(t, actor.dealOpScheduledAt(rt, dealId, processEpoch(t, dealId, newEndEpoch)))
+		actor.checkState(rt)
+	})
+
+	t.Run("extension of verified deal adds verified deal weight", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealId)
+		actor.addParticipantFunds(rt, client, big.Mul(big.NewInt(int64(newEndEpoch-endEpoch)), deal.StoragePricePerEpoch))
+
+		ret := actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, newEndEpoch))
+		assert.Equal(t, big.Zero(), ret.DealWeight)
+		assert.Equal(t, big.Mul(big.NewIntUnsigned(uint64(deal.PieceSize)), big.NewInt(int64(newEndEpoch-endEpoch))), ret.VerifiedDealWeight)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails without client funds for additional term", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, newEndEpoch))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if caller is not the provider", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 999), builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "is not the provider", func() {
+			rt.Call(actor.ExtendDeals, &market.ExtendDealsParams{SectorExpiry: sectorExpiry, Extensions: []market.ClientDealExtension{extension(dealId, newEndEpoch)}})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for unactivated deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.generateAndPublishDeal(rt, client, mAddrs, startEpoch, endEpoch)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "has not been activated", func() {
+			actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, newEndEpoch))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for invalid new end epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be after", func() {
+			actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, endEpoch))
+		})
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "after sector expiry", func() {
+			actor.extendDeals(rt, provider, sectorExpiry, extension(dealId, sectorExpiry+1))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("extends a deal of maximum duration up to its sector's expiration", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		_, maxDuration := market.DealDurationBounds(abi.PaddedPieceSize(2048))
+		maxEnd := startEpoch + maxDuration
+		longSectorExpiry := maxEnd + 100*builtin.EpochsInDay
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, maxEnd, 0, longSectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+		actor.addParticipantFunds(rt, client, big.Mul(big.NewInt(int64(longSectorExpiry-maxEnd)), d.StoragePricePerEpoch))
+
+		actor.extendDeals(rt, provider, longSectorExpiry, extension(dealId, longSectorExpiry))
+		assert.Equal(t, longSectorExpiry, actor.getDealProposal(rt, dealId).EndEpoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails for invalid client signature", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		ext := extension(dealId, newEndEpoch)
+
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.ExpectVerifySignature(ext.ClientSignature, client, mustCbor(&ext.Extension), errors.New("bad signature"))
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid extension", func() {
+			rt.Call(actor.ExtendDeals, &market.ExtendDealsParams{SectorExpiry: sectorExpiry, Extensions: []market.ClientDealExtension{ext}})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails without client datacap for verified deal", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		deal := actor.generateDealAndAddFunds(rt, client, mAddrs, startEpoch, endEpoch)
+		deal.VerifiedDeal = true
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		dealId := actor.publishDeals(rt, mAddrs, publishDealReq{deal: deal})[0]
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealId)
+		actor.addParticipantFunds(rt, client, big.Mul(big.NewInt(int64(newEndEpoch-endEpoch)), deal.StoragePricePerEpoch))
+		ext := extension(dealId, newEndEpoch)
+
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+		rt.ExpectVerifySignature(ext.ClientSignature, client, mustCbor(&ext.Extension), nil)
+		rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.UseBytes, &verifreg.UseBytesParams{
+			Address:  client,
+			DealSize: market.DataCapForDealExtension(deal.PieceSize, startEpoch, endEpoch, newEndEpoch),
+		}, big.Zero(), nil, exitcode.ErrIllegalArgument)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "failed to acquire datacap", func() {
+			rt.Call(actor.ExtendDeals, &market.ExtendDealsParams{SectorExpiry: sectorExpiry, Extensions: []market.ClientDealExtension{ext}})
+		})
+	})
+}
+
+func TestDealsByPiece(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	provider2 := tutil.NewIDAddr(t, 105)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+	mAddrs2 := &minerAddrs{owner, worker, provider2, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+	piece := tutil.MakeCID("piece", &market.PieceCIDPrefix)
+	otherPiece := tutil.MakeCID("other", &market.PieceCIDPrefix)
+	pieceSize := abi.PaddedPieceSize(2048)
+
+	setup := func(t *testing.T) (*mock.Runtime, *marketActorTestHarness, []abi.DealID) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetAddressActorType(provider2, builtin.StorageMinerActorCodeID)
+		dealIds := []abi.DealID{
+			actor.generateAndPublishDealForPiece(rt, client, mAddrs, startEpoch, endEpoch, piece, pieceSize),
+			actor.generateAndPublishDealForPiece(rt, client, mAddrs2, startEpoch, endEpoch, piece, pieceSize),
+			actor.generateAndPublishDealForPiece(rt, client, mAddrs, startEpoch, endEpoch, otherPiece, pieceSize),
+		}
+		return rt, actor, dealIds
+	}
+
+	t.Run("finds deals storing a piece with their providers", func(t *testing.T) {
+		rt, actor, dealIds := setup(t)
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealIds[0])
+
+		deals := actor.getDealsForPiece(rt, piece)
+		assert.Equal(t, []market.PieceDeal{
+			{DealID: dealIds[0], Provider: provider, Activated: 0},
+			{DealID: dealIds[1], Provider: provider2, Activated: market.EpochUndefined},
+		}, deals)
+		assert.Equal(t, []market.PieceDeal{
+			{DealID: dealIds[2], Provider: provider, Activated: market.EpochUndefined},
+		}, actor.getDealsForPiece(rt, otherPiece))
+		assert.Empty(t, actor.getDealsForPiece(rt, tutil.MakeCID("unknown", &market.PieceCIDPrefix)))
+		actor.checkState(rt)
+	})
+
+	t.Run("pages through deals from a start deal ID", func(t *testing.T) {
+		rt, actor, dealIds := setup(t)
+		third := actor.generateAndPublishDealForPiece(rt, client, mAddrs, startEpoch, endEpoch+1, piece, pieceSize)
+
+		page := actor.getDealsForPiecePage(rt, piece, 0, 1)
+		assert.Equal(t, []market.PieceDeal{{DealID: dealIds[0], Provider: provider, Activated: market.EpochUndefined}}, page.Deals)
+		assert.True(t, page.More)
+
+		page = actor.getDealsForPiecePage(rt, piece, dealIds[0]+1, 1)
+		assert.Equal(t, []market.PieceDeal{{DealID: dealIds[1], Provider: provider2, Activated: market.EpochUndefined}}, page.Deals)
+		assert.True(t, page.More)
+
+		page = actor.getDealsForPiecePage(rt, piece, dealIds[1]+1, 1)
+		assert.Equal(t, []market.PieceDeal{{DealID: third, Provider: provider, Activated: market.EpochUndefined}}, page.Deals)
+		assert.False(t, page.More)
+
+		page = actor.getDealsForPiecePage(rt, piece, third+1, 1)
+		assert.Empty(t, page.Deals)
+		assert.False(t, page.More)
+		actor.checkState(rt)
+	})
+
+	t.Run("terminated deals are removed from the index", func(t *testing.T) {
+		rt, actor, dealIds := setup(t)
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealIds[0])
+
+		rt.SetEpoch(startEpoch + 10)
+		actor.terminateDeals(rt, provider, dealIds[0])
+		assert.Equal(t, []market.PieceDeal{
+			{DealID: dealIds[1], Provider: provider2, Activated: market.EpochUndefined},
+		}, actor.getDealsForPiece(rt, piece))
+		actor.checkState(rt)
+	})
+
+	t.Run("deleted deals are removed from the index", func(t *testing.T) {
+		rt, actor, dealIds := setup(t)
+
+		// one deal is cancelled, and the other times out
+		actor.cancelDeals(rt, client, dealIds[0])
+		d2 := actor.getDealProposal(rt, dealIds[1])
+		rt.SetEpoch(processEpoch(t, dealIds[1], startEpoch))
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, d2.ProviderCollateral, nil, exitcode.Ok)
+		actor.cronTick(rt)
+
+		assert.Empty(t, actor.getDealsForPiece(rt, piece))
+		assert.Len(t, actor.getDealsForPiece(rt, otherPiece), 1)
+		actor.checkState(rt)
+	})
+
+	t.Run("deals are not indexed when the index is disabled", func(t *testing.T) {
+		// Remove this nasty static/global access when policy is encapsulated in a structure.
+		// See https://github.com/filecoin-project/specs-actors/issues/353.
+		market.PieceDealIndexEnabled = false
+		defer func() {
+			market.PieceDealIndexEnabled = true
+		}()
+
+		rt, actor, dealIds := setup(t)
+		actor.activateDeals(rt, sectorExpiry, provider, 0, dealIds[0])
+		rt.SetEpoch(startEpoch + 10)
+		actor.terminateDeals(rt, provider, dealIds[0])
+
+		var st market.State
+		rt.GetState(&st)
+		index, err := market.AsPieceDealIndex(adt.AsStore(rt), st.DealsByPiece)
+		require.NoError(t, err)
+		require.NoError(t, index.ForEachPiece(func(piece cid.Cid, id abi.DealID) error {
+			return fmt.Errorf("deal %d indexed by piece %v", id, piece)
+		}))
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "not indexed by piece", func() {
+			rt.Call(actor.GetDealsForPiece, &market.GetDealsForPieceParams{PieceCID: piece})
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestEscrowUnlockSchedule(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	t.Run("collateral and payments unlock by each deal's end epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId1 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		dealId2 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch+1, 0, sectorExpiry)
+		d1 := actor.getDealProposal(rt, dealId1)
+		d2 := actor.getDealProposal(rt, dealId2)
+
+		assert.Equal(t, []market.EscrowUnlock{
+			{Epoch: endEpoch, Amount: d1.ClientCollateral},
+			{Epoch: endEpoch + 1, Amount: d2.ClientCollateral},
+		}, actor.getEscrowUnlockSchedule(rt, client))
+		assert.Equal(t, []market.EscrowUnlock{
+			{Epoch: endEpoch, Amount: big.Add(d1.ProviderCollateral, d1.TotalStorageFee())},
+			{Epoch: endEpoch + 1, Amount: big.Add(d2.ProviderCollateral, d2.TotalStorageFee())},
+		}, actor.getEscrowUnlockSchedule(rt, provider))
+		assert.Empty(t, actor.getEscrowUnlockSchedule(rt, tutil.NewIDAddr(t, 1000)))
+		actor.checkState(rt)
+	})
+
+	t.Run("accrued payments may be settled at the current epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId1 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		dealId2 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch+1, 0, sectorExpiry)
+		d1 := actor.getDealProposal(rt, dealId1)
+		d2 := actor.getDealProposal(rt, dealId2)
+
+		current := rt.SetEpoch(startEpoch + 100)
+		accrued := big.Mul(big.NewInt(100), d1.StoragePricePerEpoch)
+		assert.Equal(t, []market.EscrowUnlock{
+			{Epoch: current, Amount: big.Mul(accrued, big.NewInt(2))},
+			{Epoch: endEpoch, Amount: big.Sub(big.Add(d1.ProviderCollateral, d1.TotalStorageFee()), accrued)},
+			{Epoch: endEpoch + 1, Amount: big.Sub(big.Add(d2.ProviderCollateral, d2.TotalStorageFee()), accrued)},
+		}, actor.getEscrowUnlockSchedule(rt, provider))
+
+		// settled payment is no longer scheduled
+		actor.settleDealPayments(rt, worker, dealId1)
+		assert.Equal(t, []market.EscrowUnlock{
+			{Epoch: current, Amount: accrued},
+			{Epoch: endEpoch, Amount: big.Sub(big.Add(d1.ProviderCollateral, d1.TotalStorageFee()), accrued)},
+			{Epoch: endEpoch + 1, Amount: big.Sub(big.Add(d2.ProviderCollateral, d2.TotalStorageFee()), accrued)},
+		}, actor.getEscrowUnlockSchedule(rt, provider))
+		actor.checkState(rt)
+	})
+
+	t.Run("funds of a slashed deal may be settled at the current epoch", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		current := rt.SetEpoch(processEpoch(t, dealId, startEpoch))
+		actor.cronTick(rt)
+		slashEpoch := rt.SetEpoch(current + 200)
 		actor.terminateDeals(rt, provider, dealId)
 
-		// next epoch for cron schedule is endEpoch + 300 ->
-		// setting epoch to higher than that will cause deal to be expired, payment will be made
-		// and deal will NOT be slashed
-		current = rt.SetEpoch(endEpoch + 300)
-		pay, slashed = actor.cronTickAndAssertBalances(rt, client, provider, current, dealId)
+		remaining := big.Mul(big.NewInt(int64(endEpoch-slashEpoch)), d.StoragePricePerEpoch)
+		assert.Equal(t, []market.EscrowUnlock{
+			{Epoch: slashEpoch, Amount: big.Add(d.ClientCollateral, remaining)},
+		}, actor.getEscrowUnlockSchedule(rt, client))
+		assert.Equal(t, []market.EscrowUnlock{
+			{Epoch: slashEpoch, Amount: big.Mul(big.NewInt(200), d.StoragePricePerEpoch)},
+		}, actor.getEscrowUnlockSchedule(rt, provider))
+		actor.checkState(rt)
+	})
 
-		require.EqualValues(t, big.Mul(duration, d.StoragePricePerEpoch), pay)
-		require.EqualValues(t, big.Zero(), slashed)
+	t.Run("pages through deals from a start deal ID", func(t *testing.T) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		dealId1 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		dealId2 := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch+1, 0, sectorExpiry)
+		d1 := actor.getDealProposal(rt, dealId1)
+		d2 := actor.getDealProposal(rt, dealId2)
 
-		// deal should be deleted as it should have expired
-		actor.assertDealDeleted(rt, dealId, d)
+		page := actor.getEscrowUnlockSchedulePage(rt, client, 0, 1)
+		assert.Equal(t, []market.EscrowUnlock{{Epoch: endEpoch, Amount: d1.ClientCollateral}}, page.Unlocks)
+		assert.True(t, page.More)
+		assert.Equal(t, dealId2, page.NextDealID)
+
+		page = actor.getEscrowUnlockSchedulePage(rt, client, page.NextDealID, 1)
+		assert.Equal(t, []market.EscrowUnlock{{Epoch: endEpoch + 1, Amount: d2.ClientCollateral}}, page.Unlocks)
+		assert.False(t, page.More)
+		actor.checkState(rt)
+	})
+}
+
+func TestAutoWithdraw(t *testing.T) {
+	owner := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	worker := tutil.NewIDAddr(t, 103)
+	client := tutil.NewIDAddr(t, 104)
+	providerRecipient := tutil.NewIDAddr(t, 105)
+	clientRecipient := tutil.NewIDAddr(t, 106)
+	mAddrs := &minerAddrs{owner, worker, provider, nil}
+
+	startEpoch := abi.ChainEpoch(50)
+	endEpoch := startEpoch + 200*builtin.EpochsInDay
+	sectorExpiry := endEpoch + 100
+
+	setup := func(t *testing.T) (*mock.Runtime, *marketActorTestHarness) {
+		rt, actor := basicMarketSetup(t, owner, provider, worker, client)
+		rt.SetAddressActorType(providerRecipient, builtin.AccountActorCodeID)
+		rt.SetAddressActorType(clientRecipient, builtin.AccountActorCodeID)
+		return rt, actor
+	}
+
+	t.Run("settlement sends released escrow to recipients", func(t *testing.T) {
+		rt, actor := setup(t)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		expectGetControlAddresses(rt, provider, owner, worker)
+		actor.setAutoWithdraw(rt, owner, provider, &providerRecipient)
+		actor.setAutoWithdraw(rt, client, client, &clientRecipient)
+		providerEscrow := actor.getEscrowBalance(rt, provider)
+		clientEscrow := actor.getEscrowBalance(rt, client)
+
+		// the provider's payment is withdrawn, while the client's payment leaves nothing released
+		rt.SetEpoch(startEpoch + 100)
+		pay := big.Mul(big.NewInt(100), d.StoragePricePerEpoch)
+		rt.ExpectSend(providerRecipient, builtin.MethodSend, nil, pay, nil, exitcode.Ok)
+		actor.settleDealPayments(rt, worker, dealId)
+		assert.Equal(t, providerEscrow, actor.getEscrowBalance(rt, provider))
+		assert.Equal(t, big.Sub(clientEscrow, pay), actor.getEscrowBalance(rt, client))
+
+		// completion releases both parties' collateral
+		rt.SetEpoch(endEpoch + 1)
+		finalPay := big.Mul(big.NewInt(int64(endEpoch-startEpoch-100)), d.StoragePricePerEpoch)
+		rt.ExpectSend(clientRecipient, builtin.MethodSend, nil, d.ClientCollateral, nil, exitcode.Ok)
+		rt.ExpectSend(providerRecipient, builtin.MethodSend, nil, big.Add(finalPay, d.ProviderCollateral), nil, exitcode.Ok)
+		ret := actor.settleDealPayments(rt, worker, dealId)
+		assert.True(t, ret.Settlements[0].Completed)
+		assert.Equal(t, big.Sub(providerEscrow, d.ProviderCollateral).Int64(), actor.getEscrowBalance(rt, provider).Int64())
+		assert.Equal(t, big.Sub(clientEscrow, big.Sum(pay, finalPay, d.ClientCollateral)).Int64(), actor.getEscrowBalance(rt, client).Int64())
+		actor.checkState(rt)
+	})
+
+	t.Run("unlocked funds deposited before settlement are not withdrawn", func(t *testing.T) {
+		rt, actor := setup(t)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+		actor.addProviderFunds(rt, abi.NewTokenAmount(1000), mAddrs)
+
+		expectGetControlAddresses(rt, provider, owner, worker)
+		actor.setAutoWithdraw(rt, owner, provider, &providerRecipient)
+
+		rt.SetEpoch(startEpoch + 100)
+		pay := big.Mul(big.NewInt(100), d.StoragePricePerEpoch)
+		rt.ExpectSend(providerRecipient, builtin.MethodSend, nil, pay, nil, exitcode.Ok)
+		actor.settleDealPayments(rt, worker, dealId)
+		assert.Equal(t, big.Add(d.ProviderCollateral, abi.NewTokenAmount(1000)), actor.getEscrowBalance(rt, provider))
+		actor.checkState(rt)
+	})
+
+	t.Run("clearing the recipient stops withdrawals", func(t *testing.T) {
+		rt, actor := setup(t)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+
+		expectGetControlAddresses(rt, provider, owner, worker)
+		actor.setAutoWithdraw(rt, owner, provider, &providerRecipient)
+		expectGetControlAddresses(rt, provider, owner, worker)
+		actor.setAutoWithdraw(rt, owner, provider, nil)
+		// clearing an unset recipient is a no-op
+		actor.setAutoWithdraw(rt, client, client, nil)
+
+		rt.SetEpoch(startEpoch + 100)
+		actor.settleDealPayments(rt, worker, dealId)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if caller is not the owner or client", func(t *testing.T) {
+		rt, actor := setup(t)
+
+		expectGetControlAddresses(rt, provider, owner, worker)
+		rt.SetCaller(worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(owner)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.SetAutoWithdraw, &market.SetAutoWithdrawParams{ProviderOrClientAddress: provider, Recipient: &providerRecipient})
+		})
+		rt.Verify()
+
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerAddr(client)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.SetAutoWithdraw, &market.SetAutoWithdrawParams{ProviderOrClientAddress: client, Recipient: &clientRecipient})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+	t.Run("a failed withdrawal returns the released escrow to the party", func(t *testing.T) {
+		rt, actor := setup(t)
+		dealId := actor.publishAndActivateDeal(rt, client, mAddrs, startEpoch, endEpoch, 0, sectorExpiry)
+		d := actor.getDealProposal(rt, dealId)
+
+		expectGetControlAddresses(rt, provider, owner, worker)
+		actor.setAutoWithdraw(rt, owner, provider, &providerRecipient)
+		actor.setAutoWithdraw(rt, client, client, &clientRecipient)
+		providerEscrow := actor.getEscrowBalance(rt, provider)
+		clientEscrow := actor.getEscrowBalance(rt, client)
+
+		// the send to the client's recipient fails, while the provider's succeeds
+		rt.SetEpoch(endEpoch + 1)
+		totalPay := big.Mul(big.NewInt(int64(endEpoch-startEpoch)), d.StoragePricePerEpoch)
+		rt.ExpectSend(clientRecipient, builtin.MethodSend, nil, d.ClientCollateral, nil, exitcode.SysErrInsufficientFunds)
+		rt.ExpectSend(providerRecipient, builtin.MethodSend, nil, big.Add(totalPay, d.ProviderCollateral), nil, exitcode.Ok)
+		ret := actor.settleDealPayments(rt, worker, dealId)
+		assert.True(t, ret.Settlements[0].Completed)
+		assert.Equal(t, big.Sub(clientEscrow, totalPay).Int64(), actor.getEscrowBalance(rt, client).Int64())
+		assert.Equal(t, big.Sub(providerEscrow, d.ProviderCollateral).Int64(), actor.getEscrowBalance(rt, provider).Int64())
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if recipient is not an account", func(t *testing.T) {
+		rt, actor := setup(t)
+		multisig := tutil.NewIDAddr(t, 107)
+		rt.SetAddressActorType(multisig, builtin.MultisigActorCodeID)
+
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(client)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "must be an account", func() {
+			rt.Call(actor.SetAutoWithdraw, &market.SetAutoWithdrawParams{ProviderOrClientAddress: client, Recipient: &multisig})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if recipient does not exist", func(t *testing.T) {
+		rt, actor := setup(t)
+		unknown := tutil.NewBLSAddr(t, 1)
+
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(client)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.SetAutoWithdraw, &market.SetAutoWithdrawParams{ProviderOrClientAddress: client, Recipient: &unknown})
+		})
+		rt.Verify()
 		actor.checkState(rt)
 	})
 }
@@ -2797,6 +4260,9 @@ type marketActorTestHarness struct {
 
 	networkQAPower       abi.StoragePower
 	networkBaselinePower abi.StoragePower
+
+	// The last verified registry allocation made for a published verified deal.
+	lastAllocationID verifreg.AllocationID
 }
 
 func (h *marketActorTestHarness) constructAndVerify(rt *mock.Runtime) {
@@ -2821,6 +4287,20 @@ func (h *marketActorTestHarness) verifyDealsForActivation(rt *mock.Runtime, prov
 	return val
 }
 
+func (h *marketActorTestHarness) onMinerSectorsPreCommit(rt *mock.Runtime, provider address.Address, dealIDs ...abi.DealID) {
+	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+	rt.Call(h.OnMinerSectorsPreCommit, &market.OnMinerSectorsPreCommitParams{DealIDs: dealIDs})
+	rt.Verify()
+}
+
+func (h *marketActorTestHarness) onMinerPreCommitsRemoved(rt *mock.Runtime, provider address.Address, dealIDs ...abi.DealID) {
+	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+	rt.Call(h.OnMinerPreCommitsRemoved, &market.OnMinerPreCommitsRemovedParams{DealIDs: dealIDs})
+	rt.Verify()
+}
+
 type minerAddrs struct {
 	owner    address.Address
 	worker   address.Address
@@ -2916,6 +4396,23 @@ func (h *marketActorTestHarness) cronTickNoChange(rt *mock.Runtime, client, prov
 // if this is not the first crontick, the `desiredNextEpoch` param is ignored.
 func (h *marketActorTestHarness) cronTickAndAssertBalances(rt *mock.Runtime, client, provider address.Address,
 	currentEpoch abi.ChainEpoch, dealId abi.DealID) (payment abi.TokenAmount, amountSlashed abi.TokenAmount) {
+	return h.processAndAssertBalances(rt, client, provider, currentEpoch, dealId, func() {
+		h.cronTick(rt)
+	})
+}
+
+// Settles payment for a deal, as the provider's worker, and checks the resulting balances.
+func (h *marketActorTestHarness) settleAndAssertBalances(rt *mock.Runtime, client, provider, worker address.Address,
+	currentEpoch abi.ChainEpoch, dealId abi.DealID) (payment abi.TokenAmount, amountSlashed abi.TokenAmount) {
+	return h.processAndAssertBalances(rt, client, provider, currentEpoch, dealId, func() {
+		ret := h.settleDealPayments(rt, worker, dealId)
+		require.Len(h.t, ret.Settlements, 1)
+		require.Empty(h.t, ret.Errors)
+	})
+}
+
+func (h *marketActorTestHarness) processAndAssertBalances(rt *mock.Runtime, client, provider address.Address,
+	currentEpoch abi.ChainEpoch, dealId abi.DealID, process func()) (payment abi.TokenAmount, amountSlashed abi.TokenAmount) {
 	// fetch current client and provider escrow balances
 	cLocked := h.getLockedBalance(rt, client)
 	cEscrow := h.getEscrowBalance(rt, client)
@@ -2962,7 +4459,7 @@ func (h *marketActorTestHarness) cronTickAndAssertBalances(rt *mock.Runtime, cli
 		updatedProviderLocked = big.Zero()
 	}
 
-	h.cronTick(rt)
+	process()
 
 	require.EqualValues(h.t, updatedClientEscrow, h.getEscrowBalance(rt, client))
 	require.EqualValues(h.t, updatedClientLocked, h.getLockedBalance(rt, client))
@@ -2980,8 +4477,82 @@ func (h *marketActorTestHarness) cronTick(rt *mock.Runtime) {
 	rt.Verify()
 }
 
+func (h *marketActorTestHarness) settleDealPayments(rt *mock.Runtime, caller address.Address, dealIDs ...abi.DealID) *market.SettleDealPaymentsReturn {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.SettleDealPayments, &market.SettleDealPaymentsParams{DealIDs: dealIDs}).(*market.SettleDealPaymentsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) cancelDeals(rt *mock.Runtime, client address.Address, dealIDs ...abi.DealID) {
+	rt.SetCaller(client, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerType(builtin.CallerTypesSignable...)
+	rt.Call(h.CancelDeals, &market.CancelDealsParams{DealIDs: dealIDs})
+	rt.Verify()
+}
+
+func (h *marketActorTestHarness) extendDeals(rt *mock.Runtime, provider address.Address, sectorExpiry abi.ChainEpoch,
+	extensions ...market.ClientDealExtension) *market.ExtendDealsReturn {
+	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+	for _, ext := range extensions {
+		rt.ExpectVerifySignature(ext.ClientSignature, h.getDealProposal(rt, ext.Extension.DealID).Client, mustCbor(&ext.Extension), nil)
+	}
+	for _, ext := range extensions {
+		if d := h.getDealProposal(rt, ext.Extension.DealID); d.VerifiedDeal {
+			rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.UseBytes, &verifreg.UseBytesParams{
+				Address:  d.Client,
+				DealSize: market.DataCapForDealExtension(d.PieceSize, d.StartEpoch, d.EndEpoch, ext.Extension.NewEndEpoch),
+			}, big.Zero(), nil, exitcode.Ok)
+		}
+	}
+	ret := rt.Call(h.ExtendDeals, &market.ExtendDealsParams{SectorExpiry: sectorExpiry, Extensions: extensions}).(*market.ExtendDealsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) setAutoWithdraw(rt *mock.Runtime, caller, holder address.Address, recipient *address.Address) {
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(caller)
+	rt.Call(h.SetAutoWithdraw, &market.SetAutoWithdrawParams{ProviderOrClientAddress: holder, Recipient: recipient})
+	rt.Verify()
+}
+
+func (h *marketActorTestHarness) getEscrowUnlockSchedule(rt *mock.Runtime, addr address.Address) []market.EscrowUnlock {
+	ret := h.getEscrowUnlockSchedulePage(rt, addr, 0, 0)
+	assert.False(h.t, ret.More)
+	return ret.Unlocks
+}
+
+func (h *marketActorTestHarness) getEscrowUnlockSchedulePage(rt *mock.Runtime, addr address.Address, start abi.DealID, limit uint64) *market.GetEscrowUnlockScheduleReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetEscrowUnlockSchedule, &market.GetEscrowUnlockScheduleParams{
+		Address:     addr,
+		StartDealID: start,
+		Limit:       limit,
+	}).(*market.GetEscrowUnlockScheduleReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) getDealsForPiece(rt *mock.Runtime, piece cid.Cid) []market.PieceDeal {
+	ret := h.getDealsForPiecePage(rt, piece, 0, 0)
+	assert.False(h.t, ret.More)
+	return ret.Deals
+}
+
+func (h *marketActorTestHarness) getDealsForPiecePage(rt *mock.Runtime, piece cid.Cid, start abi.DealID, limit uint64) *market.GetDealsForPieceReturn {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.GetDealsForPiece, &market.GetDealsForPieceParams{PieceCID: piece, StartDealID: start, Limit: limit}).(*market.GetDealsForPieceReturn)
+	rt.Verify()
+	return ret
+}
+
 type publishDealReq struct {
 	deal market.DealProposal
+	// A client allocation for the deal to claim, if any.
+	clientAllocationID verifreg.AllocationID
 }
 
 func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *minerAddrs, publishDealReqs ...publishDealReq) []abi.DealID {
@@ -2999,7 +4570,7 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 
 	var params market.PublishStorageDealsParams
 
-	for _, pdr := range publishDealReqs {
+	for i, pdr := range publishDealReqs {
 		//  create a client proposal with a valid signature
 		buf := bytes.Buffer{}
 		require.NoError(h.t, pdr.deal.MarshalCBOR(&buf), "failed to marshal deal proposal")
@@ -3009,7 +4580,29 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 
 		// expect a call to verify the above signature
 		rt.ExpectVerifySignature(sig, pdr.deal.Client, buf.Bytes(), nil)
-		if pdr.deal.VerifiedDeal {
+		if pdr.clientAllocationID != verifreg.NoAllocationID {
+			if params.AllocationIDs == nil {
+				params.AllocationIDs = make([]verifreg.AllocationID, len(publishDealReqs))
+			}
+			params.AllocationIDs[i] = pdr.clientAllocationID
+		}
+		if pdr.deal.VerifiedDeal && rt.NetworkVersion() >= market.DealAllocationClaimsVersion {
+			param := &verifreg.AllocateDealDataCapParams{
+				Client: pdr.deal.Client,
+				Allocation: verifreg.AllocationRequest{
+					Provider:   pdr.deal.Provider,
+					Data:       pdr.deal.PieceCID,
+					Size:       pdr.deal.PieceSize,
+					TermMin:    pdr.deal.Duration(),
+					TermMax:    pdr.deal.EndEpoch - rt.Epoch(),
+					Expiration: pdr.deal.StartEpoch,
+				},
+				ClientAllocationID: pdr.clientAllocationID,
+			}
+			h.lastAllocationID++
+			rt.ExpectSend(builtin.VerifiedRegistryActorAddr, builtin.MethodsVerifiedRegistry.AllocateDealDataCap, param, abi.NewTokenAmount(0),
+				&verifreg.AllocateDealDataCapReturn{AllocationID: h.lastAllocationID}, exitcode.Ok)
+		} else if pdr.deal.VerifiedDeal {
 			param := &verifreg.UseBytesParams{
 				Address:  pdr.deal.Client,
 				DealSize: big.NewIntUnsigned(uint64(pdr.deal.PieceSize)),
@@ -3025,6 +4618,7 @@ func (h *marketActorTestHarness) publishDeals(rt *mock.Runtime, minerAddrs *mine
 	resp, ok := ret.(*market.PublishStorageDealsReturn)
 	require.True(h.t, ok, "unexpected type returned from call to PublishStorageDeals")
 	require.Len(h.t, resp.IDs, len(publishDealReqs))
+	require.Empty(h.t, resp.DealErrors)
 
 	// assert state after publishing the deals
 	dealIds := resp.IDs
@@ -3062,6 +4656,19 @@ func (h *marketActorTestHarness) assertDealsNotActivated(rt *mock.Runtime, epoch
 	}
 }
 
+// Returns the verified registry allocation recorded for a published verified deal, if not yet claimed.
+func (h *marketActorTestHarness) dealAllocation(rt *mock.Runtime, dealID abi.DealID) (verifreg.AllocationID, bool) {
+	var st market.State
+	rt.GetState(&st)
+
+	allocationIDs, err := adt.AsMap(adt.AsStore(rt), st.DealAllocationIds, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+	var allocationID cbg.CborInt
+	found, err := allocationIDs.Get(abi.UIntKey(uint64(dealID)), &allocationID)
+	require.NoError(h.t, err)
+	return verifreg.AllocationID(allocationID), found
+}
+
 func (h *marketActorTestHarness) activateDeals(rt *mock.Runtime, sectorExpiry abi.ChainEpoch, provider address.Address, currentEpoch abi.ChainEpoch, dealIDs ...abi.DealID) {
 	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
 	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
@@ -3079,6 +4686,25 @@ func (h *marketActorTestHarness) activateDeals(rt *mock.Runtime, sectorExpiry ab
 	}
 }
 
+func (h *marketActorTestHarness) batchActivateDeals(rt *mock.Runtime, provider address.Address, computeCID bool,
+	sectors ...market.SectorDealsActivation) *market.BatchActivateDealsReturn {
+	rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+	rt.ExpectValidateCallerType(builtin.StorageMinerActorCodeID)
+
+	params := &market.BatchActivateDealsParams{Sectors: sectors, ComputeCID: computeCID}
+
+	ret := rt.Call(h.BatchActivateDeals, params).(*market.BatchActivateDealsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *marketActorTestHarness) assertActivatedSectors(ret *market.BatchActivateDealsReturn, expected ...uint64) {
+	activated, err := ret.ActivatedSectors.All(1 << 20)
+	require.NoError(h.t, err)
+	assert.Equal(h.t, expected, activated)
+	assert.Equal(h.t, len(expected), len(ret.Sectors))
+}
+
 func (h *marketActorTestHarness) getDealProposal(rt *mock.Runtime, dealID abi.DealID) *market.DealProposal {
 	var st market.State
 	rt.GetState(&st)
@@ -3286,6 +4912,18 @@ func (h *marketActorTestHarness) generateDealAndAddFunds(rt *mock.Runtime, clien
 	return deal
 }
 
+// Generates a verified deal within the bounds from network version 17, for a piece of the minimum verified deal size.
+func (h *marketActorTestHarness) generateVerifiedDealV17AndAddFunds(rt *mock.Runtime, client address.Address, minerAddrs *minerAddrs,
+	startEpoch, endEpoch abi.ChainEpoch) market.DealProposal {
+	deal := generateDealProposal(client, minerAddrs.provider, startEpoch, endEpoch)
+	deal.VerifiedDeal = true
+	deal.PieceSize = abi.PaddedPieceSize(verifreg.MinVerifiedDealSize.Uint64())
+	deal.ClientCollateral, _ = market.DealClientCollateralBoundsV17(deal.PieceSize, deal.Duration(), deal.StoragePricePerEpoch)
+	h.addProviderFunds(rt, deal.ProviderCollateral, minerAddrs)
+	h.addParticipantFunds(rt, client, deal.ClientBalanceRequirement())
+	return deal
+}
+
 func (h *marketActorTestHarness) generateDealWithCollateralAndAddFunds(rt *mock.Runtime, client address.Address,
 	minerAddrs *minerAddrs, providerCollateral, clientCollateral abi.TokenAmount, startEpoch, endEpoch abi.ChainEpoch) market.DealProposal {
 	deal := generateDealProposalWithCollateral(client, minerAddrs.provider, providerCollateral, clientCollateral,
@@ -3338,7 +4976,9 @@ func basicMarketSetup(t *testing.T, owner, provider, worker, client address.Addr
 		WithActorType(owner, builtin.AccountActorCodeID).
 		WithActorType(worker, builtin.AccountActorCodeID).
 		WithActorType(provider, builtin.StorageMinerActorCodeID).
-		WithActorType(client, builtin.AccountActorCodeID)
+		WithActorType(client, builtin.AccountActorCodeID).
+		// Deal fixtures satisfy the bounds in effect before the piece-size-aware bounds of network version 17.
+		WithNetworkVersion(network.Version16)
 
 	rt := builder.Build(t)
 	power := abi.NewStoragePower(1 << 50)
@@ -3405,6 +5045,20 @@ func expectQueryNetworkInfo(rt *mock.Runtime, h *marketActorTestHarness) {
 	)
 }
 
+func (h *marketActorTestHarness) dealOpScheduledAt(rt *mock.Runtime, dealID abi.DealID, epoch abi.ChainEpoch) bool {
+	var st market.State
+	rt.GetState(&st)
+	dobe, err := market.AsSetMultimap(rt.AdtStore(), st.DealOpsByEpoch, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+	scheduled := false
+	err = dobe.ForEach(epoch, func(id abi.DealID) error {
+		scheduled = scheduled || id == dealID
+		return nil
+	})
+	require.NoError(h.t, err)
+	return scheduled
+}
+
 func processEpoch(t *testing.T, id abi.DealID, startEpoch abi.ChainEpoch) abi.ChainEpoch {
 	return market.GenRandNextEpoch(startEpoch, id)
 }
@@ -0,0 +1,76 @@
+package market
+
+import (
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// PartyDealIndex is a secondary index of deals by their client and provider, a HAMT[address]Set[DealID].
+// A deal is indexed from publication until its proposal is removed from state.
+type PartyDealIndex struct {
+	mm *SetMultimap
+}
+
+// Interprets a store as a party deal index with root `r`.
+func AsPartyDealIndex(s adt.Store, r cid.Cid) (*PartyDealIndex, error) {
+	mm, err := AsSetMultimap(s, r, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &PartyDealIndex{mm}, nil
+}
+
+// Creates a new index backed by an empty HAMT.
+func MakeEmptyPartyDealIndex(s adt.Store) (*PartyDealIndex, error) {
+	mm, err := MakeEmptySetMultimap(s, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &PartyDealIndex{mm}, nil
+}
+
+// Returns the root cid of the underlying HAMT.
+func (pi *PartyDealIndex) Root() (cid.Cid, error) {
+	return pi.mm.Root()
+}
+
+// Indexes a deal under both its client and provider.
+func (pi *PartyDealIndex) Put(deal *DealProposal, dealID abi.DealID) error {
+	if err := pi.mm.put(abi.AddrKey(deal.Client), dealID); err != nil {
+		return err
+	}
+	return pi.mm.put(abi.AddrKey(deal.Provider), dealID)
+}
+
+// Removes a deal from the index under both its client and provider, if present.
+func (pi *PartyDealIndex) Remove(deal *DealProposal, dealID abi.DealID) error {
+	if err := pi.mm.remove(abi.AddrKey(deal.Client), dealID); err != nil {
+		return err
+	}
+	return pi.mm.remove(abi.AddrKey(deal.Provider), dealID)
+}
+
+// Iterates the deals of a client or provider, iteration halts if the function returns an error.
+func (pi *PartyDealIndex) ForEach(party addr.Address, fn func(id abi.DealID) error) error {
+	return pi.mm.forEach(abi.AddrKey(party), fn)
+}
+
+// Iterates every indexed deal with its party, iteration halts if the function returns an error.
+func (pi *PartyDealIndex) ForEachParty(fn func(party addr.Address, id abi.DealID) error) error {
+	var setRoot cbg.CborCid
+	return pi.mm.mp.ForEach(&setRoot, func(k string) error {
+		party, err := addr.NewFromBytes([]byte(k))
+		if err != nil {
+			return xerrors.Errorf("failed to parse party key %v: %w", k, err)
+		}
+		return pi.ForEach(party, func(id abi.DealID) error {
+			return fn(party, id)
+		})
+	})
+}
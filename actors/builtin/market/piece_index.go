@@ -0,0 +1,147 @@
+package market
+
+import (
+	"github.com/filecoin-project/go-state-types/abi"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// Bitwidth of the AMT of deals storing each piece.
+const PieceDealsAmtBitwidth = 5
+
+// PieceDealIndex is a secondary index of deals by the piece they store, a HAMT[PieceCID]AMT[DealID].
+// The deals of a piece are held in an AMT keyed by deal ID, so that they can be iterated in order from any deal.
+// A deal is indexed from publication until it is terminated or removed from state, if PieceDealIndexEnabled.
+type PieceDealIndex struct {
+	mp    *adt.Map
+	store adt.Store
+}
+
+// Interprets a store as a piece deal index with root `r`.
+func AsPieceDealIndex(s adt.Store, r cid.Cid) (*PieceDealIndex, error) {
+	m, err := adt.AsMap(s, r, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &PieceDealIndex{mp: m, store: s}, nil
+}
+
+// Creates a new index backed by an empty HAMT.
+func MakeEmptyPieceDealIndex(s adt.Store) (*PieceDealIndex, error) {
+	m, err := adt.MakeEmptyMap(s, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &PieceDealIndex{mp: m, store: s}, nil
+}
+
+// Returns the root cid of the underlying HAMT.
+func (pi *PieceDealIndex) Root() (cid.Cid, error) {
+	return pi.mp.Root()
+}
+
+func (pi *PieceDealIndex) Put(piece cid.Cid, dealID abi.DealID) error {
+	deals, found, err := pi.get(piece)
+	if err != nil {
+		return err
+	}
+	if !found {
+		deals, err = adt.MakeEmptyArray(pi.store, PieceDealsAmtBitwidth)
+		if err != nil {
+			return err
+		}
+	}
+	present := cbg.CborBool(true)
+	if err = deals.Set(uint64(dealID), &present); err != nil {
+		return xerrors.Errorf("failed to add deal %d to piece %v: %w", dealID, piece, err)
+	}
+	return pi.put(piece, deals)
+}
+
+// Removes a deal from the index, if present.
+// The piece is removed if it has no remaining deals.
+func (pi *PieceDealIndex) Remove(piece cid.Cid, dealID abi.DealID) error {
+	deals, found, err := pi.get(piece)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	if _, err = deals.TryDelete(uint64(dealID)); err != nil {
+		return xerrors.Errorf("failed to remove deal %d from piece %v: %w", dealID, piece, err)
+	}
+	if deals.Length() == 0 {
+		if _, err := pi.mp.TryDelete(abi.CidKey(piece)); err != nil {
+			return xerrors.Errorf("failed to delete piece %v: %w", piece, err)
+		}
+		return nil
+	}
+	return pi.put(piece, deals)
+}
+
+// Iterates the deals storing a piece in ascending order of deal ID, iteration halts if the function returns an error.
+func (pi *PieceDealIndex) ForEach(piece cid.Cid, fn func(id abi.DealID) error) error {
+	return pi.ForEachFrom(piece, 0, fn)
+}
+
+// Iterates the deals storing a piece in ascending order of deal ID, beginning from deal ID `start`.
+// Iteration halts if the function returns an error.
+func (pi *PieceDealIndex) ForEachFrom(piece cid.Cid, start abi.DealID, fn func(id abi.DealID) error) error {
+	deals, found, err := pi.get(piece)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	return deals.ForEachFrom(uint64(start), nil, func(i int64) error {
+		return fn(abi.DealID(i))
+	})
+}
+
+// Iterates every indexed deal with its piece, iteration halts if the function returns an error.
+func (pi *PieceDealIndex) ForEachPiece(fn func(piece cid.Cid, id abi.DealID) error) error {
+	var dealsRoot cbg.CborCid
+	return pi.mp.ForEach(&dealsRoot, func(k string) error {
+		piece, err := cid.Cast([]byte(k))
+		if err != nil {
+			return xerrors.Errorf("failed to parse piece key %v: %w", k, err)
+		}
+		return pi.ForEach(piece, func(id abi.DealID) error {
+			return fn(piece, id)
+		})
+	})
+}
+
+func (pi *PieceDealIndex) get(piece cid.Cid) (*adt.Array, bool, error) {
+	var dealsRoot cbg.CborCid
+	found, err := pi.mp.Get(abi.CidKey(piece), &dealsRoot)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load deals for piece %v: %w", piece, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	deals, err := adt.AsArray(pi.store, cid.Cid(dealsRoot), PieceDealsAmtBitwidth)
+	if err != nil {
+		return nil, false, err
+	}
+	return deals, true, nil
+}
+
+func (pi *PieceDealIndex) put(piece cid.Cid, deals *adt.Array) error {
+	root, err := deals.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush deals for piece %v: %w", piece, err)
+	}
+	dealsRoot := cbg.CborCid(root)
+	if err = pi.mp.Put(abi.CidKey(piece), &dealsRoot); err != nil {
+		return xerrors.Errorf("failed to store deals for piece %v: %w", piece, err)
+	}
+	return nil
+}
@@ -3,8 +3,10 @@ package market
 import (
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/network"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
 )
 
 // The number of epochs between payment and other state processing for deals.
@@ -26,6 +28,17 @@ var DealMaxDuration = abi.ChainEpoch(540 * builtin.EpochsInDay) // PARAM_SPEC
 // DealMaxLabelSize is the maximum size of a deal label.
 const DealMaxLabelSize = 256
 
+// Whether the market indexes deals by piece CID, for GetDealsForPiece.
+// The index costs a write when each deal is published and when it is terminated or removed.
+// It must be fixed for the life of a network, since an index that is not maintained throughout is incomplete.
+var PieceDealIndexEnabled = true // PARAM_SPEC
+
+// Maximum number of deals returned by a single GetDealsForPiece query.
+const GetDealsForPieceMax = 256
+
+// Maximum number of deals accounted for by a single GetEscrowUnlockSchedule query.
+const GetEscrowUnlockScheduleMax = 256
+
 // Bounds (inclusive) on deal duration
 func DealDurationBounds(_ abi.PaddedPieceSize) (min abi.ChainEpoch, max abi.ChainEpoch) {
 	return DealMinDuration, DealMaxDuration
@@ -56,11 +69,147 @@ func DealClientCollateralBounds(_ abi.PaddedPieceSize, _ abi.ChainEpoch) (min ab
 	return abi.NewTokenAmount(0), builtin.TotalFilecoin
 }
 
+// Network version from which verified deals allocate their clients' DataCap to their provider and piece when
+// published, and claim the allocation when activated.
+const DealAllocationClaimsVersion = network.Version17
+
+// Network version from which deal bounds account for piece size, sector lifetime and the expected storage fee.
+const DealBoundsV17Version = network.Version17
+
+// Maximum expiration of a sector from the epoch it is pre-committed, mirroring miner.MaxSectorExpirationExtension.
+var DealMaxSectorCommitment = abi.ChainEpoch(540 * builtin.EpochsInDay) // PARAM_SPEC
+
+// Minimum delay between a sector's pre-commitment and the activation of its deals, mirroring miner.PreCommitChallengeDelay.
+var DealMinSectorActivationDelay = abi.ChainEpoch(150) // PARAM_SPEC
+
+// Maximum deal duration from network version 17, the longest a deal can be stored by a sector that is
+// pre-committed, proven and activated no later than the deal's start epoch.
+var DealMaxDurationV17 = DealMaxSectorCommitment - DealMinSectorActivationDelay // PARAM_SPEC
+
+// Minimum duration from network version 17 of a deal storing a piece smaller than DealMinPricedPieceSize.
+// Such a deal pays at least DealMinTotalPrice whatever its duration.
+var DealMinDurationTinyPiece = abi.ChainEpoch(30 * builtin.EpochsInDay) // PARAM_SPEC
+
+// Pieces smaller than this size are charged at least DealMinTotalPrice from network version 17.
+var DealMinPricedPieceSize = abi.PaddedPieceSize(1 << 20) // PARAM_SPEC
+
+// Minimum total storage fee for a deal storing a piece smaller than DealMinPricedPieceSize.
+var DealMinTotalPrice = abi.NewTokenAmount(1_000_000) // PARAM_SPEC
+
+// Minimum client collateral from network version 17, as a fraction of the deal's total storage fee.
+var DealClientCollateralFeeShare = builtin.BigFrac{
+	Numerator:   big.NewInt(1), // PARAM_SPEC
+	Denominator: big.NewInt(100),
+}
+
+// Bounds (inclusive) on deal duration from network version 17.
+// A deal for a tiny piece may be shorter, since it pays a minimum total price.
+func DealDurationBoundsV17(pieceSize abi.PaddedPieceSize) (min abi.ChainEpoch, max abi.ChainEpoch) {
+	if pieceSize < DealMinPricedPieceSize {
+		return DealMinDurationTinyPiece, DealMaxDurationV17
+	}
+	return DealMinDuration, DealMaxDurationV17
+}
+
+// Bounds (inclusive) on deal price per epoch from network version 17.
+// A deal for a tiny piece must pay at least a minimum total price over its duration.
+func DealPricePerEpochBoundsV17(pieceSize abi.PaddedPieceSize, duration abi.ChainEpoch) (min abi.TokenAmount, max abi.TokenAmount) {
+	if pieceSize >= DealMinPricedPieceSize || duration <= 0 {
+		return abi.NewTokenAmount(0), builtin.TotalFilecoin
+	}
+	// Round up so that the total price is no less than the minimum.
+	durationBig := big.NewInt(int64(duration))
+	min = big.Div(big.Add(DealMinTotalPrice, big.Sub(durationBig, big.NewInt(1))), durationBig)
+	return min, builtin.TotalFilecoin
+}
+
+// Bounds (inclusive) on deal client collateral from network version 17.
+// The client must lock collateral of at least a share of the deal's total storage fee.
+func DealClientCollateralBoundsV17(_ abi.PaddedPieceSize, duration abi.ChainEpoch, pricePerEpoch abi.TokenAmount) (min abi.TokenAmount, max abi.TokenAmount) {
+	totalFee := big.Mul(big.NewInt(int64(duration)), pricePerEpoch)
+	min = big.Div(big.Mul(totalFee, DealClientCollateralFeeShare.Numerator), DealClientCollateralFeeShare.Denominator)
+	return big.Max(min, big.Zero()), builtin.TotalFilecoin
+}
+
+func dealDurationBounds(nv network.Version, pieceSize abi.PaddedPieceSize) (min abi.ChainEpoch, max abi.ChainEpoch) {
+	if nv >= DealBoundsV17Version {
+		return DealDurationBoundsV17(pieceSize)
+	}
+	return DealDurationBounds(pieceSize)
+}
+
+func dealPricePerEpochBounds(nv network.Version, pieceSize abi.PaddedPieceSize, duration abi.ChainEpoch) (min abi.TokenAmount, max abi.TokenAmount) {
+	if nv >= DealBoundsV17Version {
+		return DealPricePerEpochBoundsV17(pieceSize, duration)
+	}
+	return DealPricePerEpochBounds(pieceSize, duration)
+}
+
+func dealClientCollateralBounds(nv network.Version, pieceSize abi.PaddedPieceSize, duration abi.ChainEpoch,
+	pricePerEpoch abi.TokenAmount) (min abi.TokenAmount, max abi.TokenAmount) {
+	if nv >= DealBoundsV17Version {
+		return DealClientCollateralBoundsV17(pieceSize, duration, pricePerEpoch)
+	}
+	return DealClientCollateralBounds(pieceSize, duration)
+}
+
 // Penalty to provider deal collateral if the deadline expires before sector commitment.
 func CollateralPenaltyForDealActivationMissed(providerCollateral abi.TokenAmount) abi.TokenAmount {
 	return providerCollateral
 }
 
+// Minimum share of a deal's storage fee and provider collateral that the client forfeits to the provider
+// when it cancels the deal.
+var DealCancellationPenaltyMinShare = builtin.BigFrac{
+	Numerator:   big.NewInt(1), // PARAM_SPEC
+	Denominator: big.NewInt(10),
+}
+
+// Penalty to the client if it cancels a deal before activation, paid to the provider, which may already
+// have committed resources to the deal.
+// The penalty is the client's collateral, but no less than a share of the storage fee and provider collateral,
+// so that a deal without client collateral cannot be cancelled for free. It never exceeds the funds the
+// client locked for the deal.
+func DealCancellationPenalty(totalStorageFee, clientCollateral, providerCollateral abi.TokenAmount) abi.TokenAmount {
+	minPenalty := big.Div(big.Mul(big.Add(totalStorageFee, providerCollateral), DealCancellationPenaltyMinShare.Numerator),
+		DealCancellationPenaltyMinShare.Denominator)
+	return big.Min(big.Max(clientCollateral, minPenalty), big.Add(totalStorageFee, clientCollateral))
+}
+
+// Fraction of a verified deal's unused datacap that is restored to the client when the provider terminates the deal early.
+// Applies only to deals which spent their client's datacap directly, since the datacap of a claimed allocation
+// is held by the claim.
+var DealTerminationDataCapRefund = builtin.BigFrac{
+	Numerator:   big.NewInt(1), // PARAM_SPEC
+	Denominator: big.NewInt(1),
+}
+
+// Datacap restored to the client of a verified deal terminated by its provider at terminationEpoch.
+// The refund is pro-rated by the portion of the deal's term remaining at termination.
+func DataCapRefundForDealTermination(pieceSize abi.PaddedPieceSize, startEpoch, endEpoch, terminationEpoch abi.ChainEpoch) abi.StoragePower {
+	duration := endEpoch - startEpoch
+	remaining := endEpoch - terminationEpoch
+	if remaining > duration {
+		remaining = duration
+	}
+	if remaining <= 0 {
+		return big.Zero()
+	}
+	num := big.Mul(big.Mul(big.NewIntUnsigned(uint64(pieceSize)), big.NewInt(int64(remaining))), DealTerminationDataCapRefund.Numerator)
+	denom := big.Mul(big.NewInt(int64(duration)), DealTerminationDataCapRefund.Denominator)
+	return big.Div(num, denom)
+}
+
+// Datacap consumed from the client of a verified deal to extend its term from endEpoch to newEndEpoch.
+// The charge is pro-rated by the added term relative to the deal's existing term, rounded up, and is at least
+// the minimum verified deal size the registry accepts.
+func DataCapForDealExtension(pieceSize abi.PaddedPieceSize, startEpoch, endEpoch, newEndEpoch abi.ChainEpoch) abi.StoragePower {
+	num := big.Mul(big.NewIntUnsigned(uint64(pieceSize)), big.NewInt(int64(newEndEpoch-endEpoch)))
+	denom := big.NewInt(int64(endEpoch - startEpoch))
+	charge := big.Div(big.Sub(big.Add(num, denom), big.NewInt(1)), denom)
+	return big.Max(charge, verifreg.MinVerifiedDealSize)
+}
+
 // Computes the weight for a deal proposal, which is a function of its size and duration.
 func DealWeight(proposal *DealProposal) abi.DealWeight {
 	dealDuration := big.NewInt(int64(proposal.Duration()))
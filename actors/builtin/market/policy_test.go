@@ -0,0 +1,164 @@
+package market_test
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+)
+
+func TestDealBoundsV17(t *testing.T) {
+	tinyPiece := abi.PaddedPieceSize(2048)
+	largePiece := abi.PaddedPieceSize(32 << 30)
+	duration := abi.ChainEpoch(200 * builtin.EpochsInDay)
+
+	t.Run("duration", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			pieceSize abi.PaddedPieceSize
+			min       abi.ChainEpoch
+		}{
+			"tiny piece has shorter min duration": {
+				pieceSize: tinyPiece,
+				min:       market.DealMinDurationTinyPiece,
+			},
+			"largest tiny piece has shorter min duration": {
+				pieceSize: market.DealMinPricedPieceSize / 2,
+				min:       market.DealMinDurationTinyPiece,
+			},
+			"min priced piece has min duration": {
+				pieceSize: market.DealMinPricedPieceSize,
+				min:       market.DealMinDuration,
+			},
+			"large piece has min duration": {
+				pieceSize: largePiece,
+				min:       market.DealMinDuration,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				min, max := market.DealDurationBoundsV17(tc.pieceSize)
+				assert.Equal(t, tc.min, min)
+				assert.Equal(t, market.DealMaxSectorCommitment-market.DealMinSectorActivationDelay, max)
+				assert.True(t, max < market.DealMaxDuration)
+			})
+		}
+	})
+
+	t.Run("price per epoch", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			pieceSize abi.PaddedPieceSize
+			duration  abi.ChainEpoch
+			min       abi.TokenAmount
+		}{
+			"tiny piece pays min total price": {
+				pieceSize: tinyPiece,
+				duration:  abi.ChainEpoch(1_000_000),
+				min:       big.Div(market.DealMinTotalPrice, big.NewInt(1_000_000)),
+			},
+			"tiny piece min price rounds up": {
+				pieceSize: tinyPiece,
+				duration:  duration,
+				min:       big.Add(big.Div(market.DealMinTotalPrice, big.NewInt(int64(duration))), big.NewInt(1)),
+			},
+			"largest tiny piece pays min total price": {
+				pieceSize: market.DealMinPricedPieceSize / 2,
+				duration:  abi.ChainEpoch(1_000_000),
+				min:       big.Div(market.DealMinTotalPrice, big.NewInt(1_000_000)),
+			},
+			"min priced piece has no min price": {
+				pieceSize: market.DealMinPricedPieceSize,
+				duration:  duration,
+				min:       big.Zero(),
+			},
+			"large piece has no min price": {
+				pieceSize: largePiece,
+				duration:  duration,
+				min:       big.Zero(),
+			},
+			"zero duration has no min price": {
+				pieceSize: tinyPiece,
+				duration:  0,
+				min:       big.Zero(),
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				min, max := market.DealPricePerEpochBoundsV17(tc.pieceSize, tc.duration)
+				assert.Equal(t, tc.min, min)
+				assert.Equal(t, builtin.TotalFilecoin, max)
+				if tc.duration > 0 && !min.IsZero() {
+					assert.True(t, big.Mul(min, big.NewInt(int64(tc.duration))).GreaterThanEqual(market.DealMinTotalPrice))
+				}
+			})
+		}
+	})
+
+	t.Run("client collateral", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			duration abi.ChainEpoch
+			price    abi.TokenAmount
+			min      abi.TokenAmount
+		}{
+			"share of storage fee": {
+				duration: duration,
+				price:    abi.NewTokenAmount(1000),
+				min:      big.NewInt(int64(duration) * 1000 / 100),
+			},
+			"share of storage fee rounds down": {
+				duration: 199,
+				price:    abi.NewTokenAmount(1),
+				min:      big.NewInt(1),
+			},
+			"free deal requires no collateral": {
+				duration: duration,
+				price:    big.Zero(),
+				min:      big.Zero(),
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				for _, size := range []abi.PaddedPieceSize{tinyPiece, largePiece} {
+					min, max := market.DealClientCollateralBoundsV17(size, tc.duration, tc.price)
+					assert.Equal(t, tc.min, min)
+					assert.Equal(t, builtin.TotalFilecoin, max)
+				}
+			})
+		}
+	})
+}
+
+func TestDealCancellationPenalty(t *testing.T) {
+	fee := abi.NewTokenAmount(1000)
+	providerCollateral := abi.NewTokenAmount(200)
+
+	t.Run("client without collateral pays the minimum share", func(t *testing.T) {
+		assert.Equal(t, abi.NewTokenAmount(120), market.DealCancellationPenalty(fee, big.Zero(), providerCollateral))
+	})
+
+	t.Run("client collateral above the minimum is forfeited", func(t *testing.T) {
+		assert.Equal(t, abi.NewTokenAmount(500), market.DealCancellationPenalty(fee, abi.NewTokenAmount(500), providerCollateral))
+	})
+
+	t.Run("penalty does not exceed the client's locked funds", func(t *testing.T) {
+		assert.Equal(t, abi.NewTokenAmount(10), market.DealCancellationPenalty(abi.NewTokenAmount(10), big.Zero(), abi.NewTokenAmount(10_000)))
+	})
+}
+
+func TestDataCapForDealExtension(t *testing.T) {
+	pieceSize := abi.PaddedPieceSize(1 << 30)
+
+	t.Run("charge is pro-rated by the added term", func(t *testing.T) {
+		assert.Equal(t, big.NewIntUnsigned(uint64(pieceSize/2)), market.DataCapForDealExtension(pieceSize, 100, 300, 400))
+		assert.Equal(t, big.NewIntUnsigned(uint64(pieceSize*2)), market.DataCapForDealExtension(pieceSize, 100, 300, 700))
+	})
+
+	t.Run("charge is rounded up", func(t *testing.T) {
+		assert.Equal(t, big.NewIntUnsigned(uint64(pieceSize/3+1)), market.DataCapForDealExtension(pieceSize, 0, 300, 400))
+	})
+
+	t.Run("charge is at least the minimum verified deal size", func(t *testing.T) {
+		assert.Equal(t, verifreg.MinVerifiedDealSize, market.DataCapForDealExtension(pieceSize, 0, 1_000_000, 1_000_001))
+	})
+}
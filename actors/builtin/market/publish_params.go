@@ -0,0 +1,154 @@
+package market
+
+import (
+	"fmt"
+	"io"
+
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+)
+
+// PublishStorageDealsParams is serialized by hand so that AllocationIDs is omitted when empty.
+// Parameters which reference no client allocations then serialize exactly as in earlier versions,
+// as a CBOR array of one field.
+
+var lengthBufPublishStorageDealsParams = []byte{129}
+var lengthBufPublishStorageDealsParamsWithAllocations = []byte{130}
+
+func (t *PublishStorageDealsParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	lengthBuf := lengthBufPublishStorageDealsParams
+	if len(t.AllocationIDs) > 0 {
+		lengthBuf = lengthBufPublishStorageDealsParamsWithAllocations
+	}
+	if _, err := w.Write(lengthBuf); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Deals ([]market.ClientDealProposal) (slice)
+	if len(t.Deals) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Deals was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Deals))); err != nil {
+		return err
+	}
+	for _, v := range t.Deals {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+
+	if len(t.AllocationIDs) == 0 {
+		return nil
+	}
+
+	// t.AllocationIDs ([]verifreg.AllocationID) (slice)
+	if len(t.AllocationIDs) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.AllocationIDs was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.AllocationIDs))); err != nil {
+		return err
+	}
+	for _, v := range t.AllocationIDs {
+		if err := cbg.CborWriteHeader(w, cbg.MajUnsignedInt, uint64(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *PublishStorageDealsParams) UnmarshalCBOR(r io.Reader) error {
+	*t = PublishStorageDealsParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 && extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+	hasAllocations := extra == 2
+
+	// t.Deals ([]market.ClientDealProposal) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Deals: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Deals = make([]ClientDealProposal, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v ClientDealProposal
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Deals[i] = v
+	}
+
+	if !hasAllocations {
+		return nil
+	}
+
+	// t.AllocationIDs ([]verifreg.AllocationID) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.AllocationIDs: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.AllocationIDs = make([]verifreg.AllocationID, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		maj, val, err := cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return xerrors.Errorf("failed to read uint64 for t.AllocationIDs slice: %w", err)
+		}
+
+		if maj != cbg.MajUnsignedInt {
+			return xerrors.Errorf("value read for array t.AllocationIDs was not a uint, instead got %d", maj)
+		}
+
+		t.AllocationIDs[i] = verifreg.AllocationID(val)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package market_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/crypto"
+	market7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/market"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+)
+
+func TestPublishStorageDealsParamsSerialization(t *testing.T) {
+	client := tutil.NewIDAddr(t, 101)
+	provider := tutil.NewIDAddr(t, 102)
+	deal := generateDealProposal(client, provider, abi.ChainEpoch(10), abi.ChainEpoch(1000))
+	sig := crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("signature")}
+
+	t.Run("params without allocations serialize as in v7", func(t *testing.T) {
+		params := market.PublishStorageDealsParams{Deals: []market.ClientDealProposal{{Proposal: deal, ClientSignature: sig}}}
+		buf := bytes.Buffer{}
+		require.NoError(t, params.MarshalCBOR(&buf))
+
+		var decoded7 market7.PublishStorageDealsParams
+		require.NoError(t, decoded7.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+		buf7 := bytes.Buffer{}
+		require.NoError(t, decoded7.MarshalCBOR(&buf7))
+		assert.Equal(t, buf7.Bytes(), buf.Bytes())
+
+		var decoded market.PublishStorageDealsParams
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+		assert.Len(t, decoded.Deals, 1)
+		assert.Empty(t, decoded.AllocationIDs)
+	})
+
+	t.Run("allocation IDs round trip", func(t *testing.T) {
+		params := market.PublishStorageDealsParams{
+			Deals:         []market.ClientDealProposal{{Proposal: deal, ClientSignature: sig}, {Proposal: deal, ClientSignature: sig}},
+			AllocationIDs: []verifreg.AllocationID{verifreg.NoAllocationID, 5},
+		}
+		buf := bytes.Buffer{}
+		require.NoError(t, params.MarshalCBOR(&buf))
+
+		var decoded market.PublishStorageDealsParams
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+		assert.Len(t, decoded.Deals, 2)
+		assert.Equal(t, params.AllocationIDs, decoded.AllocationIDs)
+	})
+}
@@ -0,0 +1,61 @@
+package market_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	market7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/market"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+)
+
+func TestPublishStorageDealsReturnSerialization(t *testing.T) {
+	t.Run("all valid deals serialize as in v7", func(t *testing.T) {
+		ret := market.PublishStorageDealsReturn{
+			IDs:        []abi.DealID{5, 6},
+			ValidDeals: bitfield.NewFromSet([]uint64{0, 1}),
+		}
+		buf := bytes.Buffer{}
+		require.NoError(t, ret.MarshalCBOR(&buf))
+
+		ret7 := market7.PublishStorageDealsReturn{
+			IDs:        []abi.DealID{5, 6},
+			ValidDeals: bitfield.NewFromSet([]uint64{0, 1}),
+		}
+		buf7 := bytes.Buffer{}
+		require.NoError(t, ret7.MarshalCBOR(&buf7))
+		assert.Equal(t, buf7.Bytes(), buf.Bytes())
+
+		var decoded market.PublishStorageDealsReturn
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+		assert.Equal(t, ret.IDs, decoded.IDs)
+		assert.Empty(t, decoded.DealErrors)
+	})
+
+	t.Run("deal errors round trip", func(t *testing.T) {
+		ret := market.PublishStorageDealsReturn{
+			IDs:        []abi.DealID{5},
+			ValidDeals: bitfield.NewFromSet([]uint64{1}),
+			DealErrors: []exitcode.ExitCode{exitcode.ErrIllegalArgument, exitcode.Ok, exitcode.ErrInsufficientFunds},
+		}
+		buf := bytes.Buffer{}
+		require.NoError(t, ret.MarshalCBOR(&buf))
+
+		var decoded market.PublishStorageDealsReturn
+		require.NoError(t, decoded.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+		assert.Equal(t, ret.IDs, decoded.IDs)
+		assert.Equal(t, ret.DealErrors, decoded.DealErrors)
+		valid, err := decoded.ValidDeals.All(10)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{1}, valid)
+
+		// A decoder for earlier versions rejects the extra field.
+		var decoded7 market7.PublishStorageDealsReturn
+		assert.Error(t, decoded7.UnmarshalCBOR(bytes.NewReader(buf.Bytes())))
+	})
+}
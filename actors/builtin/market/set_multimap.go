@@ -52,8 +52,11 @@ func (mm *SetMultimap) Root() (cid.Cid, error) {
 }
 
 func (mm *SetMultimap) Put(epoch abi.ChainEpoch, v abi.DealID) error {
+	return mm.put(abi.UIntKey(uint64(epoch)), v)
+}
+
+func (mm *SetMultimap) put(k abi.Keyer, v abi.DealID) error {
 	// Load the hamt under key, or initialize a new empty one if not found.
-	k := abi.UIntKey(uint64(epoch))
 	set, found, err := mm.get(k)
 	if err != nil {
 		return err
@@ -67,7 +70,7 @@ func (mm *SetMultimap) Put(epoch abi.ChainEpoch, v abi.DealID) error {
 
 	// Add to the set.
 	if err = set.Put(dealKey(v)); err != nil {
-		return xerrors.Errorf("failed to add key to set %v: %w", epoch, err)
+		return xerrors.Errorf("failed to add key to set %v: %w", k, err)
 	}
 
 	src, err := set.Root()
@@ -117,6 +120,52 @@ func (mm *SetMultimap) PutMany(epoch abi.ChainEpoch, vs []abi.DealID) error {
 	return nil
 }
 
+// Removes a value for a key, if present.
+// The key is removed if it has no remaining values.
+func (mm *SetMultimap) Remove(epoch abi.ChainEpoch, v abi.DealID) error {
+	return mm.remove(abi.UIntKey(uint64(epoch)), v)
+}
+
+func (mm *SetMultimap) remove(k abi.Keyer, v abi.DealID) error {
+	set, found, err := mm.get(k)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	if _, err = set.TryDelete(dealKey(v)); err != nil {
+		return xerrors.Errorf("failed to remove key from set %v: %w", k, err)
+	}
+
+	// Remove the key altogether if its set is now empty.
+	empty := true
+	stopErr := xerrors.New("stop")
+	if err = set.ForEach(func(string) error {
+		empty = false
+		return stopErr
+	}); err != nil && err != stopErr {
+		return xerrors.Errorf("failed to iterate set %v: %w", k, err)
+	}
+	if empty {
+		if _, err := mm.mp.TryDelete(k); err != nil {
+			return xerrors.Errorf("failed to delete set key %v: %w", k, err)
+		}
+		return nil
+	}
+
+	src, err := set.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush set root: %w", err)
+	}
+	newSetRoot := cbg.CborCid(src)
+	if err = mm.mp.Put(k, &newSetRoot); err != nil {
+		return xerrors.Errorf("failed to store set: %w", err)
+	}
+	return nil
+}
+
 // Removes all values for a key.
 func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
 	if _, err := mm.mp.TryDelete(abi.UIntKey(uint64(key))); err != nil {
@@ -127,7 +176,11 @@ func (mm *SetMultimap) RemoveAll(key abi.ChainEpoch) error {
 
 // Iterates all entries for a key, iteration halts if the function returns an error.
 func (mm *SetMultimap) ForEach(epoch abi.ChainEpoch, fn func(id abi.DealID) error) error {
-	set, found, err := mm.get(abi.UIntKey(uint64(epoch)))
+	return mm.forEach(abi.UIntKey(uint64(epoch)), fn)
+}
+
+func (mm *SetMultimap) forEach(k abi.Keyer, fn func(id abi.DealID) error) error {
+	set, found, err := mm.get(k)
 	if err != nil {
 		return err
 	}
@@ -13,11 +13,15 @@ import (
 	"github.com/filecoin-project/go-state-types/big"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
 
 type DealSummary struct {
 	Provider         address.Address
+	Client           address.Address
+	PieceSize        abi.PaddedPieceSize
+	VerifiedDeal     bool
 	StartEpoch       abi.ChainEpoch
 	EndEpoch         abi.ChainEpoch
 	SectorStartEpoch abi.ChainEpoch
@@ -32,6 +36,12 @@ type StateSummary struct {
 	LockTableCount       uint64
 	DealOpEpochCount     uint64
 	DealOpCount          uint64
+	PieceIndexCount      uint64
+	// Datacap spent by each client on verified deals which are not slashed, excluding pending allocations.
+	// This datacap may yet be restored to the client if the deal times out or is terminated.
+	VerifiedDealDataCap map[address.Address]abi.StoragePower
+	// Verified registry allocations made for published verified deals, claimed if the deal is activated.
+	DealAllocations map[abi.DealID]verifreg.AllocationID
 }
 
 // Checks internal invariants of market state.
@@ -58,6 +68,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 	maxDealID := int64(-1)
 	proposalStats := make(map[abi.DealID]*DealSummary)
 	expectedDealOps := make(map[abi.DealID]struct{})
+	proposalPieces := make(map[abi.DealID]cid.Cid)
 	totalProposalCollateral := abi.NewTokenAmount(0)
 
 	if proposals, err := adt.AsArray(store, st.Proposals, ProposalsAmtBitwidth); err != nil {
@@ -76,11 +87,15 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 
 			// keep some state
 			proposalCids[pcid] = struct{}{}
+			proposalPieces[abi.DealID(dealID)] = proposal.PieceCID
 			if dealID > maxDealID {
 				maxDealID = dealID
 			}
 			proposalStats[abi.DealID(dealID)] = &DealSummary{
 				Provider:         proposal.Provider,
+				Client:           proposal.Client,
+				PieceSize:        proposal.PieceSize,
+				VerifiedDeal:     proposal.VerifiedDeal,
 				StartEpoch:       proposal.StartEpoch,
 				EndEpoch:         proposal.EndEpoch,
 				SectorStartEpoch: abi.ChainEpoch(-1),
@@ -220,6 +235,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 
 	dealOpEpochCount := uint64(0)
 	dealOpCount := uint64(0)
+	scheduledDeals := make(map[abi.DealID]abi.ChainEpoch)
 	if dealOps, err := AsSetMultimap(store, st.DealOpsByEpoch, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth); err != nil {
 		acc.Addf("error loading deal ops: %v", err)
 	} else {
@@ -235,6 +251,9 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 			return dealOps.ForEach(abi.ChainEpoch(epoch), func(id abi.DealID) error {
 				_, found := proposalStats[id]
 				acc.Require(found, "deal op found for deal id %d with missing proposal at epoch %d", id, epoch)
+				prev, scheduled := scheduledDeals[id]
+				acc.Require(!scheduled, "deal op for deal id %d at epoch %d already scheduled at epoch %d", id, epoch, prev)
+				scheduledDeals[id] = abi.ChainEpoch(epoch)
 				delete(expectedDealOps, id)
 				dealOpCount++
 				return nil
@@ -245,6 +264,150 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 
 	acc.Require(len(expectedDealOps) == 0, "missing deal ops for proposals: %v", expectedDealOps)
 
+	//
+	// Deals by Piece
+	//
+
+	// Every unterminated deal should be indexed by its piece, if the index is enabled.
+	expectedIndexed := make(map[abi.DealID]struct{})
+	for dealID, stats := range proposalStats { //nolint:nomaprange
+		if PieceDealIndexEnabled && stats.SlashEpoch == EpochUndefined {
+			expectedIndexed[dealID] = struct{}{}
+		}
+	}
+	pieceIndexCount := uint64(0)
+	if dealsByPiece, err := AsPieceDealIndex(store, st.DealsByPiece); err != nil {
+		acc.Addf("error loading deals by piece: %v", err)
+	} else {
+		err = dealsByPiece.ForEachPiece(func(piece cid.Cid, id abi.DealID) error {
+			proposalPiece, found := proposalPieces[id]
+			acc.Require(found, "deal %d indexed by piece %v not found within proposals", id, piece)
+			acc.Require(!found || proposalPiece.Equals(piece), "deal %d indexed by piece %v but stores piece %v", id, piece, proposalPiece)
+			acc.Require(PieceDealIndexEnabled, "deal %d indexed by piece %v with the index disabled", id, piece)
+			_, unterminated := expectedIndexed[id]
+			acc.Require(!found || !PieceDealIndexEnabled || unterminated, "terminated deal %d indexed by piece %v", id, piece)
+			delete(expectedIndexed, id)
+			pieceIndexCount++
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating deals by piece")
+	}
+
+	acc.Require(len(expectedIndexed) == 0, "missing piece index entries for deals: %v", expectedIndexed)
+
+	//
+	// Deals by Party
+	//
+
+	// Every deal should be indexed by both its client and provider.
+	expectedParties := make(map[abi.DealID]map[address.Address]struct{})
+	for dealID, stats := range proposalStats { //nolint:nomaprange
+		expectedParties[dealID] = map[address.Address]struct{}{stats.Client: {}, stats.Provider: {}}
+	}
+	if dealsByParty, err := AsPartyDealIndex(store, st.DealsByParty); err != nil {
+		acc.Addf("error loading deals by party: %v", err)
+	} else {
+		err = dealsByParty.ForEachParty(func(party address.Address, id abi.DealID) error {
+			parties, found := expectedParties[id]
+			acc.Require(found, "deal %d indexed by party %v not found within proposals", id, party)
+			if found {
+				_, isParty := parties[party]
+				acc.Require(isParty, "deal %d indexed by %v which is neither its client nor provider", id, party)
+				delete(parties, party)
+				if len(parties) == 0 {
+					delete(expectedParties, id)
+				}
+			}
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating deals by party")
+	}
+
+	acc.Require(len(expectedParties) == 0, "missing party index entries for deals: %v", expectedParties)
+
+	//
+	// Auto-withdraw Recipients
+	//
+
+	if recipients, err := adt.AsMap(store, st.AutoWithdrawRecipients, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading auto-withdraw recipients: %v", err)
+	} else {
+		var recipient address.Address
+		err = recipients.ForEach(&recipient, func(key string) error {
+			holder, err := address.NewFromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			acc.Require(holder.Protocol() == address.ID, "auto-withdraw recipient set for non-ID address %v", holder)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating auto-withdraw recipients")
+	}
+
+	//
+	// Pre-committed Deals
+	//
+
+	if preCommitted, err := adt.AsSet(store, st.PreCommittedDeals, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading pre-committed deals: %v", err)
+	} else {
+		err = preCommitted.ForEach(func(key string) error {
+			id, err := abi.ParseUIntKey(key)
+			if err != nil {
+				return err
+			}
+			stats, found := proposalStats[abi.DealID(id)]
+			acc.Require(found, "pre-committed deal %d not found within proposals", id)
+			acc.Require(!found || stats.SectorStartEpoch == EpochUndefined, "pre-committed deal %d has been activated", id)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating pre-committed deals")
+	}
+
+	//
+	// Deal Allocations
+	//
+
+	dealAllocations := make(map[abi.DealID]verifreg.AllocationID)
+	if allocationIDs, err := adt.AsMap(store, st.DealAllocationIds, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading deal allocations: %v", err)
+	} else {
+		var allocationID cbg.CborInt
+		err = allocationIDs.ForEach(&allocationID, func(key string) error {
+			id, err := abi.ParseUIntKey(key)
+			if err != nil {
+				return err
+			}
+			stats, found := proposalStats[abi.DealID(id)]
+			acc.Require(found, "deal %d with allocation %d not found within proposals", id, allocationID)
+			acc.Require(!found || stats.VerifiedDeal, "unverified deal %d has allocation %d", id, allocationID)
+			dealAllocations[abi.DealID(id)] = verifreg.AllocationID(allocationID)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating deal allocations")
+	}
+
+	//
+	// Verified Deal DataCap
+	//
+
+	// The datacap of a deal with an allocation is held by the allocation in the verified registry until the deal
+	// is activated, and then by the market on behalf of the deal.
+	verifiedDealDataCap := make(map[address.Address]abi.StoragePower)
+	for dealID, deal := range proposalStats { // nolint:nomaprange
+		if !deal.VerifiedDeal || deal.SlashEpoch != EpochUndefined {
+			continue
+		}
+		if _, allocated := dealAllocations[dealID]; allocated && deal.SectorStartEpoch == EpochUndefined {
+			continue
+		}
+		dataCap, ok := verifiedDealDataCap[deal.Client]
+		if !ok {
+			dataCap = big.Zero()
+		}
+		verifiedDealDataCap[deal.Client] = big.Add(dataCap, big.NewIntUnsigned(uint64(deal.PieceSize)))
+	}
+
 	return &StateSummary{
 		Deals:                proposalStats,
 		PendingProposalCount: pendingProposalCount,
@@ -252,5 +415,8 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount, c
 		LockTableCount:       lockTableCount,
 		DealOpEpochCount:     dealOpEpochCount,
 		DealOpCount:          dealOpCount,
+		PieceIndexCount:      pieceIndexCount,
+		VerifiedDealDataCap:  verifiedDealDataCap,
+		DealAllocations:      dealAllocations,
 	}, acc
 }
@@ -42,14 +42,26 @@ var MethodsMultisig = struct {
 	SwapSigner                  abi.MethodNum
 	ChangeNumApprovalsThreshold abi.MethodNum
 	LockBalance                 abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	ProposeWithExpiration       abi.MethodNum
+	RemoveExpiredTransactions   abi.MethodNum
+	ProposeBatch                abi.MethodNum
+	ExecuteBatch                abi.MethodNum
+	ChangeSignerWeight          abi.MethodNum
+	SetApprovalRules            abi.MethodNum
+	LockVestingSchedule         abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
 
 var MethodsPaych = struct {
-	Constructor        abi.MethodNum
-	UpdateChannelState abi.MethodNum
-	Settle             abi.MethodNum
-	Collect            abi.MethodNum
-}{MethodConstructor, 2, 3, 4}
+	Constructor             abi.MethodNum
+	UpdateChannelState      abi.MethodNum
+	Settle                  abi.MethodNum
+	Collect                 abi.MethodNum
+	UpdateChannelStateBatch abi.MethodNum
+	Withdraw                abi.MethodNum
+	LockHTLC                abi.MethodNum
+	ClaimHTLC               abi.MethodNum
+	ReclaimHTLC             abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
 
 var MethodsMarket = struct {
 	Constructor              abi.MethodNum
@@ -61,7 +73,25 @@ var MethodsMarket = struct {
 	OnMinerSectorsTerminate  abi.MethodNum
 	ComputeDataCommitment    abi.MethodNum
 	CronTick                 abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
+	SettleDealPayments       abi.MethodNum
+	GetBalance               abi.MethodNum
+	GetDealDataCommitment    abi.MethodNum
+	GetDealClient            abi.MethodNum
+	GetDealProvider          abi.MethodNum
+	GetDealLabel             abi.MethodNum
+	GetDealTerm              abi.MethodNum
+	GetDealPricePerEpoch     abi.MethodNum
+	GetDealVerified          abi.MethodNum
+	GetDealActivation        abi.MethodNum
+	CancelDeals              abi.MethodNum
+	ExtendDeals              abi.MethodNum
+	GetDealsForPiece         abi.MethodNum
+	SetAutoWithdraw          abi.MethodNum
+	GetEscrowUnlockSchedule  abi.MethodNum
+	BatchActivateDeals       abi.MethodNum
+	OnMinerSectorsPreCommit  abi.MethodNum
+	OnMinerPreCommitsRemoved abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27}
 
 var MethodsPower = struct {
 	Constructor              abi.MethodNum
@@ -76,34 +106,41 @@ var MethodsPower = struct {
 }{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9}
 
 var MethodsMiner = struct {
-	Constructor              abi.MethodNum
-	ControlAddresses         abi.MethodNum
-	ChangeWorkerAddress      abi.MethodNum
-	ChangePeerID             abi.MethodNum
-	SubmitWindowedPoSt       abi.MethodNum
-	PreCommitSector          abi.MethodNum
-	ProveCommitSector        abi.MethodNum
-	ExtendSectorExpiration   abi.MethodNum
-	TerminateSectors         abi.MethodNum
-	DeclareFaults            abi.MethodNum
-	DeclareFaultsRecovered   abi.MethodNum
-	OnDeferredCronEvent      abi.MethodNum
-	CheckSectorProven        abi.MethodNum
-	ApplyRewards             abi.MethodNum
-	ReportConsensusFault     abi.MethodNum
-	WithdrawBalance          abi.MethodNum
-	ConfirmSectorProofsValid abi.MethodNum
-	ChangeMultiaddrs         abi.MethodNum
-	CompactPartitions        abi.MethodNum
-	CompactSectorNumbers     abi.MethodNum
-	ConfirmUpdateWorkerKey   abi.MethodNum
-	RepayDebt                abi.MethodNum
-	ChangeOwnerAddress       abi.MethodNum
-	DisputeWindowedPoSt      abi.MethodNum
-	PreCommitSectorBatch     abi.MethodNum
-	ProveCommitAggregate     abi.MethodNum
-	ProveReplicaUpdates      abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27}
+	Constructor                    abi.MethodNum
+	ControlAddresses               abi.MethodNum
+	ChangeWorkerAddress            abi.MethodNum
+	ChangePeerID                   abi.MethodNum
+	SubmitWindowedPoSt             abi.MethodNum
+	PreCommitSector                abi.MethodNum
+	ProveCommitSector              abi.MethodNum
+	ExtendSectorExpiration         abi.MethodNum
+	TerminateSectors               abi.MethodNum
+	DeclareFaults                  abi.MethodNum
+	DeclareFaultsRecovered         abi.MethodNum
+	OnDeferredCronEvent            abi.MethodNum
+	CheckSectorProven              abi.MethodNum
+	ApplyRewards                   abi.MethodNum
+	ReportConsensusFault           abi.MethodNum
+	WithdrawBalance                abi.MethodNum
+	ConfirmSectorProofsValid       abi.MethodNum
+	ChangeMultiaddrs               abi.MethodNum
+	CompactPartitions              abi.MethodNum
+	CompactSectorNumbers           abi.MethodNum
+	ConfirmUpdateWorkerKey         abi.MethodNum
+	RepayDebt                      abi.MethodNum
+	ChangeOwnerAddress             abi.MethodNum
+	DisputeWindowedPoSt            abi.MethodNum
+	PreCommitSectorBatch           abi.MethodNum
+	ProveCommitAggregate           abi.MethodNum
+	ProveReplicaUpdates            abi.MethodNum
+	CancelPreCommits               abi.MethodNum
+	GetTerminationFees             abi.MethodNum
+	ChangeMinimumUnlockedReserve   abi.MethodNum
+	ChangeSectorHistoryRecording   abi.MethodNum
+	DeclareFaultsBySector          abi.MethodNum
+	DeclareFaultsRecoveredBySector abi.MethodNum
+	ExtendSectorDeals              abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20, 21, 22, 23, 24, 25, 26, 27, 28, 29, 30, 31, 32, 33, 34}
 
 var MethodsVerifiedRegistry = struct {
 	Constructor                 abi.MethodNum
@@ -113,4 +150,13 @@ var MethodsVerifiedRegistry = struct {
 	UseBytes                    abi.MethodNum
 	RestoreBytes                abi.MethodNum
 	RemoveVerifiedClientDataCap abi.MethodNum
-}{MethodConstructor, 2, 3, 4, 5, 6, 7}
+	CreateAllocations           abi.MethodNum
+	ClaimAllocations            abi.MethodNum
+	RemoveExpiredAllocations    abi.MethodNum
+	GetClaims                   abi.MethodNum
+	SetVerifierExpiration       abi.MethodNum
+	GetVerifierAudit            abi.MethodNum
+	TransferDataCap             abi.MethodNum
+	AllocateDealDataCap         abi.MethodNum
+	RemoveExpiredClaims         abi.MethodNum
+}{MethodConstructor, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
@@ -87,6 +87,50 @@ func (q BitfieldQueue) Cut(toCut bitfield.BitField) error {
 	return nil
 }
 
+// Removes values from the queue entry for an epoch, deleting the entry if it becomes empty.
+// Unlike Cut, other values are not shifted. Fails if any value is not present in the entry.
+func (q BitfieldQueue) RemoveFromQueue(rawEpoch abi.ChainEpoch, values bitfield.BitField) error {
+	if isEmpty, err := values.IsEmpty(); err != nil {
+		return xerrors.Errorf("failed to decode values to remove: %w", err)
+	} else if isEmpty {
+		// nothing to do.
+		return nil
+	}
+	epoch := q.quant.QuantizeUp(rawEpoch)
+	var bf bitfield.BitField
+	if _, err := q.Array.Get(uint64(epoch), &bf); err != nil {
+		return xerrors.Errorf("failed to lookup queue epoch %v: %w", epoch, err)
+	}
+
+	missing, err := bitfield.SubtractBitField(values, bf)
+	if err != nil {
+		return xerrors.Errorf("failed to check values in queue epoch %v: %w", epoch, err)
+	}
+	if isEmpty, err := missing.IsEmpty(); err != nil {
+		return xerrors.Errorf("failed to decode missing values: %w", err)
+	} else if !isEmpty {
+		return xerrors.Errorf("values %v not present in queue epoch %v", missing, epoch)
+	}
+
+	bf, err = bitfield.SubtractBitField(bf, values)
+	if err != nil {
+		return xerrors.Errorf("failed to subtract values from queue epoch %v: %w", epoch, err)
+	}
+	if isEmpty, err := bf.IsEmpty(); err != nil {
+		return xerrors.Errorf("failed to decode queue epoch %v: %w", epoch, err)
+	} else if isEmpty {
+		if err := q.Array.Delete(uint64(epoch)); err != nil {
+			return xerrors.Errorf("failed to delete queue epoch %v: %w", epoch, err)
+		}
+		return nil
+	}
+
+	if err = q.Array.Set(uint64(epoch), bf); err != nil {
+		return xerrors.Errorf("failed to set queue epoch %v: %w", epoch, err)
+	}
+	return nil
+}
+
 func (q BitfieldQueue) AddManyToQueueValues(values map[abi.ChainEpoch][]uint64) error {
 	// Pre-quantize to reduce the number of updates.
 	quantizedValues := make(map[abi.ChainEpoch][]uint64, len(values))
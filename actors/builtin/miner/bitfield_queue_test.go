@@ -223,6 +223,31 @@ func TestBitfieldQueue(t *testing.T) {
 			Equals(t, queue)
 	})
 
+	t.Run("removes elements without shifting", func(t *testing.T) {
+		queue := emptyBitfieldQueueWithQuantizing(t, builtin.NewQuantSpec(5, 3), testAmtBitwidth)
+
+		require.NoError(t, queue.AddToQueueValues(abi.ChainEpoch(4), 1, 2, 3, 99))
+		require.NoError(t, queue.AddToQueueValues(abi.ChainEpoch(9), 5, 6))
+
+		// epochs are quantized the same way as when adding
+		require.NoError(t, queue.RemoveFromQueue(abi.ChainEpoch(6), bitfield.NewFromSet([]uint64{2, 3})))
+		require.NoError(t, queue.RemoveFromQueue(abi.ChainEpoch(13), bitfield.NewFromSet([]uint64{5, 6})))
+
+		// emptied entries are deleted
+		ExpectBQ().
+			Add(abi.ChainEpoch(8), 1, 99).
+			Equals(t, queue)
+	})
+
+	t.Run("fails to remove elements not in queue entry", func(t *testing.T) {
+		queue := emptyBitfieldQueue(t, testAmtBitwidth)
+
+		require.NoError(t, queue.AddToQueueValues(abi.ChainEpoch(42), 1, 2))
+		require.NoError(t, queue.AddToQueueValues(abi.ChainEpoch(43), 3))
+
+		require.Error(t, queue.RemoveFromQueue(abi.ChainEpoch(42), bitfield.NewFromSet([]uint64{2, 3})))
+	})
+
 	t.Run("adds empty bitfield to queue", func(t *testing.T) {
 		queue := emptyBitfieldQueue(t, testAmtBitwidth)
 
@@ -6,6 +6,7 @@ const (
 	BurnMethodDisputeWindowedPoSt      BurnMethod = "DisputeWindowedPoSt"
 	BurnMethodPreCommitSectorBatch     BurnMethod = "PreCommitSectorBatch"
 	BurnMethodProveCommitAggregate     BurnMethod = "ProveCommitAggregate"
+	BurnMethodCancelPreCommits         BurnMethod = "CancelPreCommits"
 	BurnMethodDeclareFaultsRecovered   BurnMethod = "DeclareFaultsRecovered"
 	BurnMethodApplyRewards             BurnMethod = "ApplyRewards"
 	BurnMethodReportConsensusFault     BurnMethod = "ReportConsensusFault"
@@ -13,4 +14,5 @@ const (
 	BurnMethodRepayDebt                BurnMethod = "RepayDebt"
 	BurnMethodProcessEarlyTerminations BurnMethod = "ProcessEarlyTerminations"
 	BurnMethodHandleProvingDeadline    BurnMethod = "HandleProvingDeadline "
+	BurnMethodExtendSectorDeals        BurnMethod = "ExtendSectorDeals"
 )
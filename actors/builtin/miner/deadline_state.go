@@ -993,6 +993,38 @@ func (dl *Deadline) ProcessDeadlineEnd(store adt.Store, quant builtin.QuantSpec,
 	return powerDelta, penalizedPower, nil
 }
 
+// Returns the live sectors of partitions not proven this deadline which are not already faulty.
+// These sectors are detected faulty when the deadline ends.
+func (dl *Deadline) MissedPoStSectors(store adt.Store) (bitfield.BitField, error) {
+	partitions, err := dl.PartitionsArray(store)
+	if err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to load partitions: %w", err)
+	}
+
+	var missed []bitfield.BitField
+	var partition Partition
+	if err := partitions.ForEach(&partition, func(partIdx int64) error {
+		if proven, err := dl.PartitionsPoSted.IsSet(uint64(partIdx)); err != nil {
+			return xerrors.Errorf("failed to check submission for partition %d: %w", partIdx, err)
+		} else if proven {
+			return nil
+		}
+		live, err := partition.LiveSectors()
+		if err != nil {
+			return err
+		}
+		nonFaulty, err := bitfield.SubtractBitField(live, partition.Faults)
+		if err != nil {
+			return xerrors.Errorf("failed to compute non-faulty sectors of partition %d: %w", partIdx, err)
+		}
+		missed = append(missed, nonFaulty)
+		return nil
+	}); err != nil {
+		return bitfield.BitField{}, err
+	}
+	return bitfield.MultiMerge(missed...)
+}
+
 type PoStResult struct {
 	// Power activated or deactivated (positive or negative).
 	PowerDelta PowerPair
@@ -1004,6 +1036,10 @@ type PoStResult struct {
 	IgnoredSectors bitfield.BitField
 	// Bitfield of partitions that were proven.
 	Partitions bitfield.BitField
+	// Sectors newly marked faulty because they were skipped.
+	NewFaultySectors bitfield.BitField
+	// Sectors recovered by the proof.
+	RecoveredSectors bitfield.BitField
 }
 
 // RecordProvenSectors processes a series of posts, recording proven partitions
@@ -1047,6 +1083,8 @@ func (dl *Deadline) RecordProvenSectors(
 
 	allSectors := make([]bitfield.BitField, 0, len(postPartitions))
 	allIgnored := make([]bitfield.BitField, 0, len(postPartitions))
+	allNewFaults := make([]bitfield.BitField, 0, len(postPartitions))
+	allRecovered := make([]bitfield.BitField, 0, len(postPartitions))
 	newFaultyPowerTotal := NewPowerPairZero()
 	retractedRecoveryPowerTotal := NewPowerPairZero()
 	recoveredPowerTotal := NewPowerPairZero()
@@ -1063,6 +1101,8 @@ func (dl *Deadline) RecordProvenSectors(
 			return nil, xc.ErrNotFound.Wrapf("no such partition %d", post.Index)
 		}
 
+		priorFaults := partition.Faults
+
 		// Process new faults and accumulate new faulty power.
 		// This updates the faults in partition state ahead of calculating the sectors to include for proof.
 		newPowerDelta, newFaultPower, retractedRecoveryPower, hasNewFaults, err := partition.RecordSkippedFaults(
@@ -1077,6 +1117,12 @@ func (dl *Deadline) RecordProvenSectors(
 		if hasNewFaults {
 			rescheduledPartitions = append(rescheduledPartitions, post.Index)
 		}
+		newFaults, err := bitfield.SubtractBitField(partition.Faults, priorFaults)
+		if err != nil {
+			return nil, xerrors.Errorf("failed to compute new faults for partition %d: %w", post.Index, err)
+		}
+		allNewFaults = append(allNewFaults, newFaults)
+		allRecovered = append(allRecovered, partition.Recoveries)
 
 		recoveredPower, err := partition.RecoverFaults(store, sectors, ssize, quant)
 		if err != nil {
@@ -1129,10 +1175,20 @@ func (dl *Deadline) RecordProvenSectors(
 	if err != nil {
 		return nil, xc.ErrIllegalState.Wrapf("failed to merge ignored sectors bitfields: %w", err)
 	}
+	allNewFaultSectorNos, err := bitfield.MultiMerge(allNewFaults...)
+	if err != nil {
+		return nil, xc.ErrIllegalState.Wrapf("failed to merge new faults bitfields: %w", err)
+	}
+	allRecoveredSectorNos, err := bitfield.MultiMerge(allRecovered...)
+	if err != nil {
+		return nil, xc.ErrIllegalState.Wrapf("failed to merge recovered sectors bitfields: %w", err)
+	}
 
 	return &PoStResult{
 		Sectors:                allSectorNos,
 		IgnoredSectors:         allIgnoredSectorNos,
+		NewFaultySectors:       allNewFaultSectorNos,
+		RecoveredSectors:       allRecoveredSectorNos,
 		PowerDelta:             powerDelta,
 		NewFaultyPower:         newFaultyPowerTotal,
 		RecoveredPower:         recoveredPowerTotal,
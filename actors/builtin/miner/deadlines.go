@@ -3,6 +3,7 @@ package miner
 import (
 	"errors"
 
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/dline"
 	"golang.org/x/xerrors"
@@ -58,6 +59,52 @@ func FindSector(store adt.Store, deadlines *Deadlines, sectorNum abi.SectorNumbe
 	return 0, 0, xerrors.Errorf("sector %d not due at any deadline", sectorNum)
 }
 
+// FindSectors returns the deadline and partition indices for a set of sector numbers,
+// together with those sector numbers not tracked by any deadline.
+// Each deadline and partition is loaded at most once, regardless of the number of sectors.
+func FindSectors(store adt.Store, deadlines *Deadlines, sectorNos bitfield.BitField) (DeadlineSectorMap, bitfield.BitField, error) {
+	found := make(DeadlineSectorMap)
+	remaining := sectorNos
+	for dlIdx := range deadlines.Due {
+		if empty, err := remaining.IsEmpty(); err != nil {
+			return nil, bitfield.BitField{}, err
+		} else if empty {
+			break
+		}
+
+		dl, err := deadlines.LoadDeadline(store, uint64(dlIdx))
+		if err != nil {
+			return nil, bitfield.BitField{}, err
+		}
+
+		partitions, err := adt.AsArray(store, dl.Partitions, DeadlinePartitionsAmtBitwidth)
+		if err != nil {
+			return nil, bitfield.BitField{}, err
+		}
+		var partition Partition
+		err = partitions.ForEach(&partition, func(i int64) error {
+			inPartition, err := bitfield.IntersectBitField(remaining, partition.Sectors)
+			if err != nil {
+				return err
+			}
+			if empty, err := inPartition.IsEmpty(); err != nil {
+				return err
+			} else if empty {
+				return nil
+			}
+			if err := found.Add(uint64(dlIdx), uint64(i), inPartition); err != nil {
+				return err
+			}
+			remaining, err = bitfield.SubtractBitField(remaining, inPartition)
+			return err
+		})
+		if err != nil {
+			return nil, bitfield.BitField{}, err
+		}
+	}
+	return found, remaining, nil
+}
+
 // Returns true if the deadline at the given index is currently mutable. A
 // "mutable" deadline may have new sectors assigned to it.
 func deadlineIsMutable(provingPeriodStart abi.ChainEpoch, dlIdx uint64, currentEpoch abi.ChainEpoch) bool {
@@ -80,6 +80,13 @@ func (a Actor) Exports() []interface{} {
 		25:                        a.PreCommitSectorBatch,
 		26:                        a.ProveCommitAggregate,
 		27:                        a.ProveReplicaUpdates,
+		28:                        a.CancelPreCommits,
+		29:                        a.GetTerminationFees,
+		30:                        a.ChangeMinimumUnlockedReserve,
+		31:                        a.ChangeSectorHistoryRecording,
+		32:                        a.DeclareFaultsBySector,
+		33:                        a.DeclareFaultsRecoveredBySector,
+		34:                        a.ExtendSectorDeals,
 	}
 }
 
@@ -305,6 +312,56 @@ func (a Actor) ChangeMultiaddrs(rt Runtime, params *ChangeMultiaddrsParams) *abi
 	return nil
 }
 
+type ChangeMinimumUnlockedReserveParams struct {
+	NewReserve abi.TokenAmount
+}
+
+// Sets the amount of unlocked funds that WithdrawBalance must leave in the actor.
+// Funds retained this way are available to repay fee debt as it is incurred, e.g. in the deadline cron.
+// Only the owner may change the reserve, which may be set to zero to remove it.
+func (a Actor) ChangeMinimumUnlockedReserve(rt Runtime, params *ChangeMinimumUnlockedReserveParams) *abi.EmptyValue {
+	if params.NewReserve.Nil() || params.NewReserve.LessThan(big.Zero()) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "invalid minimum unlocked reserve: %v", params.NewReserve)
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+
+		// Only the owner may constrain withdrawal of the owner's funds.
+		rt.ValidateImmediateCallerIs(info.Owner)
+
+		info.MinimumUnlockedReserve = params.NewReserve
+		err := st.SaveInfo(adt.AsStore(rt), info)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "could not save miner info")
+	})
+	return nil
+}
+
+type ChangeSectorHistoryRecordingParams struct {
+	Enabled bool
+}
+
+// Enables or disables recording of sector lifecycle events in state.
+// Disabling recording discards all history recorded so far.
+func (a Actor) ChangeSectorHistoryRecording(rt Runtime, params *ChangeSectorHistoryRecordingParams) *abi.EmptyValue {
+	var st State
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+
+		// The owner bears the gas and storage cost of recording.
+		rt.ValidateImmediateCallerIs(info.Owner)
+
+		if params.Enabled {
+			err := st.EnableSectorHistory(adt.AsStore(rt))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to enable sector history")
+		} else {
+			st.DisableSectorHistory()
+		}
+	})
+	return nil
+}
+
 //////////////////
 // WindowedPoSt //
 //////////////////
@@ -435,6 +492,11 @@ func (a Actor) SubmitWindowedPoSt(rt Runtime, params *SubmitWindowedPoStParams)
 		postResult, err = deadline.RecordProvenSectors(store, sectors, info.SectorSize, QuantSpecForDeadline(currDeadline), faultExpiration, params.Partitions)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process post submission for deadline %d", params.Deadline)
 
+		err = st.RecordUniformSectorEvents(store, postResult.NewFaultySectors, SectorEvent{Type: SectorEventFaultDetected, Epoch: currEpoch})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record skipped faults")
+		err = st.RecordUniformSectorEvents(store, postResult.RecoveredSectors, SectorEvent{Type: SectorEventRecovered, Epoch: currEpoch})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record recoveries")
+
 		// Make sure we actually proved something.
 
 		provenSectors, err := bitfield.SubtractBitField(postResult.Sectors, postResult.IgnoredSectors)
@@ -741,6 +803,11 @@ func (a Actor) PreCommitSectorBatch(rt Runtime, params *PreCommitSectorBatchPara
 		rt.Abortf(exitcode.ErrIllegalState, "deal weight request returned %d records, expected %d",
 			len(dealWeights.Sectors), len(params.Sectors))
 	}
+	var dealIDs []abi.DealID
+	for _, precommit := range params.Sectors {
+		dealIDs = append(dealIDs, precommit.DealIDs...)
+	}
+	notifyDealsPreCommitted(rt, dealIDs)
 
 	store := adt.AsStore(rt)
 	var st State
@@ -1063,9 +1130,21 @@ func (a Actor) ConfirmSectorProofsValid(rt Runtime, params *builtin.ConfirmSecto
 	store := adt.AsStore(rt)
 
 	// This skips missing pre-commits.
-	precommittedSectors, err := st.FindPrecommittedSectors(store, params.Sectors...)
+	found, err := st.FindPrecommittedSectors(store, params.Sectors...)
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pre-committed sectors")
 
+	// Proofs are confirmed in the epoch they are submitted, which is after the challenge delay of the
+	// pre-commit they prove. A pre-commit within its challenge delay has replaced a cancelled pre-commit
+	// with the same sector number, and the confirmed proof is of the cancelled one.
+	precommittedSectors := make([]*SectorPreCommitOnChainInfo, 0, len(found))
+	for _, precommit := range found {
+		if rt.CurrEpoch() <= precommit.PreCommitEpoch+PreCommitChallengeDelay {
+			rt.Log(rtt.WARN, "skipping sector %d pre-committed at %d within challenge delay", precommit.Info.SectorNumber, precommit.PreCommitEpoch)
+			continue
+		}
+		precommittedSectors = append(precommittedSectors, precommit)
+	}
+
 	confirmSectorProofsValid(rt, precommittedSectors, params.RewardBaselinePower, params.RewardSmoothed, params.QualityAdjPowerSmoothed)
 
 	return nil
@@ -1086,30 +1165,51 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 	// a constant number of them.
 
 	activation := rt.CurrEpoch()
-	// Pre-commits for new sectors.
-	var validPreCommits []*SectorPreCommitOnChainInfo
+	// Check (and activate) storage deals associated to sectors in a single batch.
+	// Pre-commits whose deals fail to activate are dropped from the prove commit set.
+	var dealsActivations []market.SectorDealsActivation
+	var dealPreCommits []*SectorPreCommitOnChainInfo
 	for _, precommit := range preCommits {
 		if len(precommit.Info.DealIDs) > 0 {
-			// Check (and activate) storage deals associated to sector. Abort if checks failed.
-			// TODO: we should batch these calls...
-			// https://github.com/filecoin-project/specs-actors/issues/474
-			code := rt.Send(
-				builtin.StorageMarketActorAddr,
-				builtin.MethodsMarket.ActivateDeals,
-				&market.ActivateDealsParams{
-					DealIDs:      precommit.Info.DealIDs,
-					SectorExpiry: precommit.Info.Expiration,
-				},
-				abi.NewTokenAmount(0),
-				&builtin.Discard{},
-			)
-
-			if code != exitcode.Ok {
-				rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
-				continue
+			dealsActivations = append(dealsActivations, market.SectorDealsActivation{
+				SectorType:   precommit.Info.SealProof,
+				SectorExpiry: precommit.Info.Expiration,
+				DealIDs:      precommit.Info.DealIDs,
+			})
+			dealPreCommits = append(dealPreCommits, precommit)
+		}
+	}
+	activatedDeals := make(map[abi.SectorNumber]bool, len(dealPreCommits))
+	if len(dealsActivations) > 0 {
+		var batchRet market.BatchActivateDealsReturn
+		code := rt.Send(
+			builtin.StorageMarketActorAddr,
+			builtin.MethodsMarket.BatchActivateDeals,
+			&market.BatchActivateDealsParams{Sectors: dealsActivations},
+			abi.NewTokenAmount(0),
+			&batchRet,
+		)
+		if code != exitcode.Ok {
+			rt.Log(rtt.INFO, "failed to activate deals on %d sectors, dropping from prove commit set: %s", len(dealPreCommits), code)
+		} else {
+			for i, precommit := range dealPreCommits {
+				activated, err := batchRet.ActivatedSectors.IsSet(uint64(i))
+				builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to read activated sectors")
+				if !activated {
+					rt.Log(rtt.INFO, "failed to activate deals on sector %d, dropping from prove commit set", precommit.Info.SectorNumber)
+					continue
+				}
+				activatedDeals[precommit.Info.SectorNumber] = true
 			}
 		}
+	}
 
+	// Pre-commits for new sectors.
+	var validPreCommits []*SectorPreCommitOnChainInfo
+	for _, precommit := range preCommits {
+		if len(precommit.Info.DealIDs) > 0 && !activatedDeals[precommit.Info.SectorNumber] {
+			continue
+		}
 		validPreCommits = append(validPreCommits, precommit)
 	}
 
@@ -1171,6 +1271,19 @@ func confirmSectorProofsValid(rt Runtime, preCommits []*SectorPreCommitOnChainIn
 		err := st.PutSectors(store, newSectors...)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put new sectors")
 
+		activations := make(map[abi.SectorNumber]SectorEvent, len(newSectors))
+		for _, sector := range newSectors {
+			sealedCID := sector.SealedCID
+			activations[sector.SectorNumber] = SectorEvent{
+				Type:         SectorEventActivated,
+				Epoch:        sector.Activation,
+				NewSealedCID: &sealedCID,
+				Expiration:   sector.Expiration,
+			}
+		}
+		err = st.RecordSectorEvents(store, activations)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record sector activations")
+
 		err = st.DeletePrecommittedSectors(store, newSectorNos...)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete precommited sectors")
 
@@ -1222,6 +1335,83 @@ func (a Actor) CheckSectorProven(rt Runtime, params *CheckSectorProvenParams) *a
 	return nil
 }
 
+type CancelPreCommitsParams struct {
+	Sectors bitfield.BitField
+}
+
+// Withdraws pre-commitments for sectors that will not be proven, before their prove-commit deadline.
+// A fraction of each pre-commit deposit is burnt and the remainder is returned to the miner's available balance.
+// The cancelled sector numbers are no longer allocated and may be pre-committed again,
+// and the clients of the sectors' deals may again cancel them.
+// A proof of a cancelled pre-commitment queued for batch verification cannot confirm its replacement,
+// which is too recent to have been proven (see ConfirmSectorProofsValid).
+func (a Actor) CancelPreCommits(rt Runtime, params *CancelPreCommitsParams) *abi.EmptyValue {
+	sectorCount, err := params.Sectors.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count sectors")
+	if sectorCount == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no sectors to cancel")
+	} else if sectorCount > PreCommitSectorBatchMaxSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors addressed, addressed %d want <= %d", sectorCount, PreCommitSectorBatchMaxSize)
+	}
+
+	currEpoch := rt.CurrEpoch()
+	store := adt.AsStore(rt)
+	var st State
+	penaltyToBurn := abi.NewTokenAmount(0)
+	var dealIDs []abi.DealID
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		precommits, err := st.GetAllPrecommittedSectors(store, params.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pre-committed sectors")
+
+		depositToRelease := big.Zero()
+		sectorNos := make([]abi.SectorNumber, len(precommits))
+		cleanUpEvents := map[abi.ChainEpoch][]uint64{}
+		for i, precommit := range precommits {
+			dealIDs = append(dealIDs, precommit.Info.DealIDs...)
+
+			msd, ok := MaxProveCommitDuration[precommit.Info.SealProof]
+			if !ok {
+				rt.Abortf(exitcode.ErrIllegalState, "no max seal duration for proof type: %d", precommit.Info.SealProof)
+			}
+			proveCommitDue := precommit.PreCommitEpoch + msd
+			if currEpoch > proveCommitDue {
+				rt.Abortf(exitcode.ErrForbidden, "pre-commitment for sector %d expired at %d", precommit.Info.SectorNumber, proveCommitDue)
+			}
+
+			// Reconstruct the clean up epoch scheduled in PreCommitSectorBatch.
+			cleanUpBound := proveCommitDue + ExpiredPreCommitCleanUpDelay
+			cleanUpEvents[cleanUpBound] = append(cleanUpEvents[cleanUpBound], uint64(precommit.Info.SectorNumber))
+
+			sectorNos[i] = precommit.Info.SectorNumber
+			depositToRelease = big.Add(depositToRelease, precommit.PreCommitDeposit)
+			penaltyToBurn = big.Add(penaltyToBurn, PreCommitCancellationPenalty(precommit.PreCommitDeposit))
+		}
+
+		err = st.DeletePrecommittedSectors(store, sectorNos...)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete pre-committed sectors")
+
+		err = st.RemovePreCommitCleanUps(store, cleanUpEvents)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove pre-commit expiry from queue")
+
+		err = st.DeallocateSectorNumbers(store, params.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to deallocate sector ids %v", params.Sectors)
+
+		// The penalty is paid out of the released deposit, so is always covered.
+		err = st.AddPreCommitDeposit(depositToRelease.Neg())
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to release pre-commit deposit %v", depositToRelease)
+	})
+
+	burnFunds(rt, penaltyToBurn, BurnMethodCancelPreCommits)
+	notifyPreCommitsRemoved(rt, dealIDs)
+	rt.StateReadonly(&st)
+	err = st.CheckBalanceInvariants(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
+	return nil
+}
+
 /////////////////////////
 // Sector Modification //
 /////////////////////////
@@ -1303,6 +1493,7 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 		sectors, err := LoadSectors(store, st.Sectors)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
 
+		extensions := make(map[abi.SectorNumber]SectorEvent)
 		for _, dlIdx := range deadlinesToLoad {
 			deadline, err := deadlines.LoadDeadline(store, dlIdx)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
@@ -1364,6 +1555,11 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 					newSector.VerifiedDealWeight = newVerifiedDealWeight
 
 					newSectors[i] = &newSector
+					extensions[sector.SectorNumber] = SectorEvent{
+						Type:       SectorEventExtended,
+						Epoch:      currEpoch,
+						Expiration: decl.NewExpiration,
+					}
 				}
 
 				// Overwrite sector infos.
@@ -1406,6 +1602,9 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 		st.Sectors, err = sectors.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save sectors")
 
+		err = st.RecordSectorEvents(store, extensions)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record sector extensions")
+
 		err = st.SaveDeadlines(store, deadlines)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
 	})
@@ -1417,6 +1616,151 @@ func (a Actor) ExtendSectorExpiration(rt Runtime, params *ExtendSectorExpiration
 	return nil
 }
 
+type ExtendSectorDealsParams struct {
+	Sector     abi.SectorNumber
+	Extensions []market.ClientDealExtension
+}
+
+// Extends the terms of deals in an active sector, as agreed with each deal's client, and adds the weight of
+// the extended terms to the sector. Deals may not be extended beyond the sector's expiration, so a provider
+// renewing deals for longer than the sector's current lifetime should first extend the sector's expiration.
+// As for a replica update, the sector's expected rewards are recomputed for its new power, and any increase
+// in its initial pledge requirement is locked from the miner's unlocked balance.
+// The sector's activation is unchanged, so the new expected day reward applies over the sector's whole age.
+func (a Actor) ExtendSectorDeals(rt Runtime, params *ExtendSectorDealsParams) *abi.EmptyValue {
+	if len(params.Extensions) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no deal extensions")
+	}
+
+	store := adt.AsStore(rt)
+	var st State
+	rt.StateReadonly(&st)
+	info := getMinerInfo(rt, &st)
+	rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+	sector, found, err := st.GetSector(store, params.Sector)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sector %d", params.Sector)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no such sector %d", params.Sector)
+	}
+	if sector.Expiration < rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrForbidden, "cannot extend deals in expired sector %d, expired at %d", params.Sector, sector.Expiration)
+	}
+	sectorDeals := make(map[abi.DealID]struct{}, len(sector.DealIDs))
+	for _, dealID := range sector.DealIDs {
+		sectorDeals[dealID] = struct{}{}
+	}
+	for _, extension := range params.Extensions {
+		if _, ok := sectorDeals[extension.Extension.DealID]; !ok {
+			rt.Abortf(exitcode.ErrIllegalArgument, "deal %d is not in sector %d", extension.Extension.DealID, params.Sector)
+		}
+	}
+
+	var weights market.ExtendDealsReturn
+	code := rt.Send(
+		builtin.StorageMarketActorAddr,
+		builtin.MethodsMarket.ExtendDeals,
+		&market.ExtendDealsParams{
+			SectorExpiry: sector.Expiration,
+			Extensions:   params.Extensions,
+		},
+		abi.NewTokenAmount(0),
+		&weights,
+	)
+	builtin.RequireSuccess(rt, code, "failed to extend deals in sector %d", params.Sector)
+
+	rewRet := requestCurrentEpochBlockReward(rt)
+	powRet := requestCurrentTotalPower(rt)
+
+	powerDelta := NewPowerPairZero()
+	pledgeDelta := big.Zero()
+	feeToBurn := abi.NewTokenAmount(0)
+	rt.StateTransaction(&st, func() {
+		feeToBurn = RepayDebtsOrAbort(rt, &st)
+
+		// Reload the sector, which was read before calling the market.
+		sector, found, err = st.GetSector(store, params.Sector)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sector %d", params.Sector)
+		builtin.RequireState(rt, found, "no such sector %d", params.Sector)
+
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+		dlIdx, pIdx, err := FindSector(store, deadlines, params.Sector)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to find sector %d", params.Sector)
+
+		deadline, err := deadlines.LoadDeadline(store, dlIdx)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+		partitions, err := deadline.PartitionsArray(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load partitions for deadline %d", dlIdx)
+		var partition Partition
+		found, err := partitions.Get(pIdx, &partition)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d partition %d", dlIdx, pIdx)
+		builtin.RequireState(rt, found, "no such deadline %d partition %d", dlIdx, pIdx)
+
+		active, err := partition.ActiveSectors()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load active sectors")
+		isActive, err := active.IsSet(uint64(params.Sector))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to check active sectors")
+		if !isActive {
+			rt.Abortf(exitcode.ErrForbidden, "cannot extend deals in inactive sector %d", params.Sector)
+		}
+
+		newSector := *sector
+		newSector.DealWeight = big.Add(sector.DealWeight, weights.DealWeight)
+		newSector.VerifiedDealWeight = big.Add(sector.VerifiedDealWeight, weights.VerifiedDealWeight)
+
+		pwr := QAPowerForSector(info.SectorSize, &newSector)
+		newSector.ExpectedDayReward = ExpectedRewardForPower(rewRet.ThisEpochRewardSmoothed, powRet.QualityAdjPowerSmoothed, pwr, builtin.EpochsInDay)
+		newSector.ExpectedStoragePledge = ExpectedRewardForPower(rewRet.ThisEpochRewardSmoothed, powRet.QualityAdjPowerSmoothed, pwr, InitialPledgeProjectionPeriod)
+
+		newInitialPledge := InitialPledgeForPower(pwr, rewRet.ThisEpochBaselinePower, rewRet.ThisEpochRewardSmoothed,
+			powRet.QualityAdjPowerSmoothed, rt.TotalFilCircSupply())
+		if newInitialPledge.GreaterThan(sector.InitialPledge) {
+			deficit := big.Sub(newInitialPledge, sector.InitialPledge)
+
+			unlockedBalance, err := st.GetUnlockedBalance(rt.CurrentBalance())
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to calculate unlocked balance")
+			builtin.RequirePredicate(rt, unlockedBalance.GreaterThanEqual(deficit), exitcode.ErrInsufficientFunds,
+				"insufficient funds for new initial pledge requirement %s, available: %s", deficit, unlockedBalance)
+
+			err = st.AddInitialPledge(deficit)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add initial pledge")
+
+			newSector.InitialPledge = newInitialPledge
+		}
+
+		sectors, err := LoadSectors(store, st.Sectors)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+		err = sectors.Store(&newSector)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update sector %d", params.Sector)
+		st.Sectors, err = sectors.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save sectors")
+
+		// The expiration is unchanged, so replacing the sector only adjusts the partition's power and pledge.
+		powerDelta, pledgeDelta, err = partition.ReplaceSectors(store, []*SectorOnChainInfo{sector}, []*SectorOnChainInfo{&newSector},
+			info.SectorSize, st.QuantSpecForDeadline(dlIdx))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to replace sector %d", params.Sector)
+
+		err = partitions.Set(pIdx, &partition)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d partition %d", dlIdx, pIdx)
+		deadline.Partitions, err = partitions.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save partitions for deadline %d", dlIdx)
+		err = deadlines.UpdateDeadline(store, dlIdx, deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadline %d", dlIdx)
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	})
+
+	burnFunds(rt, feeToBurn, BurnMethodExtendSectorDeals)
+	notifyPledgeChanged(rt, pledgeDelta)
+	requestUpdatePower(rt, powerDelta)
+
+	rt.StateReadonly(&st)
+	err = st.CheckBalanceInvariants(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
+	return nil
+}
+
 //type TerminateSectorsParams struct {
 //	Terminations []TerminationDeclaration
 //}
@@ -1548,6 +1892,66 @@ func (a Actor) TerminateSectors(rt Runtime, params *TerminateSectorsParams) *Ter
 	return &TerminateSectorsReturn{Done: !more}
 }
 
+type GetTerminationFeesParams struct {
+	Sectors bitfield.BitField
+}
+
+type SectorTerminationFee struct {
+	SectorNumber abi.SectorNumber
+	Fee          abi.TokenAmount
+}
+
+type GetTerminationFeesReturn struct {
+	Fees  []SectorTerminationFee
+	Total abi.TokenAmount
+}
+
+// Quotes the fee that would be charged to terminate each of a set of live sectors at the current epoch,
+// using the network reward and power estimates that TerminateSectors would use.
+// Faulty sectors may be quoted. Sectors that have already terminated or expired may not.
+func (a Actor) GetTerminationFees(rt Runtime, params *GetTerminationFeesParams) *GetTerminationFeesReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	sectorCount, err := params.Sectors.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count sectors")
+	if sectorCount > AddressedSectorsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors addressed, addressed %d want <= %d", sectorCount, AddressedSectorsMax)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	store := adt.AsStore(rt)
+	info := getMinerInfo(rt, &st)
+
+	terminated, err := st.LoadTerminatedSectors(store)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load terminated sectors")
+	alreadyTerminated, err := bitfield.IntersectBitField(params.Sectors, terminated)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to intersect terminated sectors")
+	if empty, err := alreadyTerminated.IsEmpty(); err != nil {
+		rt.Abortf(exitcode.ErrIllegalState, "failed to check terminated sectors: %s", err)
+	} else if !empty {
+		rt.Abortf(exitcode.ErrIllegalArgument, "sectors %v already terminated", alreadyTerminated)
+	}
+
+	sectors, err := st.LoadSectorInfos(store, params.Sectors)
+	builtin.RequireNoErr(rt, err, exitcode.ErrNotFound, "failed to load sectors")
+
+	rewardStats := requestCurrentEpochBlockReward(rt)
+	pwrTotal := requestCurrentTotalPower(rt)
+
+	fees, total := TerminationFees(info.SectorSize, rt.CurrEpoch(), rewardStats.ThisEpochRewardSmoothed,
+		pwrTotal.QualityAdjPowerSmoothed, sectors)
+
+	ret := &GetTerminationFeesReturn{
+		Fees:  make([]SectorTerminationFee, len(sectors)),
+		Total: total,
+	}
+	for i, sector := range sectors {
+		ret.Fees[i] = SectorTerminationFee{SectorNumber: sector.SectorNumber, Fee: fees[i]}
+	}
+	return ret
+}
+
 ////////////
 // Faults //
 ////////////
@@ -1595,31 +1999,7 @@ func (a Actor) DeclareFaults(rt Runtime, params *DeclareFaultsParams) *abi.Empty
 		deadlines, err := st.LoadDeadlines(store)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
 
-		sectors, err := LoadSectors(store, st.Sectors)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
-
-		currEpoch := rt.CurrEpoch()
-		err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
-			targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid fault declaration deadline %d", dlIdx)
-
-			err = validateFRDeclarationDeadline(targetDeadline)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed fault declaration at deadline %d", dlIdx)
-
-			deadline, err := deadlines.LoadDeadline(store, dlIdx)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
-
-			faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
-			deadlinePowerDelta, err := deadline.RecordFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare faults for deadline %d", dlIdx)
-
-			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d partitions", dlIdx)
-
-			powerDelta = powerDelta.Add(deadlinePowerDelta)
-			return nil
-		})
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deadlines")
+		powerDelta = recordFaultDeclarations(rt, store, &st, info, deadlines, toProcess)
 
 		err = st.SaveDeadlines(store, deadlines)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
@@ -1685,27 +2065,98 @@ func (a Actor) DeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecovered
 		deadlines, err := st.LoadDeadlines(adt.AsStore(rt))
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
 
-		sectors, err := LoadSectors(store, st.Sectors)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+		recordRecoveryDeclarations(rt, store, &st, info, deadlines, toProcess)
 
-		currEpoch := rt.CurrEpoch()
-		err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
-			targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid recovery declaration deadline %d", dlIdx)
-			err = validateFRDeclarationDeadline(targetDeadline)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed recovery declaration at deadline %d", dlIdx)
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	})
 
-			deadline, err := deadlines.LoadDeadline(store, dlIdx)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+	burnFunds(rt, feeToBurn, BurnMethodDeclareFaultsRecovered)
+	rt.StateReadonly(&st)
+	err = st.CheckBalanceInvariants(rt.CurrentBalance())
+	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 
-			err = deadline.DeclareFaultsRecovered(store, sectors, info.SectorSize, pm)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare recoveries for deadline %d", dlIdx)
+	// Power is not restored yet, but when the recovered sectors are successfully PoSted.
+	return nil
+}
 
-			err = deadlines.UpdateDeadline(store, dlIdx, deadline)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d", dlIdx)
-			return nil
-		})
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to walk sectors")
+type DeclareFaultsBySectorParams struct {
+	Sectors bitfield.BitField
+}
+
+type DeclareFaultsRecoveredBySectorParams struct {
+	Sectors bitfield.BitField
+}
+
+// A sector which could not be declared faulty or recovered, and the reason.
+type SectorDeclarationError struct {
+	SectorNumber abi.SectorNumber
+	// ErrNotFound if the sector is not assigned to any deadline,
+	// ErrForbidden if its deadline does not accept declarations at the current epoch.
+	Code exitcode.ExitCode
+}
+
+type SectorDeclarationsReturn struct {
+	// Sectors for which the declaration was processed.
+	Declared bitfield.BitField
+	// Sectors for which the declaration was rejected, in ascending order of sector number.
+	Errors []SectorDeclarationError
+}
+
+// Declares sectors faulty, as DeclareFaults, but locating each sector's deadline and partition on chain.
+// Sectors which cannot be declared are reported in the return value rather than failing the message.
+func (a Actor) DeclareFaultsBySector(rt Runtime, params *DeclareFaultsBySectorParams) *SectorDeclarationsReturn {
+	validateSectorDeclarationCount(rt, params.Sectors)
+
+	store := adt.AsStore(rt)
+	var st State
+	var ret SectorDeclarationsReturn
+	powerDelta := NewPowerPairZero()
+	rt.StateTransaction(&st, func() {
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+		var toProcess DeadlineSectorMap
+		toProcess, ret = resolveSectorDeclarations(rt, store, &st, deadlines, params.Sectors)
+		powerDelta = recordFaultDeclarations(rt, store, &st, info, deadlines, toProcess)
+
+		err = st.SaveDeadlines(store, deadlines)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
+	})
+
+	requestUpdatePower(rt, powerDelta)
+	return &ret
+}
+
+// Declares sectors recovered, as DeclareFaultsRecovered, but locating each sector's deadline and partition on chain.
+// Sectors which cannot be declared are reported in the return value rather than failing the message.
+func (a Actor) DeclareFaultsRecoveredBySector(rt Runtime, params *DeclareFaultsRecoveredBySectorParams) *SectorDeclarationsReturn {
+	validateSectorDeclarationCount(rt, params.Sectors)
+
+	store := adt.AsStore(rt)
+	var st State
+	var ret SectorDeclarationsReturn
+	feeToBurn := abi.NewTokenAmount(0)
+	rt.StateTransaction(&st, func() {
+		// Verify unlocked funds cover both InitialPledgeRequirement and FeeDebt
+		// and repay fee debt now.
+		feeToBurn = RepayDebtsOrAbort(rt, &st)
+
+		info := getMinerInfo(rt, &st)
+		rt.ValidateImmediateCallerIs(append(info.ControlAddresses, info.Owner, info.Worker)...)
+		if ConsensusFaultActive(info, rt.CurrEpoch()) {
+			rt.Abortf(exitcode.ErrForbidden, "recovery not allowed during active consensus fault")
+		}
+
+		deadlines, err := st.LoadDeadlines(store)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
+
+		var toProcess DeadlineSectorMap
+		toProcess, ret = resolveSectorDeclarations(rt, store, &st, deadlines, params.Sectors)
+		recordRecoveryDeclarations(rt, store, &st, info, deadlines, toProcess)
 
 		err = st.SaveDeadlines(store, deadlines)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
@@ -1713,11 +2164,10 @@ func (a Actor) DeclareFaultsRecovered(rt Runtime, params *DeclareFaultsRecovered
 
 	burnFunds(rt, feeToBurn, BurnMethodDeclareFaultsRecovered)
 	rt.StateReadonly(&st)
-	err = st.CheckBalanceInvariants(rt.CurrentBalance())
+	err := st.CheckBalanceInvariants(rt.CurrentBalance())
 	builtin.RequireNoErr(rt, err, ErrBalanceInvariantBroken, "balance invariants broken")
 
-	// Power is not restored yet, but when the recovered sectors are successfully PoSted.
-	return nil
+	return &ret
 }
 
 /////////////////
@@ -2018,7 +2468,9 @@ func (a Actor) WithdrawBalance(rt Runtime, params *WithdrawBalanceParams) *abi.T
 		feeToBurn = RepayDebtsOrAbort(rt, &st)
 	})
 
-	amountWithdrawn := big.Min(availableBalance, params.AmountRequested)
+	// The owner's reserve of unlocked funds is not available for withdrawal.
+	withdrawable := big.Max(big.Sub(availableBalance, info.MinimumUnlockedReserve), big.Zero())
+	amountWithdrawn := big.Min(withdrawable, params.AmountRequested)
 	builtin.RequireState(rt, amountWithdrawn.GreaterThanEqual(big.Zero()), "negative amount to withdraw: %v", amountWithdrawn)
 	builtin.RequireState(rt, amountWithdrawn.LessThanEqual(availableBalance), "amount to withdraw %v < available %v", amountWithdrawn, availableBalance)
 
@@ -2086,9 +2538,8 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 		sectorInfo *SectorOnChainInfo
 	}
 
-	var sectorsDeals []market.SectorDeals
-	var sectorsDataSpec []*market.SectorDataSpec
-	var validatedUpdates []*updateAndSectorInfo
+	var sectorsDeals []market.SectorDealsActivation
+	var candidateUpdates []*updateAndSectorInfo
 	sectorNumbers := bitfield.New()
 	for i := range params.Updates {
 		update := params.Updates[i]
@@ -2159,50 +2610,58 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 			continue
 		}
 
+		candidateUpdates = append(candidateUpdates, &updateAndSectorInfo{
+			update:     &update,
+			sectorInfo: sectorInfo,
+		})
+		sectorsDeals = append(sectorsDeals, market.SectorDealsActivation{
+			SectorType:   sectorInfo.SealProof,
+			SectorExpiry: sectorInfo.Expiration,
+			DealIDs:      update.Deals,
+		})
+	}
+
+	// Activate the deals of all candidate updates in a single batch, skipping updates whose deals fail to activate.
+	var activatedDeals market.BatchActivateDealsReturn
+	if len(sectorsDeals) > 0 {
 		code := rt.Send(
 			builtin.StorageMarketActorAddr,
-			builtin.MethodsMarket.ActivateDeals,
-			&market.ActivateDealsParams{
-				DealIDs:      update.Deals,
-				SectorExpiry: sectorInfo.Expiration,
+			builtin.MethodsMarket.BatchActivateDeals,
+			&market.BatchActivateDealsParams{
+				Sectors:    sectorsDeals,
+				ComputeCID: true,
 			},
 			abi.NewTokenAmount(0),
-			&builtin.Discard{},
+			&activatedDeals,
 		)
-
 		if code != exitcode.Ok {
-			rt.Log(rtt.INFO, "failed to activate deals, skipping sector %d", update.SectorID)
-			continue
+			rt.Log(rtt.INFO, "failed to activate deals on %d sectors, skipping all: %s", len(sectorsDeals), code)
+			activatedDeals = market.BatchActivateDealsReturn{ActivatedSectors: bitfield.New()}
 		}
+	}
 
-		validatedUpdates = append(validatedUpdates, &updateAndSectorInfo{
-			update:     &update,
-			sectorInfo: sectorInfo,
-		})
-
-		sectorsDeals = append(sectorsDeals, market.SectorDeals{DealIDs: update.Deals, SectorExpiry: sectorInfo.Expiration})
-		sectorsDataSpec = append(sectorsDataSpec, &market.SectorDataSpec{
-			SectorType: sectorInfo.SealProof,
-			DealIDs:    update.Deals,
-		})
+	var validatedUpdates []*updateAndSectorInfo
+	for i, candidate := range candidateUpdates {
+		activated, err := activatedDeals.ActivatedSectors.IsSet(uint64(i))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to read activated sectors")
+		if !activated {
+			rt.Log(rtt.INFO, "failed to activate deals, skipping sector %d", candidate.update.SectorID)
+			continue
+		}
+		validatedUpdates = append(validatedUpdates, candidate)
 	}
 
 	builtin.RequireParam(rt, len(validatedUpdates) > 0, "no valid updates")
 
 	// Errors past this point cause the ProveReplicaUpdates call to fail (no more skipping sectors)
 
-	dealWeights := requestDealWeights(rt, sectorsDeals)
-	builtin.RequirePredicate(rt, len(dealWeights.Sectors) == len(validatedUpdates), exitcode.ErrIllegalState,
-		"deal weight request returned %d records, expected %d", len(dealWeights.Sectors), len(validatedUpdates))
-
-	unsealedSectorCIDs := requestUnsealedSectorCIDs(rt, sectorsDataSpec...)
-	builtin.RequirePredicate(rt, len(unsealedSectorCIDs) == len(validatedUpdates), exitcode.ErrIllegalState,
-		"unsealed sector cid request returned %d records, expected %d", len(unsealedSectorCIDs), len(validatedUpdates))
+	builtin.RequirePredicate(rt, len(activatedDeals.Sectors) == len(validatedUpdates), exitcode.ErrIllegalState,
+		"deal activation returned %d records, expected %d", len(activatedDeals.Sectors), len(validatedUpdates))
 
 	type updateWithDetails struct {
 		update            *ReplicaUpdate
 		sectorInfo        *SectorOnChainInfo
-		dealWeight        market.SectorWeights
+		dealWeight        market.SectorDealsActivated
 		unsealedSectorCID cid.Cid
 	}
 
@@ -2216,8 +2675,8 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 		declsByDeadline[updateWithSectorInfo.update.Deadline] = append(declsByDeadline[updateWithSectorInfo.update.Deadline], &updateWithDetails{
 			update:            updateWithSectorInfo.update,
 			sectorInfo:        updateWithSectorInfo.sectorInfo,
-			dealWeight:        dealWeights.Sectors[i],
-			unsealedSectorCID: unsealedSectorCIDs[i],
+			dealWeight:        activatedDeals.Sectors[i],
+			unsealedSectorCID: *activatedDeals.Sectors[i].UnsealedCID,
 		})
 	}
 
@@ -2231,6 +2690,7 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadlines")
 
 		newSectors := make([]*SectorOnChainInfo, 0)
+		replicaUpdates := make(map[abi.SectorNumber]SectorEvent, len(validatedUpdates))
 		for _, dlIdx := range deadlinesToLoad {
 			deadline, err := deadlines.LoadDeadline(store, dlIdx)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
@@ -2324,6 +2784,15 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 
 				newSectors = append(newSectors, &newSectorInfo)
 				succeededSectors.Set(uint64(newSectorInfo.SectorNumber))
+
+				oldSealedCID := updateWithDetails.sectorInfo.SealedCID
+				newSealedCID := newSectorInfo.SealedCID
+				replicaUpdates[newSectorInfo.SectorNumber] = SectorEvent{
+					Type:         SectorEventReplicaUpdated,
+					Epoch:        rt.CurrEpoch(),
+					OldSealedCID: &oldSealedCID,
+					NewSealedCID: &newSealedCID,
+				}
 			}
 
 			deadline.Partitions, err = partitions.Root()
@@ -2344,6 +2813,9 @@ func (a Actor) ProveReplicaUpdates(rt Runtime, params *ProveReplicaUpdatesParams
 		st.Sectors, err = sectors.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save sectors")
 
+		err = st.RecordSectorEvents(store, replicaUpdates)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record replica updates")
+
 		err = st.SaveDeadlines(store, deadlines)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save deadlines")
 
@@ -2449,6 +2921,9 @@ func processEarlyTerminations(rt Runtime, rewardSmoothed smoothing.FilterEstimat
 				rewardSmoothed, qualityAdjPowerSmoothed, sectors))
 			dealsToTerminate = append(dealsToTerminate, params)
 
+			err = st.RecordUniformSectorEvents(store, sectorNos, SectorEvent{Type: SectorEventTerminated, Epoch: epoch})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record sector terminations")
+
 			return nil
 		})
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to process terminations")
@@ -2505,6 +2980,7 @@ func handleProvingDeadline(rt Runtime,
 	pledgeDeltaTotal := abi.NewTokenAmount(0)
 
 	var continueCron bool
+	var expiredDealIDs []abi.DealID
 	var st State
 	rt.StateTransaction(&st, func() {
 		{
@@ -2523,8 +2999,9 @@ func handleProvingDeadline(rt Runtime,
 		}
 
 		{
-			depositToBurn, err := st.CleanUpExpiredPreCommits(store, currEpoch)
+			depositToBurn, dealIDs, err := st.CleanUpExpiredPreCommits(store, currEpoch)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to expire pre-committed sectors")
+			expiredDealIDs = dealIDs
 
 			err = st.ApplyPenalty(depositToBurn)
 			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to apply penalty")
@@ -2569,6 +3046,7 @@ func handleProvingDeadline(rt Runtime,
 	requestUpdatePower(rt, powerDeltaTotal)
 	burnFunds(rt, penaltyTotal, BurnMethodHandleProvingDeadline)
 	notifyPledgeChanged(rt, pledgeDeltaTotal)
+	notifyPreCommitsRemoved(rt, expiredDealIDs)
 
 	// Schedule cron callback for next deadline's last epoch.
 	if continueCron {
@@ -2678,6 +3156,40 @@ func requestTerminateDeals(rt Runtime, epoch abi.ChainEpoch, dealIDs []abi.DealI
 	}
 }
 
+func notifyDealsPreCommitted(rt Runtime, dealIDs []abi.DealID) {
+	for len(dealIDs) > 0 {
+		size := min64(cbg.MaxLength, uint64(len(dealIDs)))
+		code := rt.Send(
+			builtin.StorageMarketActorAddr,
+			builtin.MethodsMarket.OnMinerSectorsPreCommit,
+			&market.OnMinerSectorsPreCommitParams{
+				DealIDs: dealIDs[:size],
+			},
+			abi.NewTokenAmount(0),
+			&builtin.Discard{},
+		)
+		builtin.RequireSuccess(rt, code, "failed to record pre-committed deals, exit code %v", code)
+		dealIDs = dealIDs[size:]
+	}
+}
+
+func notifyPreCommitsRemoved(rt Runtime, dealIDs []abi.DealID) {
+	for len(dealIDs) > 0 {
+		size := min64(cbg.MaxLength, uint64(len(dealIDs)))
+		code := rt.Send(
+			builtin.StorageMarketActorAddr,
+			builtin.MethodsMarket.OnMinerPreCommitsRemoved,
+			&market.OnMinerPreCommitsRemovedParams{
+				DealIDs: dealIDs[:size],
+			},
+			abi.NewTokenAmount(0),
+			&builtin.Discard{},
+		)
+		builtin.RequireSuccess(rt, code, "failed to clear pre-committed deals, exit code %v", code)
+		dealIDs = dealIDs[size:]
+	}
+}
+
 func scheduleEarlyTerminationWork(rt Runtime) {
 	rt.Log(rtt.INFO, "scheduling early terminations with cron...")
 
@@ -2991,6 +3503,139 @@ func validateFRDeclarationDeadline(deadline *dline.Info) error {
 	return nil
 }
 
+// Records fault declarations at the deadlines and partitions specified, returning the power removed from new faults.
+// Aborts if any targeted deadline does not accept declarations at the current epoch.
+func recordFaultDeclarations(rt Runtime, store adt.Store, st *State, info *MinerInfo, deadlines *Deadlines, toProcess DeadlineSectorMap) PowerPair {
+	sectors, err := LoadSectors(store, st.Sectors)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+	powerDelta := NewPowerPairZero()
+	currEpoch := rt.CurrEpoch()
+	err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+		targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid fault declaration deadline %d", dlIdx)
+
+		err = validateFRDeclarationDeadline(targetDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed fault declaration at deadline %d", dlIdx)
+
+		deadline, err := deadlines.LoadDeadline(store, dlIdx)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+
+		faultExpirationEpoch := targetDeadline.Last() + FaultMaxAge
+		deadlinePowerDelta, err := deadline.RecordFaults(store, sectors, info.SectorSize, QuantSpecForDeadline(targetDeadline), faultExpirationEpoch, pm)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare faults for deadline %d", dlIdx)
+
+		err = recordDeclaredSectorEvents(store, st, pm, SectorEvent{Type: SectorEventFaultDeclared, Epoch: currEpoch})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record fault declarations for deadline %d", dlIdx)
+
+		err = deadlines.UpdateDeadline(store, dlIdx, deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d partitions", dlIdx)
+
+		powerDelta = powerDelta.Add(deadlinePowerDelta)
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate deadlines")
+	return powerDelta
+}
+
+// Records recovery declarations at the deadlines and partitions specified.
+// Aborts if any targeted deadline does not accept declarations at the current epoch.
+func recordRecoveryDeclarations(rt Runtime, store adt.Store, st *State, info *MinerInfo, deadlines *Deadlines, toProcess DeadlineSectorMap) {
+	sectors, err := LoadSectors(store, st.Sectors)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load sectors array")
+
+	currEpoch := rt.CurrEpoch()
+	err = toProcess.ForEach(func(dlIdx uint64, pm PartitionSectorMap) error {
+		targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid recovery declaration deadline %d", dlIdx)
+		err = validateFRDeclarationDeadline(targetDeadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed recovery declaration at deadline %d", dlIdx)
+
+		deadline, err := deadlines.LoadDeadline(store, dlIdx)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load deadline %d", dlIdx)
+
+		err = deadline.DeclareFaultsRecovered(store, sectors, info.SectorSize, pm)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to declare recoveries for deadline %d", dlIdx)
+
+		err = recordDeclaredSectorEvents(store, st, pm, SectorEvent{Type: SectorEventRecoveryDeclared, Epoch: currEpoch})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to record recovery declarations for deadline %d", dlIdx)
+
+		err = deadlines.UpdateDeadline(store, dlIdx, deadline)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store deadline %d", dlIdx)
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to walk sectors")
+}
+
+// Validates the number of sectors in a declaration addressed by sector number.
+func validateSectorDeclarationCount(rt Runtime, sectorNos bitfield.BitField) {
+	count, err := sectorNos.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to count sectors")
+	if count == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "no sectors declared")
+	}
+	if count > AddressedSectorsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "too many sectors for declaration %d, max %d", count, AddressedSectorsMax)
+	}
+}
+
+// Locates the deadline and partition of each declared sector.
+// Sectors not assigned to any deadline, or assigned to a deadline that does not accept fault or recovery declarations
+// at the current epoch, are excluded from the returned map and reported as errors.
+// Aborts if the remaining sectors span more than DeclarationsMax partitions.
+func resolveSectorDeclarations(rt Runtime, store adt.Store, st *State, deadlines *Deadlines, sectorNos bitfield.BitField) (DeadlineSectorMap, SectorDeclarationsReturn) {
+	toProcess, notFound, err := FindSectors(store, deadlines, sectorNos)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to find sectors")
+
+	currEpoch := rt.CurrEpoch()
+	immutable := bitfield.New()
+	for _, dlIdx := range toProcess.Deadlines() {
+		targetDeadline, err := declarationDeadlineInfo(st.CurrentProvingPeriodStart(currEpoch), dlIdx, currEpoch)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "invalid declaration deadline %d", dlIdx)
+		if validateFRDeclarationDeadline(targetDeadline) == nil {
+			continue
+		}
+		err = toProcess[dlIdx].ForEach(func(_ uint64, sectorNos bitfield.BitField) error {
+			immutable, err = bitfield.MergeBitFields(immutable, sectorNos)
+			return err
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to collect sectors at deadline %d", dlIdx)
+		delete(toProcess, dlIdx)
+	}
+
+	partitions, _, err := toProcess.Count()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to count declared partitions")
+	if partitions > DeclarationsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "declared sectors span too many partitions: %d > %d", partitions, DeclarationsMax)
+	}
+
+	rejected, err := bitfield.MergeBitFields(notFound, immutable)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to merge rejected sectors")
+	ret := SectorDeclarationsReturn{Errors: []SectorDeclarationError{}}
+	ret.Declared, err = bitfield.SubtractBitField(sectorNos, rejected)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to subtract rejected sectors")
+	err = rejected.ForEach(func(sectorNo uint64) error {
+		code := exitcode.ErrForbidden
+		if isNotFound, err := notFound.IsSet(sectorNo); err != nil {
+			return err
+		} else if isNotFound {
+			code = exitcode.ErrNotFound
+		}
+		ret.Errors = append(ret.Errors, SectorDeclarationError{SectorNumber: abi.SectorNumber(sectorNo), Code: code})
+		return nil
+	})
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate rejected sectors")
+	return toProcess, ret
+}
+
+// Records an event for every sector declared in a fault or recovery declaration.
+// Declarations are recorded as made, including sectors whose fault status they did not change.
+func recordDeclaredSectorEvents(store adt.Store, st *State, pm PartitionSectorMap, event SectorEvent) error {
+	return pm.ForEach(func(_ uint64, sectorNos bitfield.BitField) error {
+		return st.RecordUniformSectorEvents(store, sectorNos, event)
+	})
+}
+
 // Validates that a partition contains the given sectors.
 func validatePartitionContainsSectors(partition *Partition, sectors bitfield.BitField) error {
 	// Check that the declared sectors are actually assigned to the partition.
@@ -3006,14 +3651,24 @@ func validatePartitionContainsSectors(partition *Partition, sectors bitfield.Bit
 
 func terminationPenalty(sectorSize abi.SectorSize, currEpoch abi.ChainEpoch,
 	rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate, sectors []*SectorOnChainInfo) abi.TokenAmount {
+	_, totalFee := TerminationFees(sectorSize, currEpoch, rewardEstimate, networkQAPowerEstimate, sectors)
+	return totalFee
+}
+
+// Computes the fee for terminating each sector early at an epoch, given network reward and power estimates,
+// along with the total over all sectors.
+// This is the penalty charged when sectors are terminated, whether at the owner's request or after faulting too long.
+func TerminationFees(sectorSize abi.SectorSize, currEpoch abi.ChainEpoch,
+	rewardEstimate, networkQAPowerEstimate smoothing.FilterEstimate, sectors []*SectorOnChainInfo) ([]abi.TokenAmount, abi.TokenAmount) {
+	fees := make([]abi.TokenAmount, len(sectors))
 	totalFee := big.Zero()
-	for _, s := range sectors {
+	for i, s := range sectors {
 		sectorPower := QAPowerForSector(sectorSize, s)
-		fee := PledgePenaltyForTermination(s.ExpectedDayReward, currEpoch-s.Activation, s.ExpectedStoragePledge,
+		fees[i] = PledgePenaltyForTermination(s.ExpectedDayReward, currEpoch-s.Activation, s.ExpectedStoragePledge,
 			networkQAPowerEstimate, sectorPower, rewardEstimate, s.ReplacedDayReward, s.ReplacedSectorAge)
-		totalFee = big.Add(fee, totalFee)
+		totalFee = big.Add(fees[i], totalFee)
 	}
-	return totalFee
+	return fees, totalFee
 }
 
 func PowerForSector(sectorSize abi.SectorSize, sector *SectorOnChainInfo) PowerPair {
@@ -1222,6 +1222,190 @@ func TestAggregateProveCommit(t *testing.T) {
 	})
 }
 
+func TestCancelPreCommits(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+
+	setup := func(t *testing.T) (*actorHarness, *mock.Runtime, abi.ChainEpoch) {
+		actor := newHarness(t, periodOffset)
+		rt := builderForHarness(actor).
+			WithBalance(bigBalance, big.Zero()).
+			Build(t)
+		precommitEpoch := periodOffset + 1
+		rt.SetEpoch(precommitEpoch)
+		actor.constructAndVerify(rt)
+		dlInfo := actor.deadline(rt)
+		expiration := dlInfo.PeriodEnd() + defaultSectorExpiration*miner.WPoStProvingPeriod
+		return actor, rt, expiration
+	}
+
+	t.Run("cancels pre-commits and burns penalty", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		challengeEpoch := rt.Epoch() - 1
+
+		precommits := []*miner.SectorPreCommitOnChainInfo{
+			actor.preCommitSector(rt, actor.makePreCommit(100, challengeEpoch, expiration, nil), preCommitConf{}, true),
+			actor.preCommitSector(rt, actor.makePreCommit(101, challengeEpoch, expiration, nil), preCommitConf{}, false),
+		}
+		rt.SetEpoch(rt.Epoch() + 1)
+		remaining := actor.preCommitSector(rt, actor.makePreCommit(102, challengeEpoch, expiration, nil), preCommitConf{}, false)
+
+		expectedPenalty := big.Zero()
+		for _, precommit := range precommits {
+			expectedPenalty = big.Add(expectedPenalty, miner.PreCommitCancellationPenalty(precommit.PreCommitDeposit))
+		}
+		assert.True(t, expectedPenalty.GreaterThan(big.Zero()))
+		balanceBefore := rt.Balance()
+
+		actor.cancelPreCommits(rt, bitfield.NewFromSet([]uint64{100, 101}), expectedPenalty)
+
+		st := getState(rt)
+		assert.Equal(t, remaining.PreCommitDeposit, st.PreCommitDeposits)
+		assert.Equal(t, big.Sub(balanceBefore, expectedPenalty), rt.Balance())
+		for _, sectorNo := range []abi.SectorNumber{100, 101} {
+			_, found, err := st.GetPrecommittedSector(rt.AdtStore(), sectorNo)
+			require.NoError(t, err)
+			assert.False(t, found)
+		}
+
+		// Only the remaining pre-commit is scheduled for clean up.
+		expirations := actor.collectPrecommitExpirations(rt, st)
+		expectedCleanUp := st.QuantSpecEveryDeadline().QuantizeUp(remaining.PreCommitEpoch + miner.MaxProveCommitDuration[actor.sealProofType] + miner.ExpiredPreCommitCleanUpDelay)
+		assert.Equal(t, map[abi.ChainEpoch][]uint64{expectedCleanUp: {102}}, expirations)
+		actor.checkState(rt)
+
+		// Cancelled sector numbers may be pre-committed again.
+		actor.preCommitSector(rt, actor.makePreCommit(100, challengeEpoch, expiration, nil), preCommitConf{}, false)
+		actor.checkState(rt)
+	})
+
+	t.Run("queued proof of a cancelled pre-commit does not confirm its replacement", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		precommit := actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, nil), preCommitConf{}, true)
+
+		// Submit a proof, queueing it in the power actor for batch verification.
+		rt.SetEpoch(precommit.PreCommitEpoch + miner.PreCommitChallengeDelay + 1)
+		actor.proveCommitSector(rt, precommit, makeProveCommit(100))
+
+		actor.cancelPreCommits(rt, bitfield.NewFromSet([]uint64{100}), miner.PreCommitCancellationPenalty(precommit.PreCommitDeposit))
+
+		// A different pre-commitment reuses the sector number in the same epoch.
+		replacement := actor.makePreCommit(100, rt.Epoch()-1, expiration, nil)
+		replacement.SealedCID = tutil.MakeCID("replacement", &miner.SealedCIDPrefix)
+		actor.preCommitSector(rt, replacement, preCommitConf{}, false)
+
+		// The queued proof confirms no sector.
+		rt.SetCaller(builtin.StoragePowerActorAddr, builtin.StoragePowerActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StoragePowerActorAddr)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "all prove commits failed", func() {
+			rt.Call(actor.a.ConfirmSectorProofsValid, &builtin.ConfirmSectorProofsParams{
+				Sectors:                 []abi.SectorNumber{100},
+				RewardSmoothed:          actor.epochRewardSmooth,
+				RewardBaselinePower:     actor.baselinePower,
+				QualityAdjPowerSmoothed: actor.epochQAPowerSmooth,
+			})
+		})
+		rt.Reset()
+
+		st := getState(rt)
+		_, found, err := st.GetSector(rt.AdtStore(), 100)
+		require.NoError(t, err)
+		assert.False(t, found)
+		pending, found, err := st.GetPrecommittedSector(rt.AdtStore(), 100)
+		require.NoError(t, err)
+		require.True(t, found)
+		assert.Equal(t, replacement.SealedCID, pending.Info.SealedCID)
+		actor.checkState(rt)
+	})
+
+	t.Run("cancels pre-commit at prove-commit deadline", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		precommit := actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, nil), preCommitConf{}, true)
+
+		rt.SetEpoch(precommit.PreCommitEpoch + miner.MaxProveCommitDuration[actor.sealProofType])
+		actor.cancelPreCommits(rt, bitfield.NewFromSet([]uint64{100}), miner.PreCommitCancellationPenalty(precommit.PreCommitDeposit))
+
+		st := getState(rt)
+		assert.Equal(t, big.Zero(), st.PreCommitDeposits)
+		assert.Empty(t, actor.collectPrecommitExpirations(rt, st))
+		actor.checkState(rt)
+	})
+
+	t.Run("clears the pre-commitment of cancelled deals", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		precommit := actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, []abi.DealID{1, 2}), preCommitConf{
+			dealWeight: big.NewInt(1),
+			dealSpace:  1,
+		}, true)
+
+		// The harness expects the market to be notified of the cancelled deals.
+		rt.SetEpoch(rt.Epoch() + 1)
+		actor.cancelPreCommits(rt, bitfield.NewFromSet([]uint64{100}), miner.PreCommitCancellationPenalty(precommit.PreCommitDeposit))
+		actor.checkState(rt)
+	})
+
+	t.Run("clears the pre-commitment of deals when a pre-commit expires", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		precommit := actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, []abi.DealID{1, 2}), preCommitConf{
+			dealWeight: big.NewInt(1),
+			dealSpace:  1,
+		}, true)
+
+		cleanUpEpoch := precommit.PreCommitEpoch + miner.MaxProveCommitDuration[actor.sealProofType] + miner.ExpiredPreCommitCleanUpDelay
+		dlInfo := actor.deadline(rt)
+		for dlInfo.Open <= cleanUpEpoch {
+			dlInfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+		rt.SetEpoch(dlInfo.Last())
+		actor.onDeadlineCron(rt, &cronConfig{
+			noEnrollment:            true,
+			expiredPrecommitPenalty: precommit.PreCommitDeposit,
+			expiredPrecommitDeals:   []abi.DealID{1, 2},
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to cancel expired pre-commit", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		precommit := actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, nil), preCommitConf{}, true)
+
+		rt.SetEpoch(precommit.PreCommitEpoch + miner.MaxProveCommitDuration[actor.sealProofType] + 1)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "expired", func() {
+			actor.cancelPreCommits(rt, bitfield.NewFromSet([]uint64{100}), big.Zero())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to cancel missing pre-commit", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, nil), preCommitConf{}, true)
+
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.cancelPreCommits(rt, bitfield.NewFromSet([]uint64{100, 101}), big.Zero())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails with no sectors", func(t *testing.T) {
+		actor, rt, _ := setup(t)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no sectors", func() {
+			actor.cancelPreCommits(rt, bitfield.New(), big.Zero())
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if caller is not a control address", func(t *testing.T) {
+		actor, rt, expiration := setup(t)
+		actor.preCommitSector(rt, actor.makePreCommit(100, rt.Epoch()-1, expiration, nil), preCommitConf{}, true)
+
+		rt.SetCaller(tutil.NewIDAddr(t, 1234), builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.a.CancelPreCommits, &miner.CancelPreCommitsParams{Sectors: bitfield.NewFromSet([]uint64{100})})
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestBatchMethodNetworkFees(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 
@@ -44,7 +44,8 @@ type State struct {
 	// PreCommittedSectorsCleanUp maintains the state required to cleanup expired PreCommittedSectors.
 	PreCommittedSectorsCleanUp cid.Cid // BitFieldQueue (AMT[Epoch]*BitField)
 
-	// Allocated sector IDs. Sector IDs can never be reused once allocated.
+	// Allocated sector IDs. Sector IDs can never be reused once allocated, except those
+	// of pre-commitments cancelled before being proven.
 	AllocatedSectors cid.Cid // BitField
 
 	// Information for all proven and not-yet-garbage-collected sectors.
@@ -79,6 +80,11 @@ type State struct {
 
 	// True when miner cron is active, false otherwise
 	DeadlineCronActive bool
+
+	// Lifecycle events of proven and not-yet-garbage-collected sectors, recorded only while
+	// enabled by the owner. Nil when recording is disabled.
+	// Histories are removed along with their sectors when a partition is compacted.
+	SectorHistory *cid.Cid // Array, AMT[SectorNumber]SectorHistory (sparse)
 }
 
 // Bitwidth of AMTs determined empirically from mutation patterns and projections of mainnet data.
@@ -126,6 +132,10 @@ type MinerInfo struct {
 	// A proposed new owner account for this miner.
 	// Must be confirmed by a message from the pending address itself.
 	PendingOwnerAddress *addr.Address
+
+	// Amount of unlocked funds, set by the owner, which withdrawals may not draw down.
+	// Retaining unlocked funds allows fee debt to be repaid automatically rather than accruing.
+	MinimumUnlockedReserve abi.TokenAmount
 }
 
 type WorkerKeyChange struct {
@@ -232,6 +242,7 @@ func ConstructState(store adt.Store, infoCid cid.Cid, periodStart abi.ChainEpoch
 		Deadlines:                  emptyDeadlinesCid,
 		EarlyTerminations:          bitfield.New(),
 		DeadlineCronActive:         false,
+		SectorHistory:              nil,
 	}, nil
 }
 
@@ -259,6 +270,7 @@ func ConstructMinerInfo(owner, worker addr.Address, controlAddrs []addr.Address,
 		WindowPoStPartitionSectors: partitionSectors,
 		ConsensusFaultElapsed:      abi.ChainEpoch(-1),
 		PendingOwnerAddress:        nil,
+		MinimumUnlockedReserve:     big.Zero(),
 	}, nil
 }
 
@@ -343,6 +355,37 @@ func (st *State) AllocateSectorNumbers(store adt.Store, sectorNos bitfield.BitFi
 	return nil
 }
 
+// Unmarks a set of sector numbers as allocated, so that they may be allocated again.
+// Fails if any of the sector numbers is not currently allocated.
+func (st *State) DeallocateSectorNumbers(store adt.Store, sectorNos bitfield.BitField) error {
+	var priorAllocation bitfield.BitField
+	if err := store.Get(store.Context(), st.AllocatedSectors, &priorAllocation); err != nil {
+		return xc.ErrIllegalState.Wrapf("failed to load allocated sectors bitfield: %w", err)
+	}
+
+	unallocated, err := bitfield.SubtractBitField(sectorNos, priorAllocation)
+	if err != nil {
+		return xerrors.Errorf("failed to subtract allocated sector numbers: %w", err)
+	}
+	if empty, err := unallocated.IsEmpty(); err != nil {
+		return xerrors.Errorf("failed to check if difference is empty: %w", err)
+	} else if !empty {
+		return xc.ErrIllegalArgument.Wrapf("sector numbers %v not allocated", unallocated)
+	}
+
+	newAllocation, err := bitfield.SubtractBitField(priorAllocation, sectorNos)
+	if err != nil {
+		return xc.ErrIllegalState.Wrapf("failed to subtract mask from allocated bitfield: %w", err)
+	}
+
+	if root, err := store.Put(store.Context(), newAllocation); err != nil {
+		return xc.ErrIllegalState.Wrapf("failed to store allocated sectors bitfield after removing %v: %w", sectorNos, err)
+	} else {
+		st.AllocatedSectors = root
+	}
+	return nil
+}
+
 // Stores a pre-committed sector info, failing if the sector number is already present.
 func (st *State) PutPrecommittedSectors(store adt.Store, precommits ...*SectorPreCommitOnChainInfo) error {
 	precommitted, err := adt.AsMap(store, st.PreCommittedSectors, builtin.DefaultHamtBitwidth)
@@ -502,7 +545,11 @@ func (st *State) DeleteSectors(store adt.Store, sectorNos bitfield.BitField) err
 	}
 
 	st.Sectors, err = sectors.Root()
-	return err
+	if err != nil {
+		return err
+	}
+
+	return st.removeSectorHistories(store, sectorNos)
 }
 
 // Iterates sectors.
@@ -519,6 +566,88 @@ func (st *State) ForEachSector(store adt.Store, f func(*SectorOnChainInfo)) erro
 	})
 }
 
+// Starts recording sector lifecycle events, if not already recording.
+func (st *State) EnableSectorHistory(store adt.Store) error {
+	if st.SectorHistory != nil {
+		return nil
+	}
+	emptyHistoryCid, err := adt.StoreEmptyArray(store, SectorHistoryAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to construct empty sector history: %w", err)
+	}
+	st.SectorHistory = &emptyHistoryCid
+	return nil
+}
+
+// Stops recording sector lifecycle events, discarding all recorded history.
+func (st *State) DisableSectorHistory() {
+	st.SectorHistory = nil
+}
+
+// Returns the recorded lifecycle events of a sector.
+// Returns false if history recording is disabled or no events have been recorded for the sector.
+func (st *State) GetSectorHistory(store adt.Store, sectorNo abi.SectorNumber) (*SectorHistory, bool, error) {
+	if st.SectorHistory == nil {
+		return nil, false, nil
+	}
+	histories, err := LoadSectorHistories(store, *st.SectorHistory)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load sector history: %w", err)
+	}
+	return histories.Get(sectorNo)
+}
+
+// Records a lifecycle event for each sector in the map. Does nothing if history recording is disabled.
+func (st *State) RecordSectorEvents(store adt.Store, events map[abi.SectorNumber]SectorEvent) error {
+	if st.SectorHistory == nil || len(events) == 0 {
+		return nil
+	}
+	histories, err := LoadSectorHistories(store, *st.SectorHistory)
+	if err != nil {
+		return xerrors.Errorf("failed to load sector history: %w", err)
+	}
+	if err = histories.Append(events); err != nil {
+		return err
+	}
+	root, err := histories.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to persist sector history: %w", err)
+	}
+	st.SectorHistory = &root
+	return nil
+}
+
+// Records the same lifecycle event for every sector in a set. Does nothing if history recording is disabled.
+func (st *State) RecordUniformSectorEvents(store adt.Store, sectorNos bitfield.BitField, event SectorEvent) error {
+	if st.SectorHistory == nil {
+		return nil
+	}
+	events, err := uniformSectorEvents(sectorNos, event)
+	if err != nil {
+		return xerrors.Errorf("failed to expand sector events: %w", err)
+	}
+	return st.RecordSectorEvents(store, events)
+}
+
+func (st *State) removeSectorHistories(store adt.Store, sectorNos bitfield.BitField) error {
+	if st.SectorHistory == nil {
+		return nil
+	}
+	histories, err := LoadSectorHistories(store, *st.SectorHistory)
+	if err != nil {
+		return xerrors.Errorf("failed to load sector history: %w", err)
+	}
+	if err = histories.Remove(sectorNos); err != nil {
+		return err
+	}
+	root, err := histories.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to persist sector history: %w", err)
+	}
+	st.SectorHistory = &root
+	return nil
+}
+
 func (st *State) FindSector(store adt.Store, sno abi.SectorNumber) (uint64, uint64, error) {
 	deadlines, err := st.LoadDeadlines(store)
 	if err != nil {
@@ -701,6 +830,30 @@ func (st *State) CheckSectorActive(store adt.Store, dlIdx, pIdx uint64, sector a
 	return true, nil
 }
 
+// Loads the numbers of sectors that have terminated (or expired) but not yet been compacted away, across all partitions.
+func (st *State) LoadTerminatedSectors(store adt.Store) (bitfield.BitField, error) {
+	deadlines, err := st.LoadDeadlines(store)
+	if err != nil {
+		return bitfield.BitField{}, err
+	}
+
+	var terminated []bitfield.BitField
+	if err := deadlines.ForEach(store, func(dlIdx uint64, dl *Deadline) error {
+		partitions, err := dl.PartitionsArray(store)
+		if err != nil {
+			return err
+		}
+		var partition Partition
+		return partitions.ForEach(&partition, func(partIdx int64) error {
+			terminated = append(terminated, partition.Terminated)
+			return nil
+		})
+	}); err != nil {
+		return bitfield.BitField{}, xerrors.Errorf("failed to iterate partitions: %w", err)
+	}
+	return bitfield.MultiMerge(terminated...)
+}
+
 // Loads sector info for a sequence of sectors.
 func (st *State) LoadSectorInfos(store adt.Store, sectors bitfield.BitField) ([]*SectorOnChainInfo, error) {
 	sectorsArr, err := LoadSectors(store, st.Sectors)
@@ -1022,24 +1175,57 @@ func (st *State) AddPreCommitCleanUps(store adt.Store, cleanUpEvents map[abi.Cha
 	return nil
 }
 
-func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEpoch) (depositToBurn abi.TokenAmount, err error) {
+// Removes sector numbers from the pre-commit clean up queue, keyed by the (unquantized) epoch at which
+// they were scheduled for clean up.
+func (st *State) RemovePreCommitCleanUps(store adt.Store, cleanUpEvents map[abi.ChainEpoch][]uint64) error {
+	quant := st.QuantSpecEveryDeadline()
+	queue, err := LoadBitfieldQueue(store, st.PreCommittedSectorsCleanUp, quant, PrecommitCleanUpAmtBitwidth)
+	if err != nil {
+		return xerrors.Errorf("failed to load pre-commit clean up queue: %w", err)
+	}
+
+	// Sort the epoch keys for stable iteration when manipulating the queue
+	epochs := make([]abi.ChainEpoch, len(cleanUpEvents))
+	i := 0
+	for cleanUpEpoch := range cleanUpEvents { // nolint: nomaprange
+		epochs[i] = cleanUpEpoch
+		i++
+	}
+	sort.Slice(epochs, func(i, j int) bool {
+		return epochs[i] < epochs[j]
+	})
+
+	for _, cleanUpEpoch := range epochs {
+		if err := queue.RemoveFromQueue(cleanUpEpoch, bitfield.NewFromSet(cleanUpEvents[cleanUpEpoch])); err != nil {
+			return xerrors.Errorf("failed to remove pre-commit sector clean up from queue: %w", err)
+		}
+	}
+
+	st.PreCommittedSectorsCleanUp, err = queue.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to save pre-commit sector queue: %w", err)
+	}
+	return nil
+}
+
+func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEpoch) (depositToBurn abi.TokenAmount, dealIDs []abi.DealID, err error) {
 	depositToBurn = abi.NewTokenAmount(0)
 
 	// cleanup expired pre-committed sectors
 	cleanUpQ, err := LoadBitfieldQueue(store, st.PreCommittedSectorsCleanUp, st.QuantSpecEveryDeadline(), PrecommitCleanUpAmtBitwidth)
 	if err != nil {
-		return depositToBurn, xerrors.Errorf("failed to load sector expiry queue: %w", err)
+		return depositToBurn, nil, xerrors.Errorf("failed to load sector expiry queue: %w", err)
 	}
 
 	sectors, modified, err := cleanUpQ.PopUntil(currEpoch)
 	if err != nil {
-		return depositToBurn, xerrors.Errorf("failed to pop expired sectors: %w", err)
+		return depositToBurn, nil, xerrors.Errorf("failed to pop expired sectors: %w", err)
 	}
 
 	if modified {
 		st.PreCommittedSectorsCleanUp, err = cleanUpQ.Root()
 		if err != nil {
-			return depositToBurn, xerrors.Errorf("failed to save pre commit clean up queue: %w", err)
+			return depositToBurn, nil, xerrors.Errorf("failed to save pre commit clean up queue: %w", err)
 		}
 	}
 
@@ -1060,25 +1246,26 @@ func (st *State) CleanUpExpiredPreCommits(store adt.Store, currEpoch abi.ChainEp
 
 		// increment deposit to burn
 		depositToBurn = big.Add(depositToBurn, sector.PreCommitDeposit)
+		dealIDs = append(dealIDs, sector.Info.DealIDs...)
 		return nil
 	}); err != nil {
-		return big.Zero(), xerrors.Errorf("failed to check pre-commit expiries: %w", err)
+		return big.Zero(), nil, xerrors.Errorf("failed to check pre-commit expiries: %w", err)
 	}
 
 	// Actually delete it.
 	if len(precommitsToDelete) > 0 {
 		if err := st.DeletePrecommittedSectors(store, precommitsToDelete...); err != nil {
-			return big.Zero(), fmt.Errorf("failed to delete pre-commits: %w", err)
+			return big.Zero(), nil, fmt.Errorf("failed to delete pre-commits: %w", err)
 		}
 	}
 
 	st.PreCommitDeposits = big.Sub(st.PreCommitDeposits, depositToBurn)
 	if st.PreCommitDeposits.LessThan(big.Zero()) {
-		return big.Zero(), xerrors.Errorf("pre-commit clean up caused negative deposits: %v", st.PreCommitDeposits)
+		return big.Zero(), nil, xerrors.Errorf("pre-commit clean up caused negative deposits: %v", st.PreCommitDeposits)
 	}
 
 	// This deposit was locked separately to pledge collateral so there's no pledge change here.
-	return depositToBurn, nil
+	return depositToBurn, dealIDs, nil
 }
 
 type AdvanceDeadlineResult struct {
@@ -1163,6 +1350,14 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 		// Detect and penalize missing proofs.
 		faultExpiration := dlInfo.Last() + FaultMaxAge
 
+		// Only collected when recording sector history, since it requires a pass over the partitions.
+		var missedSectors bitfield.BitField
+		if st.SectorHistory != nil {
+			if missedSectors, err = deadline.MissedPoStSectors(store); err != nil {
+				return nil, xerrors.Errorf("failed to load sectors missing proof in deadline %d: %w", dlInfo.Index, err)
+			}
+		}
+
 		// detectedFaultyPower is new faults and failed recoveries
 		powerDelta, detectedFaultyPower, err = deadline.ProcessDeadlineEnd(store, quant, faultExpiration, st.Sectors)
 		if err != nil {
@@ -1171,6 +1366,10 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 		// Capture deadline's faulty power after new faults have been detected, but before it is
 		// dropped along with faulty sectors expiring this round.
 		totalFaultyPower = deadline.FaultyPower
+
+		if err = st.RecordUniformSectorEvents(store, missedSectors, SectorEvent{Type: SectorEventFaultDetected, Epoch: dlInfo.Last()}); err != nil {
+			return nil, xerrors.Errorf("failed to record detected faults: %w", err)
+		}
 	}
 	{
 		// Expire sectors that are due, either for on-time expiration or "early" faulty-for-too-long.
@@ -1187,6 +1386,11 @@ func (st *State) AdvanceDeadline(store adt.Store, currEpoch abi.ChainEpoch) (*Ad
 			return nil, xerrors.Errorf("failed to reduce %v initial pledge for expiring sectors: %w", expired.OnTimePledge, err)
 		}
 
+		// Sectors expiring early are recorded as terminated when the early termination is processed.
+		if err = st.RecordUniformSectorEvents(store, expired.OnTimeSectors, SectorEvent{Type: SectorEventExpired, Epoch: dlInfo.Last()}); err != nil {
+			return nil, xerrors.Errorf("failed to record sector expirations: %w", err)
+		}
+
 		// Record reduction in power of the amount of expiring active power.
 		// Faulty power has already been lost, so the amount expiring can be excluded from the delta.
 		powerDelta = powerDelta.Sub(expired.ActivePower)
@@ -135,6 +135,80 @@ func TestSectorsStore(t *testing.T) {
 	})
 }
 
+func TestSectorHistoryStore(t *testing.T) {
+	t.Run("records nothing while disabled", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+
+		err := harness.s.RecordUniformSectorEvents(harness.store, bf(1, 2), miner.SectorEvent{Type: miner.SectorEventFaultDeclared, Epoch: 10})
+		require.NoError(t, err)
+		assert.Nil(t, harness.s.SectorHistory)
+
+		_, found, err := harness.s.GetSectorHistory(harness.store, 1)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("appends events in order", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		require.NoError(t, harness.s.EnableSectorHistory(harness.store))
+
+		sealed := tutils.MakeCID("1", &miner.SealedCIDPrefix)
+		activation := miner.SectorEvent{Type: miner.SectorEventActivated, Epoch: 5, NewSealedCID: &sealed, Expiration: 1000}
+		require.NoError(t, harness.s.RecordSectorEvents(harness.store, map[abi.SectorNumber]miner.SectorEvent{1: activation}))
+		fault := miner.SectorEvent{Type: miner.SectorEventFaultDeclared, Epoch: 10}
+		require.NoError(t, harness.s.RecordUniformSectorEvents(harness.store, bf(1, 2), fault))
+
+		assert.Equal(t, []miner.SectorEvent{activation, fault}, harness.getSectorHistory(1).Events)
+		assert.Equal(t, []miner.SectorEvent{fault}, harness.getSectorHistory(2).Events)
+	})
+
+	t.Run("drops oldest events beyond maximum", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		require.NoError(t, harness.s.EnableSectorHistory(harness.store))
+
+		for i := 0; i < miner.SectorHistoryEventsMax+2; i++ {
+			event := miner.SectorEvent{Type: miner.SectorEventExtended, Epoch: abi.ChainEpoch(i), Expiration: abi.ChainEpoch(1000 + i)}
+			require.NoError(t, harness.s.RecordSectorEvents(harness.store, map[abi.SectorNumber]miner.SectorEvent{1: event}))
+		}
+
+		events := harness.getSectorHistory(1).Events
+		require.Len(t, events, miner.SectorHistoryEventsMax)
+		assert.Equal(t, abi.ChainEpoch(2), events[0].Epoch)
+		assert.Equal(t, abi.ChainEpoch(miner.SectorHistoryEventsMax+1), events[len(events)-1].Epoch)
+	})
+
+	t.Run("history is deleted with sectors", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		require.NoError(t, harness.s.EnableSectorHistory(harness.store))
+
+		for _, sectorNo := range []abi.SectorNumber{1, 2} {
+			harness.putSector(newSectorOnChainInfo(sectorNo, tutils.MakeCID("1", &miner.SealedCIDPrefix), big.NewInt(1), abi.ChainEpoch(1)))
+		}
+		require.NoError(t, harness.s.RecordUniformSectorEvents(harness.store, bf(1, 2), miner.SectorEvent{Type: miner.SectorEventTerminated, Epoch: 10}))
+
+		harness.deleteSectors(1)
+		_, found, err := harness.s.GetSectorHistory(harness.store, 1)
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Len(t, harness.getSectorHistory(2).Events, 1)
+	})
+
+	t.Run("disabling discards history", func(t *testing.T) {
+		harness := constructStateHarness(t, abi.ChainEpoch(0))
+		require.NoError(t, harness.s.EnableSectorHistory(harness.store))
+		require.NoError(t, harness.s.RecordUniformSectorEvents(harness.store, bf(1), miner.SectorEvent{Type: miner.SectorEventTerminated, Epoch: 10}))
+
+		harness.s.DisableSectorHistory()
+		assert.Nil(t, harness.s.SectorHistory)
+
+		// re-enabling starts from empty history
+		require.NoError(t, harness.s.EnableSectorHistory(harness.store))
+		_, found, err := harness.s.GetSectorHistory(harness.store, 1)
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
 // TODO minerstate: move to partition
 //func TestRecoveriesBitfield(t *testing.T) {
 //	t.Run("Add new recoveries happy path", func(t *testing.T) {
@@ -933,6 +1007,13 @@ func (h *stateHarness) deleteSectors(sectorNos ...uint64) {
 	require.NoError(h.t, err)
 }
 
+func (h *stateHarness) getSectorHistory(sectorNo abi.SectorNumber) *miner.SectorHistory {
+	history, found, err := h.s.GetSectorHistory(h.store, sectorNo)
+	require.NoError(h.t, err)
+	require.True(h.t, found)
+	return history
+}
+
 //
 // Precommit Store Operations
 //
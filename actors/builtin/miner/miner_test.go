@@ -1799,6 +1799,85 @@ func TestDeclareRecoveries(t *testing.T) {
 	})
 }
 
+func TestDeclareBySector(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("declares faults and recoveries located on chain", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sectors := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		missing := abi.SectorNumber(999)
+		sectorNos := bf(uint64(sectors[0].SectorNumber), uint64(sectors[1].SectorNumber), uint64(missing))
+		ret := actor.declareFaultsBySector(rt, sectorNos, sectors)
+		assertBitfieldEquals(t, ret.Declared, uint64(sectors[0].SectorNumber), uint64(sectors[1].SectorNumber))
+		assert.Equal(t, []miner.SectorDeclarationError{{SectorNumber: missing, Code: exitcode.ErrNotFound}}, ret.Errors)
+
+		dlIdx, pIdx, err := getState(rt).FindSector(rt.AdtStore(), sectors[0].SectorNumber)
+		require.NoError(t, err)
+		pwr := miner.PowerForSectors(actor.sectorSize, sectors)
+		dl := actor.getDeadline(rt, dlIdx)
+		assert.True(t, pwr.Equals(dl.FaultyPower))
+
+		ret = actor.declareFaultsRecoveredBySector(rt, bf(uint64(sectors[0].SectorNumber)), big.Zero())
+		assertBitfieldEquals(t, ret.Declared, uint64(sectors[0].SectorNumber))
+		assert.Empty(t, ret.Errors)
+
+		p, err := actor.getDeadline(rt, dlIdx).LoadPartition(rt.AdtStore(), pIdx)
+		require.NoError(t, err)
+		assertBitfieldEquals(t, p.Recoveries, uint64(sectors[0].SectorNumber))
+		actor.checkState(rt)
+	})
+
+	t.Run("reports sectors in immutable deadline", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sectors := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		// advance into the sector's deadline, past its fault cutoff
+		dlIdx, _, err := getState(rt).FindSector(rt.AdtStore(), sectors[0].SectorNumber)
+		require.NoError(t, err)
+		dlinfo := actor.deadline(rt)
+		for dlinfo.Index != dlIdx {
+			dlinfo = advanceDeadline(rt, actor, &cronConfig{})
+		}
+
+		ret := actor.declareFaultsBySector(rt, bf(uint64(sectors[0].SectorNumber)), nil)
+		assertBitfieldEquals(t, ret.Declared)
+		assert.Equal(t, []miner.SectorDeclarationError{{SectorNumber: sectors[0].SectorNumber, Code: exitcode.ErrForbidden}}, ret.Errors)
+
+		dl := actor.getDeadline(rt, dlIdx)
+		assert.True(t, dl.FaultyPower.IsZero())
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects empty declaration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "no sectors declared", func() {
+			rt.Call(actor.a.DeclareFaultsBySector, &miner.DeclareFaultsBySectorParams{Sectors: bf()})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects too many sectors", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		sectorNos := seq(t, 0, miner.AddressedSectorsMax+1)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "too many sectors", func() {
+			rt.Call(actor.a.DeclareFaultsRecoveredBySector, &miner.DeclareFaultsRecoveredBySectorParams{Sectors: sectorNos})
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestExtendSectorExpiration(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2111,6 +2190,168 @@ func TestExtendSectorExpiration(t *testing.T) {
 	})
 }
 
+func TestExtendSectorDeals(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	extension := func(dealID abi.DealID, newEnd abi.ChainEpoch) market.ClientDealExtension {
+		return market.ClientDealExtension{Extension: market.DealExtension{DealID: dealID, NewEndEpoch: newEnd}}
+	}
+
+	t.Run("adds extended deal weight to sector power", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10, 11}}, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		weights := &market.ExtendDealsReturn{
+			DealWeight:         big.NewInt(1 << 20),
+			VerifiedDealWeight: big.NewInt(1 << 30),
+		}
+		actor.extendSectorDeals(rt, sector.SectorNumber, weights, extension(10, sector.Expiration), extension(11, sector.Expiration))
+
+		updated := actor.getSector(rt, sector.SectorNumber)
+		assert.Equal(t, big.Add(sector.DealWeight, weights.DealWeight), updated.DealWeight)
+		assert.Equal(t, big.Add(sector.VerifiedDealWeight, weights.VerifiedDealWeight), updated.VerifiedDealWeight)
+		assert.Equal(t, sector.Expiration, updated.Expiration)
+		actor.checkState(rt)
+	})
+
+	t.Run("repays fee debt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10}}, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		st := getState(rt)
+		st.FeeDebt = abi.NewTokenAmount(1e18)
+		rt.ReplaceState(st)
+
+		weights := &market.ExtendDealsReturn{
+			DealWeight:         big.NewInt(1 << 20),
+			VerifiedDealWeight: big.Zero(),
+		}
+		actor.extendSectorDeals(rt, sector.SectorNumber, weights, extension(10, sector.Expiration))
+		assert.Equal(t, big.Zero(), getState(rt).FeeDebt)
+		actor.checkState(rt)
+	})
+
+	t.Run("locks increased initial pledge and recomputes expected rewards", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10}}, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+		st := getState(rt)
+		pledgeBefore := st.InitialPledge
+
+		// Verify the whole remaining sector spacetime, multiplying its power.
+		weights := &market.ExtendDealsReturn{
+			DealWeight:         big.Zero(),
+			VerifiedDealWeight: big.Mul(big.NewIntUnsigned(uint64(actor.sectorSize)), big.NewInt(int64(sector.Expiration-sector.Activation))),
+		}
+		actor.extendSectorDeals(rt, sector.SectorNumber, weights, extension(10, sector.Expiration))
+
+		updated := actor.getSector(rt, sector.SectorNumber)
+		pwr := miner.QAPowerForSector(actor.sectorSize, updated)
+		assert.True(t, updated.InitialPledge.GreaterThan(sector.InitialPledge))
+		assert.Equal(t, miner.InitialPledgeForPower(pwr, actor.baselinePower, actor.epochRewardSmooth, actor.epochQAPowerSmooth, rt.TotalFilCircSupply()), updated.InitialPledge)
+		assert.Equal(t, miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, pwr, builtin.EpochsInDay), updated.ExpectedDayReward)
+		assert.Equal(t, miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, pwr, miner.InitialPledgeProjectionPeriod), updated.ExpectedStoragePledge)
+
+		st = getState(rt)
+		assert.Equal(t, big.Add(pledgeBefore, big.Sub(updated.InitialPledge, sector.InitialPledge)), st.InitialPledge)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails without funds for increased initial pledge", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10}}, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		st := getState(rt)
+		unlocked, err := st.GetUnlockedBalance(rt.Balance())
+		require.NoError(t, err)
+		rt.SetBalance(big.Sub(rt.Balance(), unlocked))
+
+		weights := &market.ExtendDealsReturn{
+			DealWeight:         big.Zero(),
+			VerifiedDealWeight: big.Mul(big.NewIntUnsigned(uint64(actor.sectorSize)), big.NewInt(int64(sector.Expiration-sector.Activation))),
+		}
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ExtendDeals, &market.ExtendDealsParams{
+			SectorExpiry: sector.Expiration,
+			Extensions:   []market.ClientDealExtension{extension(10, sector.Expiration)},
+		}, big.Zero(), weights, exitcode.Ok)
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectAbortContainsMessage(exitcode.ErrInsufficientFunds, "insufficient funds for new initial pledge", func() {
+			rt.Call(actor.a.ExtendSectorDeals, &miner.ExtendSectorDealsParams{
+				Sector:     sector.SectorNumber,
+				Extensions: []market.ClientDealExtension{extension(10, sector.Expiration)},
+			})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects deal not in sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10}}, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "is not in sector", func() {
+			rt.Call(actor.a.ExtendSectorDeals, &miner.ExtendSectorDealsParams{
+				Sector:     sector.SectorNumber,
+				Extensions: []market.ClientDealExtension{extension(11, sector.Expiration)},
+			})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects unknown sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.a.ExtendSectorDeals, &miner.ExtendSectorDealsParams{
+				Sector:     100,
+				Extensions: []market.ClientDealExtension{extension(10, 1000)},
+			})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects faulty sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, [][]abi.DealID{{10}}, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+		actor.declareFaults(rt, sector)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(append(actor.controlAddrs, actor.owner, actor.worker)...)
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ExtendDeals, &market.ExtendDealsParams{
+			SectorExpiry: sector.Expiration,
+			Extensions:   []market.ClientDealExtension{extension(10, sector.Expiration)},
+		}, big.Zero(), &market.ExtendDealsReturn{DealWeight: big.Zero(), VerifiedDealWeight: big.Zero()}, exitcode.Ok)
+		expectQueryNetworkInfo(rt, actor)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "inactive sector", func() {
+			rt.Call(actor.a.ExtendSectorDeals, &miner.ExtendSectorDealsParams{
+				Sector:     sector.SectorNumber,
+				Extensions: []market.ClientDealExtension{extension(10, sector.Expiration)},
+			})
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestTerminateSectors(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2196,6 +2437,56 @@ func TestTerminateSectors(t *testing.T) {
 
 }
 
+func TestGetTerminationFees(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(big.Mul(big.NewInt(1e18), big.NewInt(200000)), big.Zero())
+
+	t.Run("quotes the fee charged by termination", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectors := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+		actor.applyRewards(rt, bigRewards, big.Zero())
+
+		sectorNos := bf(uint64(sectors[0].SectorNumber), uint64(sectors[1].SectorNumber))
+		quote := actor.getTerminationFees(rt, sectorNos)
+
+		expectedFees, expectedTotal := miner.TerminationFees(actor.sectorSize, rt.Epoch(), actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectors)
+		require.Len(t, quote.Fees, 2)
+		for i, sector := range sectors {
+			assert.Equal(t, sector.SectorNumber, quote.Fees[i].SectorNumber)
+			assert.Equal(t, expectedFees[i], quote.Fees[i].Fee)
+		}
+		assert.True(t, quote.Total.GreaterThan(big.Zero()))
+		assert.Equal(t, expectedTotal, quote.Total)
+		assert.Equal(t, big.Add(quote.Fees[0].Fee, quote.Fees[1].Fee), quote.Total)
+
+		// Terminating in the same epoch charges exactly the quoted fee.
+		actor.terminateSectors(rt, sectorNos, quote.Total)
+
+		// Terminated sectors can no longer be quoted.
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "already terminated", func() {
+			actor.getTerminationFees(rt, bf(uint64(sectors[0].SectorNumber)))
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to quote unknown sector", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectors := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			actor.getTerminationFees(rt, bf(uint64(sectors[0].SectorNumber), uint64(sectors[0].SectorNumber+1)))
+		})
+		actor.checkState(rt)
+	})
+}
+
 func TestWithdrawBalance(t *testing.T) {
 	periodOffset := abi.ChainEpoch(100)
 	actor := newHarness(t, periodOffset)
@@ -2240,6 +2531,297 @@ func TestWithdrawBalance(t *testing.T) {
 		actor.withdrawFunds(rt, requested, expectedWithdraw, feeDebt)
 		actor.checkState(rt)
 	})
+
+	t.Run("withdraw leaves minimum unlocked reserve", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		reserve := big.Sub(bigBalance, onePercentBalance)
+		actor.changeMinimumUnlockedReserve(rt, reserve)
+
+		// Only funds in excess of the reserve can be withdrawn.
+		actor.withdrawFunds(rt, bigBalance, onePercentBalance, big.Zero())
+		assert.Equal(t, reserve, rt.Balance())
+		actor.checkState(rt)
+
+		// Removing the reserve makes the remainder available.
+		actor.changeMinimumUnlockedReserve(rt, big.Zero())
+		actor.withdrawFunds(rt, bigBalance, reserve, big.Zero())
+		actor.checkState(rt)
+	})
+
+	t.Run("reserve is available to repay fee debt", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		reserve := big.Sub(bigBalance, onePercentBalance)
+		actor.changeMinimumUnlockedReserve(rt, reserve)
+
+		st := getState(rt)
+		st.FeeDebt = onePercentBalance
+		rt.ReplaceState(st)
+
+		// Fee debt is repaid first, then the reserve leaves nothing further to withdraw.
+		actor.withdrawFunds(rt, onePercentBalance, big.Zero(), onePercentBalance)
+		st = getState(rt)
+		assert.True(t, st.IsDebtFree())
+		actor.checkState(rt)
+	})
+}
+
+func TestChangeMinimumUnlockedReserve(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("owner sets reserve", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		info := actor.getInfo(rt)
+		assert.Equal(t, big.Zero(), info.MinimumUnlockedReserve)
+
+		actor.changeMinimumUnlockedReserve(rt, big.Div(bigBalance, big.NewInt(2)))
+		actor.checkState(rt)
+	})
+
+	t.Run("worker cannot set reserve", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.owner)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.a.ChangeMinimumUnlockedReserve, &miner.ChangeMinimumUnlockedReserveParams{NewReserve: big.NewInt(1)})
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("rejects negative reserve", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(actor.owner, builtin.AccountActorCodeID)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "invalid minimum unlocked reserve", func() {
+			rt.Call(actor.a.ChangeMinimumUnlockedReserve, &miner.ChangeMinimumUnlockedReserveParams{NewReserve: big.NewInt(-1)})
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestSectorHistory(t *testing.T) {
+	periodOffset := abi.ChainEpoch(100)
+	actor := newHarness(t, periodOffset)
+	builder := builderForHarness(actor).
+		WithBalance(bigBalance, big.Zero())
+
+	t.Run("not recorded by default", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		rt.SetEpoch(abi.ChainEpoch(1))
+		actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)
+
+		assert.Nil(t, getState(rt).SectorHistory)
+		actor.checkState(rt)
+	})
+
+	t.Run("records activation, extension and termination", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.changeSectorHistoryRecording(rt, true)
+
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		st := getState(rt)
+		dlIdx, pIdx, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+
+		extendEpoch := rt.Epoch()
+		newExpiration := sector.Expiration + miner.WPoStProvingPeriod
+		actor.extendSectors(rt, &miner.ExtendSectorExpirationParams{
+			Extensions: []miner.ExpirationExtension{{
+				Deadline:      dlIdx,
+				Partition:     pIdx,
+				Sectors:       bf(uint64(sector.SectorNumber)),
+				NewExpiration: newExpiration,
+			}},
+		})
+
+		// Termination fees are paid from locked funds.
+		actor.applyRewards(rt, bigRewards, big.Zero())
+
+		sector = actor.getSector(rt, sector.SectorNumber)
+		sectorSize, err := sector.SealProof.SectorSize()
+		require.NoError(t, err)
+		sectorPower := miner.QAPowerForSector(sectorSize, sector)
+		dayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, builtin.EpochsInDay)
+		twentyDayReward := miner.ExpectedRewardForPower(actor.epochRewardSmooth, actor.epochQAPowerSmooth, sectorPower, miner.InitialPledgeProjectionPeriod)
+		sectorAge := rt.Epoch() - sector.Activation
+		expectedFee := miner.PledgePenaltyForTermination(dayReward, sectorAge, twentyDayReward, actor.epochQAPowerSmooth, sectorPower, actor.epochRewardSmooth, big.Zero(), 0)
+		actor.terminateSectors(rt, bf(uint64(sector.SectorNumber)), expectedFee)
+
+		events := actor.getSectorHistory(rt, sector.SectorNumber).Events
+		require.Len(t, events, 3)
+
+		assert.Equal(t, miner.SectorEventActivated, events[0].Type)
+		assert.Equal(t, sector.Activation, events[0].Epoch)
+		assert.Equal(t, sector.SealedCID, *events[0].NewSealedCID)
+		assert.Nil(t, events[0].OldSealedCID)
+
+		assert.Equal(t, miner.SectorEventExtended, events[1].Type)
+		assert.Equal(t, extendEpoch, events[1].Epoch)
+		assert.Equal(t, newExpiration, events[1].Expiration)
+
+		assert.Equal(t, miner.SectorEventTerminated, events[2].Type)
+		assert.Equal(t, rt.Epoch(), events[2].Epoch)
+		actor.checkState(rt)
+	})
+
+	t.Run("records declared faults and recoveries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.changeSectorHistoryRecording(rt, true)
+
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		faultEpoch := rt.Epoch()
+		actor.declareFaults(rt, sector)
+
+		dlIdx, pIdx, err := getState(rt).FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		actor.declareRecoveries(rt, dlIdx, pIdx, bf(uint64(sector.SectorNumber)), big.Zero())
+
+		events := actor.getSectorHistory(rt, sector.SectorNumber).Events
+		require.Len(t, events, 3)
+		assert.Equal(t, miner.SectorEventActivated, events[0].Type)
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventFaultDeclared, Epoch: faultEpoch}, events[1])
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventRecoveryDeclared, Epoch: faultEpoch}, events[2])
+		actor.checkState(rt)
+	})
+
+	t.Run("records detected faults and proven recoveries", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.changeSectorHistoryRecording(rt, true)
+
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+		pwr := miner.PowerForSector(actor.sectorSize, sector)
+
+		dlIdx, pIdx, err := getState(rt).FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+
+		// Miss the PoSt, so cron detects the sector faulty.
+		dlinfo := advanceToDeadline(rt, actor, dlIdx)
+		detectedEpoch := dlinfo.Last()
+		pwrDelta := pwr.Neg()
+		advanceDeadline(rt, actor, &cronConfig{detectedFaultsPowerDelta: &pwrDelta})
+
+		declareEpoch := rt.Epoch()
+		actor.declareRecoveries(rt, dlIdx, pIdx, bf(uint64(sector.SectorNumber)), big.Zero())
+
+		dlinfo = advanceToDeadline(rt, actor, dlIdx)
+		actor.submitWindowPoSt(rt, dlinfo, []miner.PoStPartition{{Index: pIdx, Skipped: bitfield.New()}},
+			[]*miner.SectorOnChainInfo{sector}, &poStConfig{expectedPowerDelta: pwr})
+
+		events := actor.getSectorHistory(rt, sector.SectorNumber).Events
+		require.Len(t, events, 4)
+		assert.Equal(t, miner.SectorEventActivated, events[0].Type)
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventFaultDetected, Epoch: detectedEpoch}, events[1])
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventRecoveryDeclared, Epoch: declareEpoch}, events[2])
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventRecovered, Epoch: rt.Epoch()}, events[3])
+		actor.checkState(rt)
+	})
+
+	t.Run("records skipped faults", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.changeSectorHistoryRecording(rt, true)
+
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sectors := actor.commitAndProveSectors(rt, 2, defaultSectorExpiration, nil, true)
+		advanceAndSubmitPoSts(rt, actor, sectors...)
+
+		dlIdx, pIdx, err := getState(rt).FindSector(rt.AdtStore(), sectors[0].SectorNumber)
+		require.NoError(t, err)
+		dlinfo := advanceToDeadline(rt, actor, dlIdx)
+		actor.submitWindowPoSt(rt, dlinfo, []miner.PoStPartition{{Index: pIdx, Skipped: bf(uint64(sectors[0].SectorNumber))}},
+			sectors, &poStConfig{expectedPowerDelta: miner.PowerForSectors(actor.sectorSize, sectors[:1]).Neg()})
+
+		events := actor.getSectorHistory(rt, sectors[0].SectorNumber).Events
+		require.Len(t, events, 2)
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventFaultDetected, Epoch: rt.Epoch()}, events[1])
+		assert.Len(t, actor.getSectorHistory(rt, sectors[1].SectorNumber).Events, 1)
+		actor.checkState(rt)
+	})
+
+	t.Run("records expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.changeSectorHistoryRecording(rt, true)
+
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		advanceAndSubmitPoSts(rt, actor, sector)
+
+		// Skip forward in state to the sector's expiration.
+		st := getState(rt)
+		initialPledge := st.InitialPledge
+		dlIdx, _, err := st.FindSector(rt.AdtStore(), sector.SectorNumber)
+		require.NoError(t, err)
+		expiration := st.QuantSpecForDeadline(dlIdx).QuantizeUp(sector.Expiration)
+		remainingPeriods := (expiration-st.ProvingPeriodStart)/miner.WPoStProvingPeriod + 1
+		st.ProvingPeriodStart += remainingPeriods * miner.WPoStProvingPeriod
+		st.CurrentDeadline = dlIdx
+		rt.ReplaceState(st)
+
+		rt.SetEpoch(expiration)
+		powerDelta := miner.PowerForSector(actor.sectorSize, sector).Neg()
+		// because we skip forward in state the sector is detected faulty, no penalty
+		advanceDeadline(rt, actor, &cronConfig{
+			noEnrollment:              true,
+			expiredSectorsPowerDelta:  &powerDelta,
+			expiredSectorsPledgeDelta: initialPledge.Neg(),
+		})
+
+		events := actor.getSectorHistory(rt, sector.SectorNumber).Events
+		require.Len(t, events, 3)
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventFaultDetected, Epoch: expiration}, events[1])
+		assert.Equal(t, miner.SectorEvent{Type: miner.SectorEventExpired, Epoch: expiration}, events[2])
+		actor.checkState(rt)
+	})
+
+	t.Run("disabling discards history", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+		actor.changeSectorHistoryRecording(rt, true)
+
+		rt.SetEpoch(abi.ChainEpoch(1))
+		sector := actor.commitAndProveSectors(rt, 1, defaultSectorExpiration, nil, true)[0]
+		assert.Len(t, actor.getSectorHistory(rt, sector.SectorNumber).Events, 1)
+
+		actor.changeSectorHistoryRecording(rt, false)
+		actor.checkState(rt)
+	})
+
+	t.Run("only owner may change recording", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt)
+
+		rt.SetCaller(actor.worker, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.owner)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.a.ChangeSectorHistoryRecording, &miner.ChangeSectorHistoryRecordingParams{Enabled: true})
+		})
+		assert.Nil(t, getState(rt).SectorHistory)
+		actor.checkState(rt)
+	})
 }
 
 func TestRepayDebts(t *testing.T) {
@@ -3928,6 +4510,8 @@ func (h *actorHarness) preCommitSector(rt *mock.Runtime, params *miner.PreCommit
 			}},
 		}
 		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.VerifyDealsForActivation, &vdParams, big.Zero(), &vdReturn, exitcode.Ok)
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerSectorsPreCommit,
+			&market.OnMinerSectorsPreCommitParams{DealIDs: params.DealIDs}, big.Zero(), nil, exitcode.Ok)
 	} else {
 		// Ensure the deal IDs and configured deal weight returns are consistent.
 		require.Equal(h.t, abi.SectorSize(0), conf.dealSpace, "no deals but positive deal space configured")
@@ -3966,12 +4550,14 @@ func (h *actorHarness) preCommitSectorBatch(rt *mock.Runtime, params *miner.PreC
 	}
 	sectorDeals := make([]market.SectorDeals, len(params.Sectors))
 	sectorWeights := make([]market.SectorWeights, len(params.Sectors))
+	var dealIDs []abi.DealID
 	anyDeals := false
 	for i, sector := range params.Sectors {
 		sectorDeals[i] = market.SectorDeals{
 			SectorExpiry: sector.Expiration,
 			DealIDs:      sector.DealIDs,
 		}
+		dealIDs = append(dealIDs, sector.DealIDs...)
 
 		if len(conf.sectorWeights) > i {
 			sectorWeights[i] = conf.sectorWeights[i]
@@ -3997,6 +4583,8 @@ func (h *actorHarness) preCommitSectorBatch(rt *mock.Runtime, params *miner.PreC
 			Sectors: sectorWeights,
 		}
 		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.VerifyDealsForActivation, &vdParams, big.Zero(), &vdReturn, exitcode.Ok)
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerSectorsPreCommit,
+			&market.OnMinerSectorsPreCommitParams{DealIDs: dealIDs}, big.Zero(), nil, exitcode.Ok)
 	}
 	st := getState(rt)
 	// burn networkFee
@@ -4162,21 +4750,31 @@ func (h *actorHarness) proveCommitAggregateSector(rt *mock.Runtime, conf proveCo
 func (h *actorHarness) confirmSectorProofsValidInternal(rt *mock.Runtime, conf proveCommitConf, precommits ...*miner.SectorPreCommitOnChainInfo) {
 	// Prepare for and receive call to ConfirmSectorProofsValid.
 	var validPrecommits []*miner.SectorPreCommitOnChainInfo
+	var activations []market.SectorDealsActivation
+	activated := bitfield.New()
+	var activatedWeights []market.SectorDealsActivated
 	for _, precommit := range precommits {
-		validPrecommits = append(validPrecommits, precommit)
 		if len(precommit.Info.DealIDs) > 0 {
-			vdParams := market.ActivateDealsParams{
-				DealIDs:      precommit.Info.DealIDs,
+			activations = append(activations, market.SectorDealsActivation{
+				SectorType:   precommit.Info.SealProof,
 				SectorExpiry: precommit.Info.Expiration,
+				DealIDs:      precommit.Info.DealIDs,
+			})
+			if _, found := conf.verifyDealsExit[precommit.Info.SectorNumber]; found {
+				continue
 			}
-			exit, found := conf.verifyDealsExit[precommit.Info.SectorNumber]
-			if found {
-				validPrecommits = validPrecommits[:len(validPrecommits)-1] // pop
-			} else {
-				exit = exitcode.Ok
-			}
-			rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ActivateDeals, &vdParams, big.Zero(), nil, exit)
+			activated.Set(uint64(len(activations) - 1))
+			activatedWeights = append(activatedWeights, market.SectorDealsActivated{
+				DealWeight:         big.Zero(),
+				VerifiedDealWeight: big.Zero(),
+			})
 		}
+		validPrecommits = append(validPrecommits, precommit)
+	}
+	if len(activations) > 0 {
+		baParams := market.BatchActivateDealsParams{Sectors: activations}
+		baRet := market.BatchActivateDealsReturn{ActivatedSectors: activated, Sectors: activatedWeights}
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.BatchActivateDeals, &baParams, big.Zero(), &baRet, exitcode.Ok)
 	}
 
 	// expected pledge is the sum of initial pledges
@@ -4611,6 +5209,37 @@ func (h *actorHarness) declareRecoveries(rt *mock.Runtime, deadlineIdx uint64, p
 	rt.Verify()
 }
 
+func (h *actorHarness) declareFaultsBySector(rt *mock.Runtime, sectorNos bitfield.BitField, expectedFaults []*miner.SectorOnChainInfo) *miner.SectorDeclarationsReturn {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	if len(expectedFaults) > 0 {
+		expectedRawDelta, expectedQADelta := powerForSectors(h.sectorSize, expectedFaults)
+		claim := &power.UpdateClaimedPowerParams{
+			RawByteDelta:         expectedRawDelta.Neg(),
+			QualityAdjustedDelta: expectedQADelta.Neg(),
+		}
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdateClaimedPower, claim, abi.NewTokenAmount(0), nil, exitcode.Ok)
+	}
+
+	ret := rt.Call(h.a.DeclareFaultsBySector, &miner.DeclareFaultsBySectorParams{Sectors: sectorNos}).(*miner.SectorDeclarationsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *actorHarness) declareFaultsRecoveredBySector(rt *mock.Runtime, sectorNos bitfield.BitField, expectedDebtRepaid abi.TokenAmount) *miner.SectorDeclarationsReturn {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	if expectedDebtRepaid.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedDebtRepaid, nil, exitcode.Ok)
+	}
+
+	ret := rt.Call(h.a.DeclareFaultsRecoveredBySector, &miner.DeclareFaultsRecoveredBySectorParams{Sectors: sectorNos}).(*miner.SectorDeclarationsReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) extendSectors(rt *mock.Runtime, params *miner.ExtendSectorExpirationParams) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4645,6 +5274,58 @@ func (h *actorHarness) extendSectors(rt *mock.Runtime, params *miner.ExtendSecto
 	rt.Verify()
 }
 
+func (h *actorHarness) extendSectorDeals(rt *mock.Runtime, sectorNo abi.SectorNumber, weights *market.ExtendDealsReturn,
+	extensions ...market.ClientDealExtension) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	sector := h.getSector(rt, sectorNo)
+	rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.ExtendDeals, &market.ExtendDealsParams{
+		SectorExpiry: sector.Expiration,
+		Extensions:   extensions,
+	}, big.Zero(), weights, exitcode.Ok)
+	expectQueryNetworkInfo(rt, h)
+
+	if st := getState(rt); st.FeeDebt.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, st.FeeDebt, nil, exitcode.Ok)
+	}
+
+	newSector := *sector
+	newSector.DealWeight = big.Add(sector.DealWeight, weights.DealWeight)
+	newSector.VerifiedDealWeight = big.Add(sector.VerifiedDealWeight, weights.VerifiedDealWeight)
+	newPower := miner.QAPowerForSector(h.sectorSize, &newSector)
+	newPledge := miner.InitialPledgeForPower(newPower, h.baselinePower, h.epochRewardSmooth, h.epochQAPowerSmooth, rt.TotalFilCircSupply())
+	if newPledge.GreaterThan(sector.InitialPledge) {
+		pledgeDelta := big.Sub(newPledge, sector.InitialPledge)
+		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil, exitcode.Ok)
+	}
+	qaDelta := big.Sub(newPower, miner.QAPowerForSector(h.sectorSize, sector))
+	if !qaDelta.IsZero() {
+		rt.ExpectSend(builtin.StoragePowerActorAddr,
+			builtin.MethodsPower.UpdateClaimedPower,
+			&power.UpdateClaimedPowerParams{
+				RawByteDelta:         big.Zero(),
+				QualityAdjustedDelta: qaDelta,
+			},
+			abi.NewTokenAmount(0),
+			nil,
+			exitcode.Ok,
+		)
+	}
+	rt.Call(h.a.ExtendSectorDeals, &miner.ExtendSectorDealsParams{Sector: sectorNo, Extensions: extensions})
+	rt.Verify()
+}
+
+func (h *actorHarness) getTerminationFees(rt *mock.Runtime, sectors bitfield.BitField) *miner.GetTerminationFeesReturn {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+	expectQueryNetworkInfo(rt, h)
+
+	ret := rt.Call(h.a.GetTerminationFees, &miner.GetTerminationFeesParams{Sectors: sectors}).(*miner.GetTerminationFeesReturn)
+	rt.Verify()
+	return ret
+}
+
 func (h *actorHarness) terminateSectors(rt *mock.Runtime, sectors bitfield.BitField, expectedFee abi.TokenAmount) (miner.PowerPair, abi.TokenAmount) {
 	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
@@ -4795,6 +5476,7 @@ type cronConfig struct {
 	expiredSectorsPledgeDelta abi.TokenAmount
 	continuedFaultsPenalty    abi.TokenAmount // Expected amount burnt to pay continued fault penalties.
 	expiredPrecommitPenalty   abi.TokenAmount // Expected amount burnt to pay for expired precommits
+	expiredPrecommitDeals     []abi.DealID    // Expected deals of expired precommits to be cleared from the market
 	repaidFeeDebt             abi.TokenAmount // Expected amount burnt to repay fee debt.
 	penaltyFromUnlocked       abi.TokenAmount // Expected reduction in unlocked balance from penalties exceeding vesting funds.
 }
@@ -4860,6 +5542,11 @@ func (h *actorHarness) onDeadlineCron(rt *mock.Runtime, config *cronConfig) {
 		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.UpdatePledgeTotal, &pledgeDelta, big.Zero(), nil, exitcode.Ok)
 	}
 
+	if len(config.expiredPrecommitDeals) > 0 {
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerPreCommitsRemoved,
+			&market.OnMinerPreCommitsRemovedParams{DealIDs: config.expiredPrecommitDeals}, big.Zero(), nil, exitcode.Ok)
+	}
+
 	// Re-enrollment for next period.
 	if !config.noEnrollment {
 		rt.ExpectSend(builtin.StoragePowerActorAddr, builtin.MethodsPower.EnrollCronEvent,
@@ -4883,7 +5570,9 @@ func (h *actorHarness) withdrawFunds(rt *mock.Runtime, amountRequested, expected
 	rt.SetCaller(h.owner, builtin.AccountActorCodeID)
 	rt.ExpectValidateCallerAddr(h.owner)
 
-	rt.ExpectSend(h.owner, builtin.MethodSend, nil, expectedWithdrawn, nil, exitcode.Ok)
+	if expectedWithdrawn.GreaterThan(big.Zero()) {
+		rt.ExpectSend(h.owner, builtin.MethodSend, nil, expectedWithdrawn, nil, exitcode.Ok)
+	}
 	if expectedDebtRepaid.GreaterThan(big.Zero()) {
 		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedDebtRepaid, nil, exitcode.Ok)
 	}
@@ -4896,7 +5585,37 @@ func (h *actorHarness) withdrawFunds(rt *mock.Runtime, amountRequested, expected
 
 	rt.Verify()
 
-	assert.Equal(h.t, expectedWithdrawn, *withdrawn, "return value indicates %s withdrawn but expected %s", *withdrawn, expectedWithdrawn)
+	assert.True(h.t, expectedWithdrawn.Equals(*withdrawn), "return value indicates %s withdrawn but expected %s", *withdrawn, expectedWithdrawn)
+}
+
+func (h *actorHarness) changeMinimumUnlockedReserve(rt *mock.Runtime, reserve abi.TokenAmount) {
+	rt.SetCaller(h.owner, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.owner)
+
+	rt.Call(h.a.ChangeMinimumUnlockedReserve, &miner.ChangeMinimumUnlockedReserveParams{NewReserve: reserve})
+	rt.Verify()
+
+	info := h.getInfo(rt)
+	assert.Equal(h.t, reserve, info.MinimumUnlockedReserve)
+}
+
+func (h *actorHarness) changeSectorHistoryRecording(rt *mock.Runtime, enabled bool) {
+	rt.SetCaller(h.owner, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.owner)
+
+	rt.Call(h.a.ChangeSectorHistoryRecording, &miner.ChangeSectorHistoryRecordingParams{Enabled: enabled})
+	rt.Verify()
+
+	st := getState(rt)
+	assert.Equal(h.t, enabled, st.SectorHistory != nil)
+}
+
+func (h *actorHarness) getSectorHistory(rt *mock.Runtime, sno abi.SectorNumber) *miner.SectorHistory {
+	st := getState(rt)
+	history, found, err := st.GetSectorHistory(rt.AdtStore(), sno)
+	require.NoError(h.t, err)
+	require.True(h.t, found)
+	return history
 }
 
 func (h *actorHarness) repayDebt(rt *mock.Runtime, value, expectedRepayedFromVest, expectedRepaidFromBalance abi.TokenAmount) {
@@ -4919,6 +5638,31 @@ func (h *actorHarness) repayDebt(rt *mock.Runtime, value, expectedRepayedFromVes
 	rt.Verify()
 }
 
+func (h *actorHarness) cancelPreCommits(rt *mock.Runtime, sectorNos bitfield.BitField, expectedPenalty abi.TokenAmount) {
+	rt.SetCaller(h.worker, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(append(h.controlAddrs, h.owner, h.worker)...)
+
+	if expectedPenalty.GreaterThan(big.Zero()) {
+		rt.ExpectSend(builtin.BurntFundsActorAddr, builtin.MethodSend, nil, expectedPenalty, nil, exitcode.Ok)
+	}
+	var dealIDs []abi.DealID
+	st := getState(rt)
+	err := sectorNos.ForEach(func(sno uint64) error {
+		precommit, found, err := st.GetPrecommittedSector(rt.AdtStore(), abi.SectorNumber(sno))
+		if found {
+			dealIDs = append(dealIDs, precommit.Info.DealIDs...)
+		}
+		return err
+	})
+	require.NoError(h.t, err)
+	if len(dealIDs) > 0 {
+		rt.ExpectSend(builtin.StorageMarketActorAddr, builtin.MethodsMarket.OnMinerPreCommitsRemoved,
+			&market.OnMinerPreCommitsRemovedParams{DealIDs: dealIDs}, big.Zero(), nil, exitcode.Ok)
+	}
+	rt.Call(h.a.CancelPreCommits, &miner.CancelPreCommitsParams{Sectors: sectorNos})
+	rt.Verify()
+}
+
 func (h *actorHarness) compactPartitions(rt *mock.Runtime, deadline uint64, partitions bitfield.BitField) {
 	param := miner.CompactPartitionsParams{Deadline: deadline, Partitions: partitions}
 
@@ -53,6 +53,13 @@ var TerminationRewardFactor = builtin.BigFrac{ // PARAM_SPEC
 	Denominator: big.NewInt(2),
 }
 
+// Fraction of pre-commit deposit penalized when a pre-commitment is voluntarily cancelled before it expires.
+// This is less than the full deposit lost when a pre-commitment expires unproven.
+var PreCommitCancellationPenaltyFactor = builtin.BigFrac{ // PARAM_SPEC
+	Numerator:   big.NewInt(1),
+	Denominator: big.NewInt(4),
+}
+
 // Maximum number of lifetime days penalized when a sector is terminated.
 const TerminationLifetimeCap = 140 // PARAM_SPEC
 
@@ -153,6 +160,14 @@ func PreCommitDepositForPower(rewardEstimate, networkQAPowerEstimate smoothing.F
 	return ExpectedRewardForPowerClampedAtAttoFIL(rewardEstimate, networkQAPowerEstimate, qaSectorPower, PreCommitDepositProjectionPeriod)
 }
 
+// The penalty for cancelling a pre-commitment before it expires, as a fraction of its deposit.
+func PreCommitCancellationPenalty(preCommitDeposit abi.TokenAmount) abi.TokenAmount {
+	return big.Div(
+		big.Mul(preCommitDeposit, PreCommitCancellationPenaltyFactor.Numerator),
+		PreCommitCancellationPenaltyFactor.Denominator,
+	)
+}
+
 // Computes the pledge requirement for committing new quality-adjusted power to the network, given the current
 // network total and baseline power, per-epoch  reward, and circulating token supply.
 // The pledge comprises two parts:
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
 )
 
@@ -134,6 +135,12 @@ func TestPower(t *testing.T) {
 	})
 }
 
+func TestMarketSectorCommitmentPolicy(t *testing.T) {
+	// The market bounds deal duration by these sector policies, which it cannot import.
+	assert.Equal(t, abi.ChainEpoch(miner.MaxSectorExpirationExtension), market.DealMaxSectorCommitment)
+	assert.Equal(t, miner.PreCommitChallengeDelay, market.DealMinSectorActivationDelay)
+}
+
 func weight(size abi.SectorSize, duration abi.ChainEpoch) big.Int {
 	return big.Mul(big.NewIntUnsigned(uint64(size)), big.NewInt(int64(duration)))
 }
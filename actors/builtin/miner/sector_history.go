@@ -0,0 +1,124 @@
+package miner
+
+import (
+	"sort"
+
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/ipfs/go-cid"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// Bitwidth of the sector history AMT, matching the sectors AMT which it shadows.
+const SectorHistoryAmtBitwidth = SectorsAmtBitwidth
+
+// Maximum number of lifecycle events retained for a single sector.
+// When a new event would exceed the bound, the oldest event is dropped.
+const SectorHistoryEventsMax = 32 // PARAM_SPEC
+
+type SectorEventType uint64
+
+const (
+	SectorEventActivated SectorEventType = iota
+	SectorEventReplicaUpdated
+	SectorEventExtended
+	SectorEventFaultDeclared
+	SectorEventRecoveryDeclared
+	SectorEventTerminated
+	SectorEventFaultDetected // A missed or skipped proof
+	SectorEventRecovered     // A proof of a sector declared recovered
+	SectorEventExpired       // Expiration on time
+)
+
+// A single change in the lifecycle of a sector.
+type SectorEvent struct {
+	Type  SectorEventType
+	Epoch abi.ChainEpoch // Epoch at which the event took effect
+	// The sealed CID replaced by the event, set for replica updates only.
+	OldSealedCID *cid.Cid
+	// The sealed CID in effect after the event, set for activations and replica updates.
+	NewSealedCID *cid.Cid
+	// The sector expiration after the event, or zero if unchanged by the event.
+	Expiration abi.ChainEpoch
+}
+
+// The recorded lifecycle events of a sector, oldest first.
+type SectorHistory struct {
+	Events []SectorEvent
+}
+
+func LoadSectorHistories(store adt.Store, root cid.Cid) (SectorHistories, error) {
+	arr, err := adt.AsArray(store, root, SectorHistoryAmtBitwidth)
+	if err != nil {
+		return SectorHistories{}, err
+	}
+	return SectorHistories{arr}, nil
+}
+
+// SectorHistories is a helper type for accessing/modifying a miner's sector histories,
+// an AMT[SectorNumber]SectorHistory (sparse).
+type SectorHistories struct {
+	*adt.Array
+}
+
+func (sh SectorHistories) Get(sectorNumber abi.SectorNumber) (*SectorHistory, bool, error) {
+	var history SectorHistory
+	if found, err := sh.Array.Get(uint64(sectorNumber), &history); err != nil {
+		return nil, false, xerrors.Errorf("failed to get history for sector %d: %w", sectorNumber, err)
+	} else if !found {
+		return nil, false, nil
+	}
+	return &history, true, nil
+}
+
+// Appends events to the histories of the given sectors, dropping each sector's oldest events
+// in excess of SectorHistoryEventsMax.
+func (sh SectorHistories) Append(events map[abi.SectorNumber]SectorEvent) error {
+	// Update each sector in-order to be deterministic.
+	sectorNos := make([]abi.SectorNumber, 0, len(events))
+	for sectorNo := range events { // nolint:nomaprange // subsequently sorted
+		sectorNos = append(sectorNos, sectorNo)
+	}
+	sort.Slice(sectorNos, func(i, j int) bool {
+		return sectorNos[i] < sectorNos[j]
+	})
+
+	for _, sectorNo := range sectorNos {
+		var history SectorHistory
+		if _, err := sh.Array.Get(uint64(sectorNo), &history); err != nil {
+			return xerrors.Errorf("failed to load history for sector %d: %w", sectorNo, err)
+		}
+		history.Events = append(history.Events, events[sectorNo])
+		if len(history.Events) > SectorHistoryEventsMax {
+			history.Events = history.Events[len(history.Events)-SectorHistoryEventsMax:]
+		}
+		if err := sh.Array.Set(uint64(sectorNo), &history); err != nil {
+			return xerrors.Errorf("failed to store history for sector %d: %w", sectorNo, err)
+		}
+	}
+	return nil
+}
+
+// Removes the histories of the given sectors, if present.
+func (sh SectorHistories) Remove(sectorNos bitfield.BitField) error {
+	return sectorNos.ForEach(func(sectorNo uint64) error {
+		if _, err := sh.Array.TryDelete(sectorNo); err != nil {
+			return xerrors.Errorf("failed to delete history for sector %d: %w", sectorNo, err)
+		}
+		return nil
+	})
+}
+
+// Builds an event of the same type and epoch for each sector in a set.
+func uniformSectorEvents(sectorNos bitfield.BitField, event SectorEvent) (map[abi.SectorNumber]SectorEvent, error) {
+	events := make(map[abi.SectorNumber]SectorEvent)
+	if err := sectorNos.ForEach(func(sectorNo uint64) error {
+		events[abi.SectorNumber(sectorNo)] = event
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -89,6 +89,8 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 		acc.RequireNoError(err, "error iterating sectors")
 	}
 
+	CheckSectorHistory(st, store, allSectors, acc)
+
 	// Check deadlines
 	acc.Require(st.CurrentDeadline < WPoStPeriodDeadlines,
 		"current deadline index is greater than deadlines per period(%d): %d", WPoStPeriodDeadlines, st.CurrentDeadline)
@@ -116,6 +118,30 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 	return minerSummary, acc
 }
 
+func CheckSectorHistory(st *State, store adt.Store, allSectors map[abi.SectorNumber]*SectorOnChainInfo, acc *builtin.MessageAccumulator) {
+	if st.SectorHistory == nil {
+		return
+	}
+	histories, err := LoadSectorHistories(store, *st.SectorHistory)
+	if err != nil {
+		acc.Addf("error loading sector history: %v", err)
+		return
+	}
+	var history SectorHistory
+	err = histories.ForEach(&history, func(sno int64) error {
+		_, found := allSectors[abi.SectorNumber(sno)]
+		acc.Require(allSectors == nil || found, "history recorded for sector %d not in sectors", sno)
+		acc.Require(len(history.Events) > 0, "empty history recorded for sector %d", sno)
+		acc.Require(len(history.Events) <= SectorHistoryEventsMax, "history for sector %d has %d events, max %d",
+			sno, len(history.Events), SectorHistoryEventsMax)
+		for _, event := range history.Events {
+			acc.Require(event.Type <= SectorEventExpired, "history for sector %d has unknown event type %d", sno, event.Type)
+		}
+		return nil
+	})
+	acc.RequireNoError(err, "error iterating sector history")
+}
+
 type DeadlineStateSummary struct {
 	AllSectors        bitfield.BitField
 	LiveSectors       bitfield.BitField
@@ -703,6 +729,9 @@ func CheckMinerInfo(info *MinerInfo, acc *builtin.MessageAccumulator) {
 			"pending owner address %v is same as existing owner %v", info.PendingOwnerAddress, info.Owner)
 	}
 
+	acc.Require(!info.MinimumUnlockedReserve.Nil() && info.MinimumUnlockedReserve.GreaterThanEqual(big.Zero()),
+		"minimum unlocked reserve %v is negative", info.MinimumUnlockedReserve)
+
 	windowPoStProofInfo, found := abi.PoStProofInfos[info.WindowPoStProofType]
 	acc.Require(found, "miner has unrecognized Window PoSt proof type %d", info.WindowPoStProofType)
 	if found {
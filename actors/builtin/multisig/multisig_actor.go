@@ -21,16 +21,18 @@ import (
 
 type TxnID = multisig0.TxnID
 
-//type Transaction struct {
-//	To     addr.Address
-//	Value  abi.TokenAmount
-//	Method abi.MethodNum
-//	Params []byte
-//
-//	// This address at index 0 is the transaction proposer, order of this slice must be preserved.
-//	Approved []addr.Address
-//}
-type Transaction = multisig0.Transaction
+type Transaction struct {
+	To     addr.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+
+	// This address at index 0 is the transaction proposer, order of this slice must be preserved.
+	Approved []addr.Address
+
+	// The last epoch at which the transaction may be approved, or zero if the transaction does not expire.
+	Expiration abi.ChainEpoch
+}
 
 // Data for a BLAKE2B-256 to be attached to methods referencing proposals via TXIDs.
 // Ensures the existence of a cryptographic reference to the original proposal. Useful
@@ -47,6 +49,25 @@ type Transaction = multisig0.Transaction
 //}
 type ProposalHashData = multisig0.ProposalHashData
 
+// Hash data for a transaction with an expiration.
+// Transactions which do not expire are hashed as ProposalHashData, so their hashes are unchanged.
+type ExpiringProposalHashData struct {
+	Requester  addr.Address
+	To         addr.Address
+	Value      abi.TokenAmount
+	Method     abi.MethodNum
+	Params     []byte
+	Expiration abi.ChainEpoch
+}
+
+func (phd *ExpiringProposalHashData) Serialize() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := phd.MarshalCBOR(buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 type Actor struct{}
 
 func (a Actor) Exports() []interface{} {
@@ -60,6 +81,13 @@ func (a Actor) Exports() []interface{} {
 		7:                         a.SwapSigner,
 		8:                         a.ChangeNumApprovalsThreshold,
 		9:                         a.LockBalance,
+		10:                        a.ProposeWithExpiration,
+		11:                        a.RemoveExpiredTransactions,
+		12:                        a.ProposeBatch,
+		13:                        a.ExecuteBatch,
+		14:                        a.ChangeSignerWeight,
+		15:                        a.SetApprovalRules,
+		16:                        a.LockVestingSchedule,
 	}
 }
 
@@ -159,10 +187,120 @@ type ProposeReturn = multisig0.ProposeReturn
 
 func (a Actor) Propose(rt runtime.Runtime, params *ProposeParams) *ProposeReturn {
 	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	return a.propose(rt, params.To, params.Value, params.Method, params.Params, 0)
+}
+
+type ProposeWithExpirationParams struct {
+	To     addr.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+	// The last epoch at which the transaction may be approved, or zero if the transaction does not expire.
+	Expiration abi.ChainEpoch
+}
+
+// Proposes a transaction which may no longer be approved after an expiration epoch.
+func (a Actor) ProposeWithExpiration(rt runtime.Runtime, params *ProposeWithExpirationParams) *ProposeReturn {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	validateExpiration(rt, params.Expiration)
+	return a.propose(rt, params.To, params.Value, params.Method, params.Params, params.Expiration)
+}
+
+// A single call within a batch transaction.
+type BatchedCall struct {
+	To     addr.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+}
+
+type ProposeBatchParams struct {
+	Calls []BatchedCall
+	// The last epoch at which the transaction may be approved, or zero if the transaction does not expire.
+	Expiration abi.ChainEpoch
+}
+
+// Proposes a transaction executing a sequence of calls atomically.
+// The batch is proposed as a transaction invoking ExecuteBatch on this multisig, with the calls
+// as its parameters, so its proposal hash takes the usual ProposalHashData form. Signers may review
+// each call by decoding the hash data's Params as ExecuteBatchParams.
+func (a Actor) ProposeBatch(rt runtime.Runtime, params *ProposeBatchParams) *ProposeReturn {
+	rt.ValidateImmediateCallerType(builtin.CallerTypesSignable...)
+	validateExpiration(rt, params.Expiration)
+
+	if len(params.Calls) == 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "batch must contain at least one call")
+	}
+	if len(params.Calls) > BatchCallsMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "batch of %d calls exceeds maximum %d", len(params.Calls), BatchCallsMax)
+	}
+	for i, call := range params.Calls {
+		if call.Value.Sign() < 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "value of call %d must be non-negative, was %v", i, call.Value)
+		}
+	}
+
+	var buf bytes.Buffer
+	err := (&ExecuteBatchParams{Calls: params.Calls}).MarshalCBOR(&buf)
+	builtin.RequireNoErr(rt, err, exitcode.ErrSerialization, "failed to serialize batch")
+
+	return a.propose(rt, rt.Receiver(), big.Zero(), builtin.MethodsMultisig.ExecuteBatch, buf.Bytes(), params.Expiration)
+}
+
+type ExecuteBatchParams struct {
+	Calls []BatchedCall
+}
+
+type ExecuteBatchReturn struct {
+	// The return values of each call, in order.
+	Returns [][]byte
+}
+
+// Executes a batch of calls, aborting if any fails so that the effects of all calls are reverted.
+// Can only be called by the multisig wallet itself, as an approved transaction.
+func (a Actor) ExecuteBatch(rt runtime.Runtime, params *ExecuteBatchParams) *ExecuteBatchReturn {
+	rt.ValidateImmediateCallerIs(rt.Receiver())
+
+	total := big.Zero()
+	for i, call := range params.Calls {
+		if call.Value.Sign() < 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "value of call %d must be non-negative, was %v", i, call.Value)
+		}
+		total = big.Add(total, call.Value)
+	}
+
+	var st State
+	rt.StateReadonly(&st)
+	if err := st.assertAvailable(rt.CurrentBalance(), total, rt.CurrEpoch()); err != nil {
+		rt.Abortf(exitcode.ErrInsufficientFunds, "insufficient funds unlocked: %v", err)
+	}
+
+	returns := make([][]byte, len(params.Calls))
+	for i, call := range params.Calls {
+		var out builtin.CBORBytes
+		code := rt.Send(call.To, call.Method, builtin.CBORBytes(call.Params), call.Value, &out)
+		if !code.IsSuccess() {
+			rt.Abortf(code, "batch call %d to %v method %d failed", i, call.To, call.Method)
+		}
+		returns[i] = out
+	}
+	return &ExecuteBatchReturn{Returns: returns}
+}
+
+func validateExpiration(rt runtime.Runtime, expiration abi.ChainEpoch) {
+	if expiration < 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "negative expiration %d", expiration)
+	}
+	if expiration != 0 && expiration < rt.CurrEpoch() {
+		rt.Abortf(exitcode.ErrIllegalArgument, "expiration %d before current epoch %d", expiration, rt.CurrEpoch())
+	}
+}
+
+func (a Actor) propose(rt runtime.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, expiration abi.ChainEpoch) *ProposeReturn {
 	proposer := rt.Caller()
 
-	if params.Value.Sign() < 0 {
-		rt.Abortf(exitcode.ErrIllegalArgument, "proposed value must be non-negative, was %v", params.Value)
+	if value.Sign() < 0 {
+		rt.Abortf(exitcode.ErrIllegalArgument, "proposed value must be non-negative, was %v", value)
 	}
 
 	var txnID TxnID
@@ -179,11 +317,12 @@ func (a Actor) Propose(rt runtime.Runtime, params *ProposeParams) *ProposeReturn
 		txnID = st.NextTxnID
 		st.NextTxnID += 1
 		txn = &Transaction{
-			To:       params.To,
-			Value:    params.Value,
-			Method:   params.Method,
-			Params:   params.Params,
-			Approved: []addr.Address{},
+			To:         to,
+			Value:      value,
+			Method:     method,
+			Params:     params,
+			Approved:   []addr.Address{},
+			Expiration: expiration,
 		}
 
 		if err := ptx.Put(txnID, txn); err != nil {
@@ -239,6 +378,9 @@ func (a Actor) Approve(rt runtime.Runtime, params *TxnIDParams) *ApproveReturn {
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
 
 		txn = getTransaction(rt, ptx, params.ID, params.ProposalHash)
+		if txn.IsExpired(rt.CurrEpoch()) {
+			rt.Abortf(exitcode.ErrForbidden, "transaction %v expired at epoch %d", params.ID, txn.Expiration)
+		}
 	})
 
 	// if the transaction already has enough approvers, execute it without "processing" this approval.
@@ -318,6 +460,9 @@ func (a Actor) AddSigner(rt runtime.Runtime, params *AddSignerParams) *abi.Empty
 		}
 
 		st.Signers = append(st.Signers, resolvedNewSigner)
+		if len(st.SignerWeights) > 0 {
+			st.SignerWeights = append(st.SignerWeights, 1)
+		}
 		if params.Increase {
 			st.NumApprovalsThreshold = st.NumApprovalsThreshold + 1
 		}
@@ -349,18 +494,23 @@ func (a Actor) RemoveSigner(rt runtime.Runtime, params *RemoveSignerParams) *abi
 		}
 
 		newSigners := make([]addr.Address, 0, len(st.Signers))
+		var newWeights []uint64
 		// signers have already been resolved
-		for _, s := range st.Signers {
+		for i, s := range st.Signers {
 			if resolvedOldSigner != s {
 				newSigners = append(newSigners, s)
+				if len(st.SignerWeights) > 0 {
+					newWeights = append(newWeights, st.SignerWeights[i])
+				}
 			}
 		}
 
-		// if the number of signers is below the threshold after removing the given signer,
+		// if the signers' weight is below the threshold after removing the given signer,
 		// we should decrease the threshold by 1. This means that decrease should NOT be set to false
 		// in such a scenario.
-		if !params.Decrease && uint64(len(st.Signers)-1) < st.NumApprovalsThreshold {
-			rt.Abortf(exitcode.ErrIllegalArgument, "can't reduce signers to %d below threshold %d with decrease=false", len(st.Signers)-1, st.NumApprovalsThreshold)
+		remainingWeight := st.TotalWeight() - st.SignerWeight(resolvedOldSigner)
+		if !params.Decrease && remainingWeight < st.NumApprovalsThreshold {
+			rt.Abortf(exitcode.ErrIllegalArgument, "can't reduce signer weight to %d below threshold %d with decrease=false", remainingWeight, st.NumApprovalsThreshold)
 		}
 
 		if params.Decrease {
@@ -374,6 +524,11 @@ func (a Actor) RemoveSigner(rt runtime.Runtime, params *RemoveSignerParams) *abi
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to purge approvals of removed signer")
 
 		st.Signers = newSigners
+		st.SignerWeights = newWeights
+		st.normalizeSignerWeights()
+
+		err = st.checkThresholdsAttainable()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "can't remove signer %v", resolvedOldSigner)
 	})
 
 	return nil
@@ -406,14 +561,24 @@ func (a Actor) SwapSigner(rt runtime.Runtime, params *SwapSignerParams) *abi.Emp
 			rt.Abortf(exitcode.ErrIllegalArgument, "%s already a signer", toResolved)
 		}
 
+		// The new signer takes the weight of the one it replaces.
+		fromWeight := st.SignerWeight(fromResolved)
 		newSigners := make([]addr.Address, 0, len(st.Signers))
-		for _, s := range st.Signers {
+		var newWeights []uint64
+		for i, s := range st.Signers {
 			if s != fromResolved {
 				newSigners = append(newSigners, s)
+				if len(st.SignerWeights) > 0 {
+					newWeights = append(newWeights, st.SignerWeights[i])
+				}
 			}
 		}
 		newSigners = append(newSigners, toResolved)
+		if len(st.SignerWeights) > 0 {
+			newWeights = append(newWeights, fromWeight)
+		}
 		st.Signers = newSigners
+		st.SignerWeights = newWeights
 
 		err := st.PurgeApprovals(store, fromResolved)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to purge approvals of removed signer")
@@ -433,7 +598,7 @@ func (a Actor) ChangeNumApprovalsThreshold(rt runtime.Runtime, params *ChangeNum
 
 	var st State
 	rt.StateTransaction(&st, func() {
-		if params.NewThreshold == 0 || params.NewThreshold > uint64(len(st.Signers)) {
+		if params.NewThreshold == 0 || params.NewThreshold > st.TotalWeight() {
 			rt.Abortf(exitcode.ErrIllegalArgument, "New threshold value not supported")
 		}
 
@@ -452,22 +617,149 @@ type LockBalanceParams = multisig0.LockBalanceParams
 func (a Actor) LockBalance(rt runtime.Runtime, params *LockBalanceParams) *abi.EmptyValue {
 	// Can only be called by the multisig wallet itself.
 	rt.ValidateImmediateCallerIs(rt.Receiver())
+	lockBalance(rt, params.StartEpoch, params.UnlockDuration, params.Amount, VestingLinear, 0, nil)
+	return nil
+}
 
-	if params.UnlockDuration <= 0 {
+type LockVestingScheduleParams struct {
+	StartEpoch     abi.ChainEpoch
+	UnlockDuration abi.ChainEpoch
+	Amount         abi.TokenAmount
+	Kind           VestingKind
+	// Epochs after StartEpoch before which nothing unlocks, for a cliff schedule.
+	Cliff abi.ChainEpoch
+	// Amounts unlocking at epochs after StartEpoch, for a piecewise schedule.
+	// The amounts must sum to Amount, and the last step must be at UnlockDuration.
+	Steps []VestingStep
+}
+
+// Locks a balance to unlock according to a vesting schedule.
+// As with LockBalance, the locked balance may be set only once.
+func (a Actor) LockVestingSchedule(rt runtime.Runtime, params *LockVestingScheduleParams) *abi.EmptyValue {
+	// Can only be called by the multisig wallet itself.
+	rt.ValidateImmediateCallerIs(rt.Receiver())
+	lockBalance(rt, params.StartEpoch, params.UnlockDuration, params.Amount, params.Kind, params.Cliff, params.Steps)
+	return nil
+}
+
+func lockBalance(rt runtime.Runtime, startEpoch, unlockDuration abi.ChainEpoch, amount abi.TokenAmount, kind VestingKind, cliff abi.ChainEpoch, steps []VestingStep) {
+	if unlockDuration <= 0 {
 		// Note: Unlock duration of zero is workable, but rejected as ineffective, probably an error.
 		rt.Abortf(exitcode.ErrIllegalArgument, "unlock duration must be positive")
 	}
 
-	if params.Amount.LessThan(big.Zero()) {
+	if amount.LessThan(big.Zero()) {
 		rt.Abortf(exitcode.ErrIllegalArgument, "amount to lock must be positive")
 	}
 
+	err := validateVesting(kind, unlockDuration, amount, cliff, steps)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "invalid vesting schedule")
+
 	var st State
 	rt.StateTransaction(&st, func() {
 		if st.UnlockDuration != 0 {
 			rt.Abortf(exitcode.ErrForbidden, "modification of unlock disallowed")
 		}
-		st.SetLocked(params.StartEpoch, params.UnlockDuration, params.Amount)
+		st.SetLocked(startEpoch, unlockDuration, amount)
+		st.SetVesting(kind, cliff, steps)
+	})
+}
+
+type RemoveExpiredTransactionsParams struct {
+	TxnIDs []TxnID
+}
+
+type RemoveExpiredTransactionsReturn struct {
+	// The transactions removed. Transactions which have not expired, or do not exist, are skipped.
+	Removed []TxnID
+}
+
+// Removes pending transactions which have expired. May be called by anyone.
+func (a Actor) RemoveExpiredTransactions(rt runtime.Runtime, params *RemoveExpiredTransactionsParams) *RemoveExpiredTransactionsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	removed := []TxnID{}
+	var st State
+	rt.StateTransaction(&st, func() {
+		ptx, err := adt.AsMap(adt.AsStore(rt), st.PendingTxns, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load pending transactions")
+
+		for _, txnID := range params.TxnIDs {
+			var txn Transaction
+			found, err := ptx.Get(txnID, &txn)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load transaction %v", txnID)
+			if !found || !txn.IsExpired(rt.CurrEpoch()) {
+				continue
+			}
+
+			err = ptx.Delete(txnID)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete transaction %v", txnID)
+			removed = append(removed, txnID)
+		}
+
+		st.PendingTxns, err = ptx.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush pending transactions")
+	})
+
+	return &RemoveExpiredTransactionsReturn{Removed: removed}
+}
+
+type ChangeSignerWeightParams struct {
+	Signer addr.Address
+	Weight uint64
+}
+
+// Sets the approval weight of a signer.
+func (a Actor) ChangeSignerWeight(rt runtime.Runtime, params *ChangeSignerWeightParams) *abi.EmptyValue {
+	// Can only be called by the multisig wallet itself.
+	rt.ValidateImmediateCallerIs(rt.Receiver())
+	signer, err := builtin.ResolveToIDAddr(rt, params.Signer)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to resolve address %v", params.Signer)
+
+	if params.Weight == 0 || params.Weight > SignerWeightMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "signer weight %d must be between 1 and %d", params.Weight, uint64(SignerWeightMax))
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		if !st.IsSigner(signer) {
+			rt.Abortf(exitcode.ErrForbidden, "%s is not a signer", signer)
+		}
+		st.setSignerWeight(signer, params.Weight)
+
+		err := st.checkThresholdsAttainable()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "can't change weight of signer %v to %d", signer, params.Weight)
+	})
+	return nil
+}
+
+type SetApprovalRulesParams struct {
+	Rules []ApprovalRule
+}
+
+// Replaces the rules raising the approval weight required for transactions.
+func (a Actor) SetApprovalRules(rt runtime.Runtime, params *SetApprovalRulesParams) *abi.EmptyValue {
+	// Can only be called by the multisig wallet itself.
+	rt.ValidateImmediateCallerIs(rt.Receiver())
+
+	if len(params.Rules) > ApprovalRulesMax {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot set more than %d approval rules", ApprovalRulesMax)
+	}
+	for i, rule := range params.Rules {
+		if rule.Threshold == 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "approval rule %d must require at least one approval", i)
+		}
+		if rule.MinValue.Sign() < 0 {
+			rt.Abortf(exitcode.ErrIllegalArgument, "approval rule %d minimum value must be non-negative, was %v", i, rule.MinValue)
+		}
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		st.ApprovalRules = params.Rules
+
+		err := st.checkThresholdsAttainable()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "can't set approval rules")
 	})
 	return nil
 }
@@ -526,7 +818,7 @@ func executeTransactionIfApproved(rt runtime.Runtime, st State, txnID TxnID, txn
 	var code exitcode.ExitCode
 	applied := false
 
-	thresholdMet := uint64(len(txn.Approved)) >= st.NumApprovalsThreshold
+	thresholdMet := st.ApprovalWeight(txn.Approved) >= requiredThreshold(rt, &st, txn.To, txn.Method, txn.Value, txn.Params)
 	if thresholdMet {
 		if err := st.assertAvailable(rt.CurrentBalance(), txn.Value, rt.CurrEpoch()); err != nil {
 			rt.Abortf(exitcode.ErrInsufficientFunds, "insufficient funds unlocked: %v", err)
@@ -564,18 +856,77 @@ func executeTransactionIfApproved(rt runtime.Runtime, st State, txnID TxnID, txn
 	return applied, out, code
 }
 
+// Returns the approval weight required for a call.
+// The weight required for a batch is the greatest required by any of its calls, each judged by the total value
+// sent by the batch, so that splitting a transfer among calls does not lower the weight required.
+func requiredThreshold(rt runtime.Runtime, st *State, to addr.Address, method abi.MethodNum, value abi.TokenAmount, params []byte) uint64 {
+	type call struct {
+		self   bool
+		method abi.MethodNum
+	}
+	var calls []call
+	total := big.Zero()
+
+	var collect func(to addr.Address, method abi.MethodNum, value abi.TokenAmount, params []byte)
+	collect = func(to addr.Address, method abi.MethodNum, value abi.TokenAmount, params []byte) {
+		resolved, ok := rt.ResolveAddress(to)
+		self := ok && resolved == rt.Receiver()
+		calls = append(calls, call{self: self, method: method})
+		if !self || method != builtin.MethodsMultisig.ExecuteBatch {
+			total = big.Add(total, value)
+			return
+		}
+
+		// Value sent to execute a batch returns to the multisig, and is counted when its calls send it on.
+		var batch ExecuteBatchParams
+		if err := batch.UnmarshalCBOR(bytes.NewReader(params)); err != nil {
+			rt.Abortf(exitcode.ErrSerialization, "failed to deserialize batch: %v", err)
+		}
+		for _, c := range batch.Calls {
+			collect(c.To, c.Method, c.Value, c.Params)
+		}
+	}
+	collect(to, method, value, params)
+
+	threshold := uint64(0)
+	for _, c := range calls {
+		if t := st.RequiredThreshold(c.self, c.method, total); t > threshold {
+			threshold = t
+		}
+	}
+	return threshold
+}
+
+// Whether a transaction may no longer be approved at an epoch.
+func (t *Transaction) IsExpired(currEpoch abi.ChainEpoch) bool {
+	return t.Expiration != 0 && currEpoch > t.Expiration
+}
+
 // Computes a digest of a proposed transaction. This digest is used to confirm identity of the transaction
 // associated with an ID, which might change under chain re-orgs.
 func ComputeProposalHash(txn *Transaction, hash func([]byte) [32]byte) ([]byte, error) {
-	hashData := ProposalHashData{
-		Requester: txn.Approved[0],
-		To:        txn.To,
-		Value:     txn.Value,
-		Method:    txn.Method,
-		Params:    txn.Params,
+	var data []byte
+	var err error
+	if txn.Expiration == 0 {
+		hashData := ProposalHashData{
+			Requester: txn.Approved[0],
+			To:        txn.To,
+			Value:     txn.Value,
+			Method:    txn.Method,
+			Params:    txn.Params,
+		}
+		data, err = hashData.Serialize()
+	} else {
+		hashData := ExpiringProposalHashData{
+			Requester:  txn.Approved[0],
+			To:         txn.To,
+			Value:      txn.Value,
+			Method:     txn.Method,
+			Params:     txn.Params,
+			Expiration: txn.Expiration,
+		}
+		data, err = hashData.Serialize()
 	}
-
-	data, err := hashData.Serialize()
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct multisig approval hash: %w", err)
 	}
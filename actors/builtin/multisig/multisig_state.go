@@ -22,6 +22,57 @@ type State struct {
 	UnlockDuration abi.ChainEpoch
 
 	PendingTxns cid.Cid // HAMT[TxnID]Transaction
+
+	// Approval weights of each signer, in the order of Signers.
+	// Empty if every signer has weight 1, in which case the threshold counts approvals.
+	SignerWeights []uint64
+	// Rules raising the approval weight required for transactions above NumApprovalsThreshold.
+	ApprovalRules []ApprovalRule
+
+	// The shape of the vesting schedule unlocking InitialBalance over UnlockDuration from StartEpoch.
+	VestingKind VestingKind
+	// Epochs after StartEpoch before which nothing unlocks, for a cliff schedule.
+	VestingCliff abi.ChainEpoch
+	// Amounts unlocking at epochs after StartEpoch, for a piecewise schedule.
+	VestingSteps []VestingStep
+}
+
+type VestingKind uint64
+
+const (
+	// Unlocks linearly over the unlock duration.
+	VestingLinear VestingKind = iota
+	// Unlocks nothing before the cliff, then as for a linear schedule.
+	VestingCliff
+	// Unlocks an amount at each of a sequence of steps, the last at the end of the unlock duration.
+	VestingPiecewise
+)
+
+// An amount unlocking at an epoch offset from the start of a piecewise vesting schedule.
+type VestingStep struct {
+	Offset abi.ChainEpoch
+	Amount abi.TokenAmount
+}
+
+// A rule requiring a minimum approval weight for transactions calling a method, or sending at least some value.
+type ApprovalRule struct {
+	// Whether the rule applies to transactions calling any method.
+	AllMethods bool
+	// The method to which the rule applies, if not all methods.
+	Method abi.MethodNum
+	// Whether the rule applies only to calls to the multisig itself, such as signer changes.
+	// Method numbers are specific to the receiving actor, so a rule for a multisig method
+	// should not also apply to calls to other actors.
+	SelfOnly bool
+	// The minimum value sent by transactions to which the rule applies.
+	MinValue abi.TokenAmount
+	// The approval weight required by transactions to which the rule applies.
+	Threshold uint64
+}
+
+// Tests whether a rule applies to a call, which is to the multisig itself if self is true.
+func (r *ApprovalRule) Matches(self bool, method abi.MethodNum, value abi.TokenAmount) bool {
+	return (self || !r.SelfOnly) && (r.AllMethods || r.Method == method) && value.GreaterThanEqual(r.MinValue)
 }
 
 // Tests whether an address is in the list of signers.
@@ -34,12 +85,105 @@ func (st *State) IsSigner(address address.Address) bool {
 	return false
 }
 
+// Returns the approval weight of a signer, or zero if the address is not a signer.
+func (st *State) SignerWeight(address address.Address) uint64 {
+	for i, signer := range st.Signers {
+		if signer == address {
+			if len(st.SignerWeights) == 0 {
+				return 1
+			}
+			return st.SignerWeights[i]
+		}
+	}
+	return 0
+}
+
+// Returns the sum of the approval weights of all signers.
+func (st *State) TotalWeight() uint64 {
+	if len(st.SignerWeights) == 0 {
+		return uint64(len(st.Signers))
+	}
+	total := uint64(0)
+	for _, w := range st.SignerWeights {
+		total += w
+	}
+	return total
+}
+
+// Returns the sum of the approval weights of a list of approvers.
+func (st *State) ApprovalWeight(approvers []address.Address) uint64 {
+	total := uint64(0)
+	for _, approver := range approvers {
+		total += st.SignerWeight(approver)
+	}
+	return total
+}
+
+// Returns the approval weight required for a call, being the greatest threshold of the rules it matches,
+// and at least NumApprovalsThreshold.
+func (st *State) RequiredThreshold(self bool, method abi.MethodNum, value abi.TokenAmount) uint64 {
+	threshold := st.NumApprovalsThreshold
+	for _, rule := range st.ApprovalRules {
+		if rule.Matches(self, method, value) && rule.Threshold > threshold {
+			threshold = rule.Threshold
+		}
+	}
+	return threshold
+}
+
+// Sets the approval weight of a signer, which must be present.
+func (st *State) setSignerWeight(address address.Address, weight uint64) {
+	if len(st.SignerWeights) == 0 {
+		st.SignerWeights = make([]uint64, len(st.Signers))
+		for i := range st.SignerWeights {
+			st.SignerWeights[i] = 1
+		}
+	}
+	for i, signer := range st.Signers {
+		if signer == address {
+			st.SignerWeights[i] = weight
+		}
+	}
+	st.normalizeSignerWeights()
+}
+
+// Clears the signer weights if every signer has weight 1.
+func (st *State) normalizeSignerWeights() {
+	for _, w := range st.SignerWeights {
+		if w != 1 {
+			return
+		}
+	}
+	st.SignerWeights = nil
+}
+
+// Checks that the signers' total weight can meet every threshold.
+func (st *State) checkThresholdsAttainable() error {
+	total := st.TotalWeight()
+	if st.NumApprovalsThreshold > total {
+		return xerrors.Errorf("threshold %d exceeds total signer weight %d", st.NumApprovalsThreshold, total)
+	}
+	for i, rule := range st.ApprovalRules {
+		if rule.Threshold > total {
+			return xerrors.Errorf("approval rule %d threshold %d exceeds total signer weight %d", i, rule.Threshold, total)
+		}
+	}
+	return nil
+}
+
 func (st *State) SetLocked(startEpoch abi.ChainEpoch, unlockDuration abi.ChainEpoch, lockedAmount abi.TokenAmount) {
 	st.StartEpoch = startEpoch
 	st.UnlockDuration = unlockDuration
 	st.InitialBalance = lockedAmount
 }
 
+// Sets a cliff or piecewise vesting schedule, on top of the linear unlock parameters.
+func (st *State) SetVesting(kind VestingKind, cliff abi.ChainEpoch, steps []VestingStep) {
+	st.VestingKind = kind
+	st.VestingCliff = cliff
+	st.VestingSteps = steps
+}
+
 func (st *State) AmountLocked(elapsedEpoch abi.ChainEpoch) abi.TokenAmount {
 	if elapsedEpoch >= st.UnlockDuration {
 		return abi.NewTokenAmount(0)
@@ -48,6 +192,22 @@ func (st *State) AmountLocked(elapsedEpoch abi.ChainEpoch) abi.TokenAmount {
 		return st.InitialBalance
 	}
 
+	switch st.VestingKind {
+	case VestingCliff:
+		if elapsedEpoch < st.VestingCliff {
+			return st.InitialBalance
+		}
+	case VestingPiecewise:
+		locked := st.InitialBalance
+		for _, step := range st.VestingSteps {
+			if step.Offset > elapsedEpoch {
+				break
+			}
+			locked = big.Sub(locked, step.Amount)
+		}
+		return locked
+	}
+
 	unlockDuration := big.NewInt(int64(st.UnlockDuration))
 	remainingLockDuration := big.Sub(unlockDuration, big.NewInt(int64(elapsedEpoch)))
 
@@ -64,6 +224,51 @@ func (st *State) AmountLocked(elapsedEpoch abi.ChainEpoch) abi.TokenAmount {
 	return locked
 }
 
+// Checks that a vesting schedule is well formed for a locked amount and unlock duration.
+func validateVesting(kind VestingKind, unlockDuration abi.ChainEpoch, amount abi.TokenAmount, cliff abi.ChainEpoch, steps []VestingStep) error {
+	switch kind {
+	case VestingLinear:
+		if cliff != 0 || len(steps) != 0 {
+			return xerrors.Errorf("linear vesting has cliff %d and %d steps", cliff, len(steps))
+		}
+	case VestingCliff:
+		if cliff <= 0 || cliff > unlockDuration {
+			return xerrors.Errorf("vesting cliff %d must be positive and at most unlock duration %d", cliff, unlockDuration)
+		}
+		if len(steps) != 0 {
+			return xerrors.Errorf("cliff vesting has %d steps", len(steps))
+		}
+	case VestingPiecewise:
+		if cliff != 0 {
+			return xerrors.Errorf("piecewise vesting has cliff %d", cliff)
+		}
+		if len(steps) == 0 || len(steps) > VestingStepsMax {
+			return xerrors.Errorf("piecewise vesting must have between 1 and %d steps, has %d", VestingStepsMax, len(steps))
+		}
+		total := big.Zero()
+		prevOffset := abi.ChainEpoch(0)
+		for i, step := range steps {
+			if step.Offset <= prevOffset {
+				return xerrors.Errorf("vesting step %d offset %d not after previous offset %d", i, step.Offset, prevOffset)
+			}
+			if step.Amount.Sign() <= 0 {
+				return xerrors.Errorf("vesting step %d amount %v must be positive", i, step.Amount)
+			}
+			prevOffset = step.Offset
+			total = big.Add(total, step.Amount)
+		}
+		if prevOffset != unlockDuration {
+			return xerrors.Errorf("last vesting step offset %d must equal unlock duration %d", prevOffset, unlockDuration)
+		}
+		if !total.Equals(amount) {
+			return xerrors.Errorf("vesting steps total %v must equal locked amount %v", total, amount)
+		}
+	default:
+		return xerrors.Errorf("unknown vesting kind %d", kind)
+	}
+	return nil
+}
+
 // Iterates all pending transactions and removes an address from each list of approvals, if present.
 // If an approval list becomes empty, the pending transaction is deleted.
 func (st *State) PurgeApprovals(store adt.Store, addr address.Address) error {
@@ -10,6 +10,7 @@ import (
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	multisig0 "github.com/filecoin-project/specs-actors/actors/builtin/multisig"
 	"github.com/minio/blake2b-simd"
 	assert "github.com/stretchr/testify/assert"
 	require "github.com/stretchr/testify/require"
@@ -565,6 +566,30 @@ func TestApprove(t *testing.T) {
 		actor.checkState(rt)
 	})
 
+	t.Run("approve with a proposal hash computed by an earlier actors version", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, startEpoch, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+
+		proposalHash, err := multisig0.ComputeProposalHash(&multisig0.Transaction{
+			To:       chuck,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		}, blake2b.Sum256)
+		require.NoError(t, err)
+
+		rt.SetBalance(sendValue)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, sendValue, nil, 0)
+		actor.approveOK(rt, txnID, proposalHash, nil)
+		actor.assertTransactions(rt)
+		actor.checkState(rt)
+	})
+
 	t.Run("approve with non-empty return value", func(t *testing.T) {
 		const numApprovals = uint64(2)
 
@@ -1921,6 +1946,619 @@ func TestLockBalance(t *testing.T) {
 // Helper methods for calling multisig actor methods
 //
 
+func TestTransactionExpiration(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+
+	const noUnlockDuration = abi.ChainEpoch(0)
+	const numApprovals = uint64(2)
+	const txnID = int64(0)
+	const fakeMethod = abi.MethodNum(42)
+	const expiration = abi.ChainEpoch(100)
+	var sendValue = abi.NewTokenAmount(10)
+	var fakeParams = builtin.CBORBytes([]byte{1, 2, 3, 4})
+	var signers = []addr.Address{anne, bob}
+
+	builder := mock.NewBuilder(receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("transaction may be approved until its expiration", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, 0, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpiration(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+		expected := multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     fakeMethod,
+			Params:     fakeParams,
+			Approved:   []addr.Address{anne},
+			Expiration: expiration,
+		}
+		actor.assertTransactions(rt, expected)
+
+		// the proposal hash covers the expiration
+		expected.Expiration = 0
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.Approve, &multisig.TxnIDParams{ID: multisig.TxnID(txnID), ProposalHash: makeProposalHash(t, &expected)})
+		})
+		rt.Verify()
+
+		expected.Expiration = expiration
+		rt.SetEpoch(expiration)
+		rt.SetBalance(sendValue)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, sendValue, nil, 0)
+		actor.approveOK(rt, txnID, makeProposalHash(t, &expected), nil)
+		actor.assertTransactions(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("expired transaction cannot be approved", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, 0, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpiration(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+
+		rt.SetEpoch(expiration + 1)
+		rt.SetBalance(sendValue)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.a.Approve, &multisig.TxnIDParams{ID: multisig.TxnID(txnID)})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to propose with expiration in the past", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, 0, signers...)
+
+		rt.SetEpoch(expiration + 1)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.ProposeWithExpiration, &multisig.ProposeWithExpirationParams{
+				To:         chuck,
+				Value:      sendValue,
+				Method:     fakeMethod,
+				Params:     fakeParams,
+				Expiration: expiration,
+			})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("anyone may remove expired transactions", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, numApprovals, noUnlockDuration, 0, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeWithExpiration(rt, chuck, sendValue, fakeMethod, fakeParams, expiration)
+		actor.proposeWithExpiration(rt, chuck, sendValue, fakeMethod, fakeParams, 2*expiration)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+
+		// nothing has expired yet
+		rt.SetCaller(chuck, builtin.AccountActorCodeID)
+		removed := actor.removeExpiredTransactions(rt, 0, 1, 2, 3)
+		assert.Empty(t, removed)
+
+		rt.SetEpoch(expiration + 1)
+		removed = actor.removeExpiredTransactions(rt, 0, 1, 2, 3)
+		assert.Equal(t, []multisig.TxnID{0}, removed)
+
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:         chuck,
+			Value:      sendValue,
+			Method:     fakeMethod,
+			Params:     fakeParams,
+			Approved:   []addr.Address{anne},
+			Expiration: 2 * expiration,
+		}, multisig.Transaction{
+			To:       chuck,
+			Value:    sendValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		})
+		actor.checkState(rt)
+	})
+}
+
+func TestBatch(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+
+	const noUnlockDuration = abi.ChainEpoch(0)
+	const fakeMethod = abi.MethodNum(42)
+	var sendValue = abi.NewTokenAmount(10)
+	var fakeParams = builtin.CBORBytes([]byte{1, 2, 3, 4})
+	var signers = []addr.Address{anne, bob}
+	calls := []multisig.BatchedCall{
+		{To: chuck, Value: sendValue, Method: builtin.MethodSend},
+		{To: bob, Value: big.Zero(), Method: fakeMethod, Params: fakeParams},
+	}
+
+	builder := mock.NewBuilder(receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("proposes a batch as a call to execute it", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, 0, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		ret := rt.Call(actor.a.ProposeBatch, &multisig.ProposeBatchParams{Calls: calls}).(*multisig.ProposeReturn)
+		rt.Verify()
+		assert.False(t, ret.Applied)
+
+		var buf bytes.Buffer
+		require.NoError(t, (&multisig.ExecuteBatchParams{Calls: calls}).MarshalCBOR(&buf))
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       receiver,
+			Value:    big.Zero(),
+			Method:   builtin.MethodsMultisig.ExecuteBatch,
+			Params:   buf.Bytes(),
+			Approved: []addr.Address{anne},
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to propose an invalid batch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, 0, signers...)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+
+		for _, params := range []*multisig.ProposeBatchParams{
+			{Calls: nil},
+			{Calls: []multisig.BatchedCall{{To: chuck, Value: big.NewInt(-1), Method: builtin.MethodSend}}},
+			{Calls: make([]multisig.BatchedCall, multisig.BatchCallsMax+1)},
+		} {
+			rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.a.ProposeBatch, params)
+			})
+			rt.Verify()
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("executes each call in order", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, 0, signers...)
+
+		rt.SetBalance(sendValue)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectSend(chuck, builtin.MethodSend, builtin.CBORBytes(nil), sendValue, nil, exitcode.Ok)
+		rt.ExpectSend(bob, fakeMethod, fakeParams, big.Zero(), &builtin.CBORBytes{5}, exitcode.Ok)
+		ret := rt.Call(actor.a.ExecuteBatch, &multisig.ExecuteBatchParams{Calls: calls}).(*multisig.ExecuteBatchReturn)
+		rt.Verify()
+		assert.Equal(t, [][]byte{{}, {5}}, ret.Returns)
+	})
+
+	t.Run("aborts if any call fails", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, 0, signers...)
+
+		rt.SetBalance(sendValue)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectSend(chuck, builtin.MethodSend, builtin.CBORBytes(nil), sendValue, nil, exitcode.Ok)
+		rt.ExpectSend(bob, fakeMethod, fakeParams, big.Zero(), nil, exitcode.ErrForbidden)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.a.ExecuteBatch, &multisig.ExecuteBatchParams{Calls: calls})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails to execute a batch spending locked funds", func(t *testing.T) {
+		rt := builder.Build(t)
+		rt.SetReceived(sendValue)
+		rt.SetBalance(sendValue)
+		actor.constructAndVerify(rt, 2, 10, 0, signers...)
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			rt.Call(actor.a.ExecuteBatch, &multisig.ExecuteBatchParams{Calls: calls})
+		})
+		rt.Verify()
+	})
+
+	t.Run("only the multisig may execute a batch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, 0, signers...)
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.a.ExecuteBatch, &multisig.ExecuteBatchParams{Calls: calls})
+		})
+		rt.Verify()
+	})
+}
+
+func TestWeightedSigners(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+	chuck := tutil.NewIDAddr(t, 103)
+	darlene := tutil.NewIDAddr(t, 104)
+
+	const noUnlockDuration = abi.ChainEpoch(0)
+	const fakeMethod = abi.MethodNum(42)
+	var sendValue = abi.NewTokenAmount(10)
+	var largeValue = abi.NewTokenAmount(1000)
+	var fakeParams = builtin.CBORBytes([]byte{1, 2, 3, 4})
+	var signers = []addr.Address{anne, bob, chuck}
+
+	builder := mock.NewBuilder(receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithHasher(blake2b.Sum256)
+
+	t.Run("approvals are weighted", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 3, noUnlockDuration, 0, signers...)
+		actor.changeSignerWeight(rt, anne, 3)
+		actor.changeSignerWeight(rt, bob, 2)
+
+		// anne's weight alone meets the threshold
+		rt.SetBalance(big.Mul(sendValue, big.NewInt(2)))
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, sendValue, nil, 0)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+
+		// chuck's does not, but with bob's it does
+		rt.SetCaller(chuck, builtin.AccountActorCodeID)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, sendValue, nil, 0)
+		actor.approveOK(rt, 1, nil, nil)
+		actor.assertTransactions(rt)
+
+		// restoring unit weights clears them
+		actor.changeSignerWeight(rt, anne, 1)
+		actor.changeSignerWeight(rt, bob, 1)
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Empty(t, st.SignerWeights)
+		actor.checkState(rt)
+	})
+
+	t.Run("value bands raise the threshold", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, 0, signers...)
+		actor.setApprovalRules(rt, multisig.ApprovalRule{AllMethods: true, MinValue: largeValue, Threshold: 2})
+
+		rt.SetBalance(big.Add(sendValue, largeValue))
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, sendValue, nil, 0)
+		actor.proposeOK(rt, chuck, sendValue, fakeMethod, fakeParams, nil)
+
+		actor.proposeOK(rt, chuck, largeValue, fakeMethod, fakeParams, nil)
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       chuck,
+			Value:    largeValue,
+			Method:   fakeMethod,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		})
+
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, fakeMethod, fakeParams, largeValue, nil, 0)
+		actor.approveOK(rt, 1, nil, nil)
+		actor.assertTransactions(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("value bands apply to the total value of a batch", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, 0, signers...)
+		actor.setApprovalRules(rt, multisig.ApprovalRule{AllMethods: true, MinValue: largeValue, Threshold: 2})
+		rt.SetBalance(largeValue)
+
+		// splitting a large transfer among calls, even to different recipients, requires the higher threshold
+		half := big.Div(largeValue, big.NewInt(2))
+		calls := []multisig.BatchedCall{
+			{To: chuck, Value: half, Method: builtin.MethodSend, Params: nil},
+			{To: darlene, Value: half, Method: builtin.MethodSend, Params: nil},
+		}
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		ret := rt.Call(actor.a.ProposeBatch, &multisig.ProposeBatchParams{Calls: calls}).(*multisig.ProposeReturn)
+		rt.Verify()
+		assert.False(t, ret.Applied)
+
+		// the calls of a nested batch are counted too
+		var nested bytes.Buffer
+		require.NoError(t, (&multisig.ExecuteBatchParams{Calls: calls[1:]}).MarshalCBOR(&nested))
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		ret = rt.Call(actor.a.ProposeBatch, &multisig.ProposeBatchParams{Calls: []multisig.BatchedCall{
+			calls[0],
+			{To: receiver, Value: big.Zero(), Method: builtin.MethodsMultisig.ExecuteBatch, Params: nested.Bytes()},
+		}}).(*multisig.ProposeReturn)
+		rt.Verify()
+		assert.False(t, ret.Applied)
+
+		// a batch sending less than the band needs only the base threshold
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		var small bytes.Buffer
+		require.NoError(t, (&multisig.ExecuteBatchParams{Calls: []multisig.BatchedCall{
+			{To: chuck, Value: sendValue, Method: builtin.MethodSend, Params: nil},
+			{To: darlene, Value: sendValue, Method: builtin.MethodSend, Params: nil},
+		}}).MarshalCBOR(&small))
+		rt.ExpectSend(receiver, builtin.MethodsMultisig.ExecuteBatch, builtin.CBORBytes(small.Bytes()), big.Zero(), nil, 0)
+		ret = rt.Call(actor.a.ProposeBatch, &multisig.ProposeBatchParams{Calls: []multisig.BatchedCall{
+			{To: chuck, Value: sendValue, Method: builtin.MethodSend, Params: nil},
+			{To: darlene, Value: sendValue, Method: builtin.MethodSend, Params: nil},
+		}}).(*multisig.ProposeReturn)
+		rt.Verify()
+		assert.True(t, ret.Applied)
+		actor.checkState(rt)
+	})
+
+	t.Run("method rules raise the threshold for signer changes, including in batches", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, 0, signers...)
+		actor.setApprovalRules(rt, multisig.ApprovalRule{Method: builtin.MethodsMultisig.AddSigner, SelfOnly: true, MinValue: big.Zero(), Threshold: 3})
+
+		addSignerParams := multisig.AddSignerParams{Signer: darlene}
+		var buf bytes.Buffer
+		require.NoError(t, addSignerParams.MarshalCBOR(&buf))
+
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeOK(rt, receiver, big.Zero(), builtin.MethodsMultisig.AddSigner, buf.Bytes(), nil)
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		actor.approveOK(rt, 0, nil, nil)
+
+		rt.SetCaller(chuck, builtin.AccountActorCodeID)
+		rt.ExpectSend(receiver, builtin.MethodsMultisig.AddSigner, builtin.CBORBytes(buf.Bytes()), big.Zero(), nil, 0)
+		actor.approveOK(rt, 0, nil, nil)
+		actor.assertTransactions(rt)
+
+		// the rule applies to calls within a batch
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+		ret := rt.Call(actor.a.ProposeBatch, &multisig.ProposeBatchParams{Calls: []multisig.BatchedCall{
+			{To: chuck, Value: big.Zero(), Method: fakeMethod, Params: fakeParams},
+			{To: receiver, Value: big.Zero(), Method: builtin.MethodsMultisig.AddSigner, Params: buf.Bytes()},
+		}}).(*multisig.ProposeReturn)
+		rt.Verify()
+		assert.False(t, ret.Applied)
+		actor.checkState(rt)
+	})
+
+	t.Run("self-only method rules do not apply to calls to other actors", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, noUnlockDuration, 0, signers...)
+		actor.setApprovalRules(rt, multisig.ApprovalRule{Method: builtin.MethodsMultisig.AddSigner, SelfOnly: true, MinValue: big.Zero(), Threshold: 3})
+
+		// the same method number on another actor needs only the base threshold
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, builtin.MethodsMultisig.AddSigner, fakeParams, big.Zero(), nil, 0)
+		actor.proposeOK(rt, chuck, big.Zero(), builtin.MethodsMultisig.AddSigner, fakeParams, nil)
+		actor.assertTransactions(rt)
+
+		// a rule for any target applies to both
+		actor.setApprovalRules(rt, multisig.ApprovalRule{Method: builtin.MethodsMultisig.AddSigner, MinValue: big.Zero(), Threshold: 2})
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		actor.proposeOK(rt, chuck, big.Zero(), builtin.MethodsMultisig.AddSigner, fakeParams, nil)
+		actor.assertTransactions(rt, multisig.Transaction{
+			To:       chuck,
+			Value:    big.Zero(),
+			Method:   builtin.MethodsMultisig.AddSigner,
+			Params:   fakeParams,
+			Approved: []addr.Address{anne},
+		})
+
+		rt.SetCaller(bob, builtin.AccountActorCodeID)
+		rt.ExpectSend(chuck, builtin.MethodsMultisig.AddSigner, fakeParams, big.Zero(), nil, 0)
+		actor.approveOK(rt, 1, nil, nil)
+		actor.assertTransactions(rt)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to make thresholds unattainable", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 3, noUnlockDuration, 0, signers...)
+		actor.changeSignerWeight(rt, anne, 3)
+		actor.changeNumApprovalsThreshold(rt, 5)
+
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		for _, weight := range []uint64{0, multisig.SignerWeightMax + 1, 2} {
+			rt.ExpectValidateCallerAddr(receiver)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.a.ChangeSignerWeight, &multisig.ChangeSignerWeightParams{Signer: anne, Weight: weight})
+			})
+			rt.Verify()
+		}
+
+		for _, rule := range []multisig.ApprovalRule{
+			{AllMethods: true, MinValue: big.Zero(), Threshold: 0},
+			{AllMethods: true, MinValue: big.NewInt(-1), Threshold: 1},
+			{AllMethods: true, MinValue: big.Zero(), Threshold: 6},
+		} {
+			rt.ExpectValidateCallerAddr(receiver)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.a.SetApprovalRules, &multisig.SetApprovalRulesParams{Rules: []multisig.ApprovalRule{rule}})
+			})
+			rt.Verify()
+		}
+
+		// removing anne would leave insufficient weight
+		actor.setApprovalRules(rt, multisig.ApprovalRule{AllMethods: true, MinValue: largeValue, Threshold: 4})
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.a.RemoveSigner, &multisig.RemoveSignerParams{Signer: anne, Decrease: true})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("swapped signer keeps the weight and removed signer's weight is dropped", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 2, noUnlockDuration, 0, signers...)
+		actor.changeSignerWeight(rt, anne, 3)
+
+		actor.swapSigners(rt, anne, darlene)
+		var st multisig.State
+		rt.GetState(&st)
+		assert.Equal(t, []addr.Address{bob, chuck, darlene}, st.Signers)
+		assert.Equal(t, []uint64{1, 1, 3}, st.SignerWeights)
+
+		actor.removeSigner(rt, darlene, false)
+		rt.GetState(&st)
+		assert.Equal(t, []addr.Address{bob, chuck}, st.Signers)
+		assert.Empty(t, st.SignerWeights)
+		actor.checkState(rt)
+	})
+}
+
+func TestVestingSchedules(t *testing.T) {
+	actor := msActorHarness{multisig.Actor{}, t}
+	receiver := tutil.NewIDAddr(t, 100)
+	anne := tutil.NewIDAddr(t, 101)
+	bob := tutil.NewIDAddr(t, 102)
+
+	lockAmount := abi.NewTokenAmount(100_000)
+	vestDuration := abi.ChainEpoch(1000)
+
+	builder := mock.NewBuilder(receiver).
+		WithCaller(builtin.InitActorAddr, builtin.InitActorCodeID).
+		WithEpoch(0).
+		WithHasher(blake2b.Sum256)
+
+	// Checks that exactly the expected amount may be spent at an epoch.
+	assertSpendable := func(t *testing.T, rt *mock.Runtime, epoch abi.ChainEpoch, spendable abi.TokenAmount) {
+		rt.SetEpoch(epoch)
+		rt.SetBalance(lockAmount)
+		rt.SetCaller(anne, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrInsufficientFunds, func() {
+			_ = actor.propose(rt, bob, big.Add(spendable, big.NewInt(1)), builtin.MethodSend, nil, nil)
+		})
+		rt.Reset()
+
+		if !spendable.IsZero() {
+			rt.ExpectSend(bob, builtin.MethodSend, nil, spendable, nil, exitcode.Ok)
+			actor.proposeOK(rt, bob, spendable, builtin.MethodSend, nil, nil)
+		}
+	}
+
+	t.Run("cliff vesting unlocks nothing before the cliff", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, 0, 0, anne)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		actor.lockVestingSchedule(rt, &multisig.LockVestingScheduleParams{
+			UnlockDuration: vestDuration,
+			Amount:         lockAmount,
+			Kind:           multisig.VestingCliff,
+			Cliff:          250,
+		})
+
+		assertSpendable(t, rt, 249, big.Zero())
+		assertSpendable(t, rt, 250, abi.NewTokenAmount(25_000))
+		assertSpendable(t, rt, 500, abi.NewTokenAmount(50_000))
+		assertSpendable(t, rt, vestDuration, lockAmount)
+		actor.checkState(rt)
+	})
+
+	t.Run("piecewise vesting unlocks at each step", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, 0, 0, anne)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		actor.lockVestingSchedule(rt, &multisig.LockVestingScheduleParams{
+			StartEpoch:     100,
+			UnlockDuration: vestDuration,
+			Amount:         lockAmount,
+			Kind:           multisig.VestingPiecewise,
+			Steps: []multisig.VestingStep{
+				{Offset: 250, Amount: abi.NewTokenAmount(10_000)},
+				{Offset: 500, Amount: abi.NewTokenAmount(40_000)},
+				{Offset: vestDuration, Amount: abi.NewTokenAmount(50_000)},
+			},
+		})
+
+		assertSpendable(t, rt, 349, big.Zero())
+		assertSpendable(t, rt, 350, abi.NewTokenAmount(10_000))
+		assertSpendable(t, rt, 599, abi.NewTokenAmount(10_000))
+		assertSpendable(t, rt, 600, abi.NewTokenAmount(50_000))
+		assertSpendable(t, rt, 100+vestDuration-1, abi.NewTokenAmount(50_000))
+		assertSpendable(t, rt, 100+vestDuration, lockAmount)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails to lock invalid schedules", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, 0, 0, anne)
+
+		steps := func(offsets ...int64) []multisig.VestingStep {
+			var out []multisig.VestingStep
+			for _, o := range offsets {
+				out = append(out, multisig.VestingStep{Offset: abi.ChainEpoch(o), Amount: big.Div(lockAmount, big.NewInt(int64(len(offsets))))})
+			}
+			return out
+		}
+		for _, params := range []*multisig.LockVestingScheduleParams{
+			{Kind: multisig.VestingLinear, Cliff: 10},
+			{Kind: multisig.VestingLinear, Steps: steps(1000)},
+			{Kind: multisig.VestingCliff, Cliff: 0},
+			{Kind: multisig.VestingCliff, Cliff: vestDuration + 1},
+			{Kind: multisig.VestingPiecewise},
+			{Kind: multisig.VestingPiecewise, Steps: steps(500, 500, 1000, 1000)},
+			{Kind: multisig.VestingPiecewise, Steps: steps(250, 500)},
+			{Kind: multisig.VestingPiecewise, Steps: steps(300, 600, 1000)}, // does not sum to the locked amount
+			{Kind: multisig.VestingPiecewise + 1},
+		} {
+			params.UnlockDuration = vestDuration
+			params.Amount = lockAmount
+			rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+			rt.ExpectValidateCallerAddr(receiver)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.a.LockVestingSchedule, params)
+			})
+			rt.Verify()
+		}
+		actor.checkState(rt)
+	})
+
+	t.Run("schedule cannot be locked twice", func(t *testing.T) {
+		rt := builder.Build(t)
+		actor.constructAndVerify(rt, 1, 0, 0, anne)
+		rt.SetCaller(receiver, builtin.MultisigActorCodeID)
+		actor.lockBalance(rt, 0, vestDuration, lockAmount)
+
+		rt.ExpectValidateCallerAddr(receiver)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.a.LockVestingSchedule, &multisig.LockVestingScheduleParams{
+				UnlockDuration: vestDuration,
+				Amount:         lockAmount,
+				Kind:           multisig.VestingCliff,
+				Cliff:          250,
+			})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
 type msActorHarness struct {
 	a multisig.Actor
 	t testing.TB
@@ -2010,6 +2648,33 @@ func (h *msActorHarness) approveOK(rt *mock.Runtime, txnID int64, proposalParams
 	}
 }
 
+func (h *msActorHarness) proposeWithExpiration(rt *mock.Runtime, to addr.Address, value abi.TokenAmount, method abi.MethodNum, params []byte, expiration abi.ChainEpoch) {
+	rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
+	ret := rt.Call(h.a.ProposeWithExpiration, &multisig.ProposeWithExpirationParams{
+		To:         to,
+		Value:      value,
+		Method:     method,
+		Params:     params,
+		Expiration: expiration,
+	})
+	rt.Verify()
+
+	proposeReturn, ok := ret.(*multisig.ProposeReturn)
+	if !ok {
+		h.t.Fatalf("unexpected type returned from call to ProposeWithExpiration")
+	}
+	assert.False(h.t, proposeReturn.Applied)
+}
+
+func (h *msActorHarness) removeExpiredTransactions(rt *mock.Runtime, txnIDs ...multisig.TxnID) []multisig.TxnID {
+	rt.ExpectValidateCallerAny()
+	ret := rt.Call(h.a.RemoveExpiredTransactions, &multisig.RemoveExpiredTransactionsParams{
+		TxnIDs: txnIDs,
+	})
+	rt.Verify()
+	return ret.(*multisig.RemoveExpiredTransactionsReturn).Removed
+}
+
 func (h *msActorHarness) cancel(rt *mock.Runtime, txnID int64, proposalParams []byte) {
 	rt.ExpectValidateCallerType(builtin.AccountActorCodeID, builtin.MultisigActorCodeID)
 	rt.Call(h.a.Cancel, &multisig.TxnIDParams{
@@ -2054,6 +2719,25 @@ func (h *msActorHarness) changeNumApprovalsThreshold(rt *mock.Runtime, newThresh
 	rt.Verify()
 }
 
+func (h *msActorHarness) changeSignerWeight(rt *mock.Runtime, signer addr.Address, weight uint64) {
+	rt.SetCaller(rt.Receiver(), builtin.MultisigActorCodeID)
+	rt.ExpectValidateCallerAddr(rt.Receiver())
+	rt.Call(h.a.ChangeSignerWeight, &multisig.ChangeSignerWeightParams{
+		Signer: signer,
+		Weight: weight,
+	})
+	rt.Verify()
+}
+
+func (h *msActorHarness) setApprovalRules(rt *mock.Runtime, rules ...multisig.ApprovalRule) {
+	rt.SetCaller(rt.Receiver(), builtin.MultisigActorCodeID)
+	rt.ExpectValidateCallerAddr(rt.Receiver())
+	rt.Call(h.a.SetApprovalRules, &multisig.SetApprovalRulesParams{
+		Rules: rules,
+	})
+	rt.Verify()
+}
+
 func (h *msActorHarness) lockBalance(rt *mock.Runtime, start, duration abi.ChainEpoch, amount abi.TokenAmount) {
 	rt.ExpectValidateCallerAddr(rt.Receiver())
 	rt.Call(h.a.LockBalance, &multisig.LockBalanceParams{
@@ -2064,6 +2748,12 @@ func (h *msActorHarness) lockBalance(rt *mock.Runtime, start, duration abi.Chain
 	rt.Verify()
 }
 
+func (h *msActorHarness) lockVestingSchedule(rt *mock.Runtime, params *multisig.LockVestingScheduleParams) {
+	rt.ExpectValidateCallerAddr(rt.Receiver())
+	rt.Call(h.a.LockVestingSchedule, params)
+	rt.Verify()
+}
+
 func (h *msActorHarness) assertTransactions(rt *mock.Runtime, expected ...multisig.Transaction) {
 	var st multisig.State
 	rt.GetState(&st)
@@ -3,3 +3,15 @@ package multisig
 // SignersMax is the maximum number of signers allowed in a multisig. If more
 // are required, please use a combining tree of multisigs.
 const SignersMax = 256
+
+// BatchCallsMax is the maximum number of calls in a batch transaction.
+const BatchCallsMax = 64
+
+// SignerWeightMax is the maximum approval weight of a single signer.
+const SignerWeightMax = 1 << 32
+
+// ApprovalRulesMax is the maximum number of approval rules in a multisig.
+const ApprovalRulesMax = 64
+
+// VestingStepsMax is the maximum number of steps in a piecewise vesting schedule.
+const VestingStepsMax = 120
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 )
@@ -20,12 +21,34 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 
 	// assert invariants involving signers
 	acc.Require(len(st.Signers) <= SignersMax, "multisig has too many signers: %d", len(st.Signers))
-	acc.Require(uint64(len(st.Signers)) >= st.NumApprovalsThreshold,
-		"multisig has insufficient signers to meet threshold (%d < %d)", len(st.Signers), st.NumApprovalsThreshold)
+	acc.Require(st.TotalWeight() >= st.NumApprovalsThreshold,
+		"multisig has insufficient signer weight to meet threshold (%d < %d)", st.TotalWeight(), st.NumApprovalsThreshold)
+
+	// assert invariants involving weights and approval rules
+	if len(st.SignerWeights) > 0 {
+		acc.Require(len(st.SignerWeights) == len(st.Signers), "multisig has %d signer weights for %d signers", len(st.SignerWeights), len(st.Signers))
+		allOne := true
+		for i, w := range st.SignerWeights {
+			acc.Require(w > 0 && w <= SignerWeightMax, "signer %d weight %d out of range", i, w)
+			allOne = allOne && w == 1
+		}
+		acc.Require(!allOne, "multisig has explicit signer weights all equal to 1")
+	}
+	acc.Require(len(st.ApprovalRules) <= ApprovalRulesMax, "multisig has too many approval rules: %d", len(st.ApprovalRules))
+	for i, rule := range st.ApprovalRules {
+		acc.Require(rule.Threshold > 0, "approval rule %d has zero threshold", i)
+		acc.Require(rule.Threshold <= st.TotalWeight(), "approval rule %d has insufficient signer weight to meet threshold (%d < %d)", i, st.TotalWeight(), rule.Threshold)
+		acc.Require(rule.MinValue.GreaterThanEqual(big.Zero()), "approval rule %d has negative minimum value %v", i, rule.MinValue)
+	}
 
 	if st.UnlockDuration == 0 { // See https://github.com/filecoin-project/specs-actors/issues/1185
 		acc.Require(st.StartEpoch == 0, "non-zero start epoch %d with zero unlock duration", st.StartEpoch)
 		acc.Require(st.InitialBalance.IsZero(), "non-zero locked balance %v with zero unlock duration", st.InitialBalance)
+		acc.Require(st.VestingKind == VestingLinear && st.VestingCliff == 0 && len(st.VestingSteps) == 0,
+			"vesting schedule kind %d with zero unlock duration", st.VestingKind)
+	} else {
+		acc.RequireNoError(validateVesting(st.VestingKind, st.UnlockDuration, st.InitialBalance, st.VestingCliff, st.VestingSteps),
+			"invalid vesting schedule")
 	}
 
 	// create lookup to test transaction approvals are multisig signers.
@@ -7,13 +7,14 @@ import (
 	"io"
 
 	abi "github.com/filecoin-project/go-state-types/abi"
+	paych "github.com/filecoin-project/specs-actors/v7/actors/builtin/paych"
 	cbg "github.com/whyrusleeping/cbor-gen"
 	xerrors "golang.org/x/xerrors"
 )
 
 var _ = xerrors.Errorf
 
-var lengthBufState = []byte{134}
+var lengthBufState = []byte{137}
 
 func (t *State) MarshalCBOR(w io.Writer) error {
 	if t == nil {
@@ -69,6 +70,21 @@ func (t *State) MarshalCBOR(w io.Writer) error {
 		return xerrors.Errorf("failed to write cid field t.LaneStates: %w", err)
 	}
 
+	// t.Withdrawn (big.Int) (struct)
+	if err := t.Withdrawn.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.HTLCs (cid.Cid) (struct)
+
+	if err := cbg.WriteCidBuf(scratch, w, t.HTLCs); err != nil {
+		return xerrors.Errorf("failed to write cid field t.HTLCs: %w", err)
+	}
+
+	// t.Locked (big.Int) (struct)
+	if err := t.Locked.MarshalCBOR(w); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -86,7 +102,7 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 		return fmt.Errorf("cbor input should be of type array")
 	}
 
-	if extra != 6 {
+	if extra != 9 {
 		return fmt.Errorf("cbor input had wrong number of fields")
 	}
 
@@ -178,6 +194,36 @@ func (t *State) UnmarshalCBOR(r io.Reader) error {
 
 		t.LaneStates = c
 
+	}
+	// t.Withdrawn (big.Int) (struct)
+
+	{
+
+		if err := t.Withdrawn.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Withdrawn: %w", err)
+		}
+
+	}
+	// t.HTLCs (cid.Cid) (struct)
+
+	{
+
+		c, err := cbg.ReadCid(br)
+		if err != nil {
+			return xerrors.Errorf("failed to read cid field t.HTLCs: %w", err)
+		}
+
+		t.HTLCs = c
+
+	}
+	// t.Locked (big.Int) (struct)
+
+	{
+
+		if err := t.Locked.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Locked: %w", err)
+		}
+
 	}
 	return nil
 }
@@ -252,3 +298,513 @@ func (t *LaneState) UnmarshalCBOR(r io.Reader) error {
 	}
 	return nil
 }
+
+var lengthBufHTLC = []byte{132}
+
+func (t *HTLC) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufHTLC); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Nonce (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Nonce)); err != nil {
+		return err
+	}
+
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
+		return err
+	}
+
+	// t.SecretHash ([]uint8) (slice)
+	if len(t.SecretHash) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.SecretHash was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.SecretHash))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.SecretHash[:]); err != nil {
+		return err
+	}
+
+	// t.Deadline (abi.ChainEpoch) (int64)
+	if t.Deadline >= 0 {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Deadline)); err != nil {
+			return err
+		}
+	} else {
+		if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajNegativeInt, uint64(-t.Deadline-1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *HTLC) UnmarshalCBOR(r io.Reader) error {
+	*t = HTLC{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 4 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Nonce (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Nonce = uint64(extra)
+
+	}
+	// t.Amount (big.Int) (struct)
+
+	{
+
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
+		}
+
+	}
+	// t.SecretHash ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return fmt.Errorf("t.SecretHash: byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+
+	if extra > 0 {
+		t.SecretHash = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.SecretHash[:]); err != nil {
+		return err
+	}
+	// t.Deadline (abi.ChainEpoch) (int64)
+	{
+		maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+		var extraI int64
+		if err != nil {
+			return err
+		}
+		switch maj {
+		case cbg.MajUnsignedInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 positive overflow")
+			}
+		case cbg.MajNegativeInt:
+			extraI = int64(extra)
+			if extraI < 0 {
+				return fmt.Errorf("int64 negative oveflow")
+			}
+			extraI = -1 - extraI
+		default:
+			return fmt.Errorf("wrong type for int64 field: %d", maj)
+		}
+
+		t.Deadline = abi.ChainEpoch(extraI)
+	}
+	return nil
+}
+
+var lengthBufUpdateChannelStateBatchParams = []byte{129}
+
+func (t *UpdateChannelStateBatchParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufUpdateChannelStateBatchParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Vouchers ([]paych.UpdateChannelStateParams) (slice)
+	if len(t.Vouchers) > cbg.MaxLength {
+		return xerrors.Errorf("Slice value in field t.Vouchers was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajArray, uint64(len(t.Vouchers))); err != nil {
+		return err
+	}
+	for _, v := range t.Vouchers {
+		if err := v.MarshalCBOR(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *UpdateChannelStateBatchParams) UnmarshalCBOR(r io.Reader) error {
+	*t = UpdateChannelStateBatchParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Vouchers ([]paych.UpdateChannelStateParams) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.MaxLength {
+		return fmt.Errorf("t.Vouchers: array too large (%d)", extra)
+	}
+
+	if maj != cbg.MajArray {
+		return fmt.Errorf("expected cbor array")
+	}
+
+	if extra > 0 {
+		t.Vouchers = make([]paych.UpdateChannelStateParams, extra)
+	}
+
+	for i := 0; i < int(extra); i++ {
+
+		var v paych.UpdateChannelStateParams
+		if err := v.UnmarshalCBOR(br); err != nil {
+			return err
+		}
+
+		t.Vouchers[i] = v
+	}
+
+	return nil
+}
+
+var lengthBufUpdateChannelStateBatchReturn = []byte{129}
+
+func (t *UpdateChannelStateBatchReturn) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufUpdateChannelStateBatchReturn); err != nil {
+		return err
+	}
+
+	// t.Accepted (bitfield.BitField) (struct)
+	if err := t.Accepted.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *UpdateChannelStateBatchReturn) UnmarshalCBOR(r io.Reader) error {
+	*t = UpdateChannelStateBatchReturn{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Accepted (bitfield.BitField) (struct)
+
+	{
+
+		if err := t.Accepted.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Accepted: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufWithdrawParams = []byte{129}
+
+func (t *WithdrawParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufWithdrawParams); err != nil {
+		return err
+	}
+
+	// t.Amount (big.Int) (struct)
+	if err := t.Amount.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *WithdrawParams) UnmarshalCBOR(r io.Reader) error {
+	*t = WithdrawParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Amount (big.Int) (struct)
+
+	{
+
+		if err := t.Amount.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Amount: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufLockHTLCParams = []byte{129}
+
+func (t *LockHTLCParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufLockHTLCParams); err != nil {
+		return err
+	}
+
+	// t.Sv (paych.SignedVoucher) (struct)
+	if err := t.Sv.MarshalCBOR(w); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *LockHTLCParams) UnmarshalCBOR(r io.Reader) error {
+	*t = LockHTLCParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Sv (paych.SignedVoucher) (struct)
+
+	{
+
+		if err := t.Sv.UnmarshalCBOR(br); err != nil {
+			return xerrors.Errorf("unmarshaling t.Sv: %w", err)
+		}
+
+	}
+	return nil
+}
+
+var lengthBufClaimHTLCParams = []byte{130}
+
+func (t *ClaimHTLCParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufClaimHTLCParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Lane (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Lane)); err != nil {
+		return err
+	}
+
+	// t.Secret ([]uint8) (slice)
+	if len(t.Secret) > cbg.ByteArrayMaxLen {
+		return xerrors.Errorf("Byte array in field t.Secret was too long")
+	}
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajByteString, uint64(len(t.Secret))); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(t.Secret[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *ClaimHTLCParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ClaimHTLCParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 2 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Lane (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Lane = uint64(extra)
+
+	}
+	// t.Secret ([]uint8) (slice)
+
+	maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+
+	if extra > cbg.ByteArrayMaxLen {
+		return fmt.Errorf("t.Secret: byte array too large (%d)", extra)
+	}
+	if maj != cbg.MajByteString {
+		return fmt.Errorf("expected byte array")
+	}
+
+	if extra > 0 {
+		t.Secret = make([]uint8, extra)
+	}
+
+	if _, err := io.ReadFull(br, t.Secret[:]); err != nil {
+		return err
+	}
+	return nil
+}
+
+var lengthBufReclaimHTLCParams = []byte{129}
+
+func (t *ReclaimHTLCParams) MarshalCBOR(w io.Writer) error {
+	if t == nil {
+		_, err := w.Write(cbg.CborNull)
+		return err
+	}
+	if _, err := w.Write(lengthBufReclaimHTLCParams); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 9)
+
+	// t.Lane (uint64) (uint64)
+
+	if err := cbg.WriteMajorTypeHeaderBuf(scratch, w, cbg.MajUnsignedInt, uint64(t.Lane)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (t *ReclaimHTLCParams) UnmarshalCBOR(r io.Reader) error {
+	*t = ReclaimHTLCParams{}
+
+	br := cbg.GetPeeker(r)
+	scratch := make([]byte, 8)
+
+	maj, extra, err := cbg.CborReadHeaderBuf(br, scratch)
+	if err != nil {
+		return err
+	}
+	if maj != cbg.MajArray {
+		return fmt.Errorf("cbor input should be of type array")
+	}
+
+	if extra != 1 {
+		return fmt.Errorf("cbor input had wrong number of fields")
+	}
+
+	// t.Lane (uint64) (uint64)
+
+	{
+
+		maj, extra, err = cbg.CborReadHeaderBuf(br, scratch)
+		if err != nil {
+			return err
+		}
+		if maj != cbg.MajUnsignedInt {
+			return fmt.Errorf("wrong type for uint64 field")
+		}
+		t.Lane = uint64(extra)
+
+	}
+	return nil
+}
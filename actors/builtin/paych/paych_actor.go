@@ -4,10 +4,12 @@ import (
 	"bytes"
 
 	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	rtt "github.com/filecoin-project/go-state-types/rt"
 	paych0 "github.com/filecoin-project/specs-actors/actors/builtin/paych"
 	paych7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/paych"
 
@@ -30,6 +32,11 @@ func (a Actor) Exports() []interface{} {
 		2:                         a.UpdateChannelState,
 		3:                         a.Settle,
 		4:                         a.Collect,
+		5:                         a.UpdateChannelStateBatch,
+		6:                         a.Withdraw,
+		7:                         a.LockHTLC,
+		8:                         a.ClaimHTLC,
+		9:                         a.ReclaimHTLC,
 	}
 }
 
@@ -65,8 +72,10 @@ func (pca *Actor) Constructor(rt runtime.Runtime, params *ConstructorParams) *ab
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create empty array")
 	emptyArrCid, err := emptyArr.Root()
 	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to persist empty array")
+	emptyHTLCsCid, err := adt.StoreEmptyArray(adt.AsStore(rt), HTLCsAmtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create empty htlcs array")
 
-	st := ConstructState(from, to, emptyArrCid)
+	st := ConstructState(from, to, emptyArrCid, emptyHTLCsCid)
 	rt.StateCreate(st)
 
 	return nil
@@ -134,62 +143,175 @@ func (pca Actor) UpdateChannelState(rt runtime.Runtime, params *UpdateChannelSta
 
 	// both parties must sign voucher: one who submits it, the other explicitly signs it
 	rt.ValidateImmediateCallerIs(st.From, st.To)
-	var signer addr.Address
-	if rt.Caller() == st.From {
-		signer = st.To
-	} else {
-		signer = st.From
-	}
-	sv := params.Sv
+	signer := voucherSigner(rt, &st)
 
-	if sv.Signature == nil {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher has no signature")
+	if st.SettlingAt != 0 && rt.CurrEpoch() >= st.SettlingAt {
+		rt.Abortf(ErrChannelStateUpdateAfterSettled, "no vouchers can be processed after SettlingAt epoch")
 	}
 
+	err := validateVoucher(rt, signer, params)
+	builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalArgument), "invalid voucher")
+
+	rt.StateTransaction(&st, func() {
+		lstates, err := adt.AsArray(adt.AsStore(rt), st.LaneStates, LaneStatesAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
+		htlcs, err := adt.AsArray(adt.AsStore(rt), st.HTLCs, HTLCsAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load htlcs")
+
+		err = redeemVoucher(rt, &st, lstates, htlcs, &params.Sv)
+		builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalArgument), "failed to redeem voucher")
+
+		st.LaneStates, err = lstates.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save lanes")
+	})
+	return nil
+}
+
+type UpdateChannelStateBatchParams struct {
+	Vouchers []UpdateChannelStateParams
+}
+
+type UpdateChannelStateBatchReturn struct {
+	// Indices of the vouchers that were redeemed.
+	Accepted bitfield.BitField
+}
+
+// Redeems a batch of vouchers, possibly across many lanes, in a single state transaction.
+// Each voucher is subject to the same checks as in UpdateChannelState, and is applied in order.
+// Invalid vouchers are skipped; the method fails only if no voucher is valid.
+func (pca Actor) UpdateChannelStateBatch(rt runtime.Runtime, params *UpdateChannelStateBatchParams) *UpdateChannelStateBatchReturn {
+	var st State
+	rt.StateReadonly(&st)
+
+	rt.ValidateImmediateCallerIs(st.From, st.To)
+	signer := voucherSigner(rt, &st)
+
+	builtin.RequireParam(rt, len(params.Vouchers) > 0, "empty voucher batch")
+	builtin.RequireParam(rt, len(params.Vouchers) <= MaxBatchVouchers, "too many vouchers in batch %d, max %d", len(params.Vouchers), MaxBatchVouchers)
+
 	if st.SettlingAt != 0 && rt.CurrEpoch() >= st.SettlingAt {
 		rt.Abortf(ErrChannelStateUpdateAfterSettled, "no vouchers can be processed after SettlingAt epoch")
 	}
 
+	// Signatures, time locks, secrets and extra verification calls don't depend on channel state,
+	// so are checked before entering the state transaction.
+	valid := make([]bool, len(params.Vouchers))
+	for i := range params.Vouchers {
+		if err := validateVoucher(rt, signer, &params.Vouchers[i]); err != nil {
+			rt.Log(rtt.INFO, "invalid voucher %d: %s", i, err)
+			continue
+		}
+		valid[i] = true
+	}
+
+	var accepted []uint64
+	rt.StateTransaction(&st, func() {
+		lstates, err := adt.AsArray(adt.AsStore(rt), st.LaneStates, LaneStatesAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
+		htlcs, err := adt.AsArray(adt.AsStore(rt), st.HTLCs, HTLCsAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load htlcs")
+
+		for i := range params.Vouchers {
+			if !valid[i] {
+				continue
+			}
+			if err := redeemVoucher(rt, &st, lstates, htlcs, &params.Vouchers[i].Sv); err != nil {
+				if code := exitcode.Unwrap(err, exitcode.ErrIllegalState); code == exitcode.ErrIllegalState {
+					rt.Abortf(code, "failed to redeem voucher %d: %s", i, err)
+				}
+				rt.Log(rtt.INFO, "failed to redeem voucher %d: %s", i, err)
+				continue
+			}
+			accepted = append(accepted, uint64(i))
+		}
+		builtin.RequireParam(rt, len(accepted) > 0, "all vouchers invalid")
+
+		st.LaneStates, err = lstates.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save lanes")
+	})
+	return &UpdateChannelStateBatchReturn{Accepted: bitfield.NewFromSet(accepted)}
+}
+
+// Returns the party that must have signed vouchers submitted by the caller.
+func voucherSigner(rt runtime.Runtime, st *State) addr.Address {
+	if rt.Caller() == st.From {
+		return st.To
+	}
+	return st.From
+}
+
+// Checks the parts of a voucher that are independent of the channel's lane states, invoking
+// the voucher's extra verification method if any.
+func validateVoucher(rt runtime.Runtime, signer addr.Address, params *UpdateChannelStateParams) error {
+	sv := &params.Sv
+
+	if err := checkVoucherSignature(rt, signer, sv); err != nil {
+		return err
+	}
+
 	if len(params.Secret) > MaxSecretSize {
-		rt.Abortf(exitcode.ErrIllegalArgument, "secret must be at most 256 bytes long")
+		return exitcode.ErrIllegalArgument.Wrapf("secret must be at most 256 bytes long")
 	}
 
-	vb, err := VoucherSigningBytes(&sv)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to serialize signedvoucher")
+	if err := checkVoucherTerms(rt, sv); err != nil {
+		return err
+	}
 
-	err = rt.VerifySignature(*sv.Signature, signer, vb)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "voucher signature invalid")
+	if len(sv.SecretHash) > 0 {
+		hashedSecret := rt.HashBlake2b(params.Secret)
+		if !bytes.Equal(hashedSecret[:], sv.SecretHash) {
+			return exitcode.ErrIllegalArgument.Wrapf("incorrect secret!")
+		}
+	}
+
+	return verifyVoucherExtra(rt, sv)
+}
+
+func checkVoucherSignature(rt runtime.Runtime, signer addr.Address, sv *SignedVoucher) error {
+	if sv.Signature == nil {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher has no signature")
+	}
+
+	vb, err := VoucherSigningBytes(sv)
+	if err != nil {
+		return exitcode.ErrIllegalArgument.Wrapf("failed to serialize signedvoucher: %w", err)
+	}
+
+	if err := rt.VerifySignature(*sv.Signature, signer, vb); err != nil {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher signature invalid: %w", err)
+	}
+	return nil
+}
+
+// Checks a voucher's channel, time locks and amount.
+func checkVoucherTerms(rt runtime.Runtime, sv *SignedVoucher) error {
 
 	pchAddr := rt.Receiver()
 	svpchIDAddr, found := rt.ResolveAddress(sv.ChannelAddr)
 	if !found {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher payment channel address %s does not resolve to an ID address", sv.ChannelAddr)
+		return exitcode.ErrIllegalArgument.Wrapf("voucher payment channel address %s does not resolve to an ID address", sv.ChannelAddr)
 	}
 	if pchAddr != svpchIDAddr {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher payment channel address %s does not match receiver %s", svpchIDAddr, pchAddr)
+		return exitcode.ErrIllegalArgument.Wrapf("voucher payment channel address %s does not match receiver %s", svpchIDAddr, pchAddr)
 	}
 
 	if rt.CurrEpoch() < sv.TimeLockMin {
-		rt.Abortf(exitcode.ErrIllegalArgument, "cannot use this voucher yet!")
+		return exitcode.ErrIllegalArgument.Wrapf("cannot use this voucher yet!")
 	}
 
 	if sv.TimeLockMax != 0 && rt.CurrEpoch() > sv.TimeLockMax {
-		rt.Abortf(exitcode.ErrIllegalArgument, "this voucher has expired!")
+		return exitcode.ErrIllegalArgument.Wrapf("this voucher has expired!")
 	}
 
 	if sv.Amount.Sign() < 0 {
-		rt.Abortf(exitcode.ErrIllegalArgument, "voucher amount must be non-negative, was %v", sv.Amount)
-	}
-
-	if len(sv.SecretHash) > 0 {
-		hashedSecret := rt.HashBlake2b(params.Secret)
-		if !bytes.Equal(hashedSecret[:], sv.SecretHash) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "incorrect secret!")
-		}
+		return exitcode.ErrIllegalArgument.Wrapf("voucher amount must be non-negative, was %v", sv.Amount)
 	}
+	return nil
+}
 
+// Invokes the voucher's extra verification method, if any.
+func verifyVoucherExtra(rt runtime.Runtime, sv *SignedVoucher) error {
 	if sv.Extra != nil {
-
 		code := rt.Send(
 			sv.Extra.Actor,
 			sv.Extra.Method,
@@ -197,94 +319,106 @@ func (pca Actor) UpdateChannelState(rt runtime.Runtime, params *UpdateChannelSta
 			abi.NewTokenAmount(0),
 			&builtin.Discard{},
 		)
-		builtin.RequireSuccess(rt, code, "spend voucher verification failed")
+		if !code.IsSuccess() {
+			return code.Wrapf("spend voucher verification failed")
+		}
 	}
+	return nil
+}
 
-	rt.StateTransaction(&st, func() {
-		laneFound := true
-
-		lstates, err := adt.AsArray(adt.AsStore(rt), st.LaneStates, LaneStatesAmtBitwidth)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
+// Applies a voucher to the channel's lane states and redeemable balance.
+// Either the voucher is applied in full, or an error is returned and neither st nor lstates is modified
+// (apart from unexpected state errors, which must abort).
+func redeemVoucher(rt runtime.Runtime, st *State, lstates *adt.Array, htlcs *adt.Array, sv *SignedVoucher) error {
+	// Find the voucher lane, creating if necessary.
+	laneState, err := findLane(lstates, sv.Lane)
+	if err != nil {
+		return err
+	}
+	if err := requireNoHTLC(htlcs, sv.Lane); err != nil {
+		return err
+	}
 
-		// Find the voucher lane, creating if necessary.
-		laneId := sv.Lane
-		laneState := findLane(rt, lstates, sv.Lane)
+	if laneState == nil {
+		laneState = &LaneState{
+			Redeemed: big.Zero(),
+			Nonce:    0,
+		}
+	} else if laneState.Nonce >= sv.Nonce {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher has an outdated nonce, existing nonce: %d, voucher nonce: %d, cannot redeem",
+			laneState.Nonce, sv.Nonce)
+	}
 
-		if laneState == nil {
-			laneState = &LaneState{
-				Redeemed: big.Zero(),
-				Nonce:    0,
-			}
-			laneFound = false
+	// The next section actually calculates the payment amounts to update the payment channel state
+	// 1. (optional) sum already redeemed value of all merging lanes
+	redeemedFromOthers := big.Zero()
+	mergedLanes := make(map[uint64]*LaneState, len(sv.Merges))
+	for _, merge := range sv.Merges {
+		if merge.Lane == sv.Lane {
+			return exitcode.ErrIllegalArgument.Wrapf("voucher cannot merge lanes into its own lane")
+		}
+		if _, ok := mergedLanes[merge.Lane]; ok {
+			return exitcode.ErrIllegalArgument.Wrapf("voucher merges lane %d more than once", merge.Lane)
 		}
 
-		if laneFound {
-			if laneState.Nonce >= sv.Nonce {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher has an outdated nonce, existing nonce: %d, voucher nonce: %d, cannot redeem",
-					laneState.Nonce, sv.Nonce)
-			}
+		otherls, err := findLane(lstates, merge.Lane)
+		if err != nil {
+			return err
+		}
+		if err := requireNoHTLC(htlcs, merge.Lane); err != nil {
+			return err
+		}
+		if otherls == nil {
+			return exitcode.ErrIllegalArgument.Wrapf("voucher specifies invalid merge lane %v", merge.Lane)
 		}
 
-		// The next section actually calculates the payment amounts to update the payment channel state
-		// 1. (optional) sum already redeemed value of all merging lanes
-		redeemedFromOthers := big.Zero()
-		for _, merge := range sv.Merges {
-			if merge.Lane == sv.Lane {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher cannot merge lanes into its own lane")
-			}
+		if otherls.Nonce >= merge.Nonce {
+			return exitcode.ErrIllegalArgument.Wrapf("merged lane in voucher has outdated nonce, cannot redeem")
+		}
 
-			otherls := findLane(rt, lstates, merge.Lane)
-			if otherls == nil {
-				rt.Abortf(exitcode.ErrIllegalArgument, "voucher specifies invalid merge lane %v", merge.Lane)
-				return // makes linters happy
-			}
+		redeemedFromOthers = big.Add(redeemedFromOthers, otherls.Redeemed)
+		otherls.Nonce = merge.Nonce
+		mergedLanes[merge.Lane] = otherls
+	}
 
-			if otherls.Nonce >= merge.Nonce {
-				rt.Abortf(exitcode.ErrIllegalArgument, "merged lane in voucher has outdated nonce, cannot redeem")
-			}
+	// 2. To prevent double counting, remove already redeemed amounts (from
+	// voucher or other lanes) from the voucher amount
+	balanceDelta := big.Sub(sv.Amount, big.Add(redeemedFromOthers, laneState.Redeemed))
+	newSendBalance := big.Add(st.ToSend, balanceDelta)
 
-			redeemedFromOthers = big.Add(redeemedFromOthers, otherls.Redeemed)
-			otherls.Nonce = merge.Nonce
-			err = lstates.Set(merge.Lane, otherls)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane %d", merge.Lane)
-		}
+	// 3. check operation validity
+	if newSendBalance.LessThan(big.Zero()) {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher would leave channel balance negative")
+	}
+	if newSendBalance.LessThan(st.Withdrawn) {
+		return exitcode.ErrIllegalArgument.Wrapf("voucher would reduce redeemed amount below amount already withdrawn %v", st.Withdrawn)
+	}
+	// Withdrawn funds have already left the channel's balance, and pending HTLCs are reserved from it.
+	if big.Add(big.Sub(newSendBalance, st.Withdrawn), st.Locked).GreaterThan(rt.CurrentBalance()) {
+		return exitcode.ErrIllegalArgument.Wrapf("not enough funds in channel to cover voucher")
+	}
 
-		// 2. To prevent double counting, remove already redeemed amounts (from
-		// voucher or other lanes) from the voucher amount
-		laneState.Nonce = sv.Nonce
-		balanceDelta := big.Sub(sv.Amount, big.Add(redeemedFromOthers, laneState.Redeemed))
-		// 3. set new redeemed value for merged-into lane
-		laneState.Redeemed = sv.Amount
+	// 4. set new redeemed value for merged-into lane, and add new redemption ToSend
+	for _, merge := range sv.Merges {
+		err := lstates.Set(merge.Lane, mergedLanes[merge.Lane])
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane %d", merge.Lane)
+	}
+	laneState.Nonce = sv.Nonce
+	laneState.Redeemed = sv.Amount
+	err = lstates.Set(sv.Lane, laneState)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane %d", sv.Lane)
 
-		newSendBalance := big.Add(st.ToSend, balanceDelta)
+	st.ToSend = newSendBalance
 
-		// 4. check operation validity
-		if newSendBalance.LessThan(big.Zero()) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "voucher would leave channel balance negative")
+	// update channel settlingAt and MinSettleHeight if delayed by voucher
+	if sv.MinSettleHeight != 0 {
+		if st.SettlingAt != 0 && st.SettlingAt < sv.MinSettleHeight {
+			st.SettlingAt = sv.MinSettleHeight
 		}
-		if newSendBalance.GreaterThan(rt.CurrentBalance()) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "not enough funds in channel to cover voucher")
-		}
-
-		// 5. add new redemption ToSend
-		st.ToSend = newSendBalance
-
-		// update channel settlingAt and MinSettleHeight if delayed by voucher
-		if sv.MinSettleHeight != 0 {
-			if st.SettlingAt != 0 && st.SettlingAt < sv.MinSettleHeight {
-				st.SettlingAt = sv.MinSettleHeight
-			}
-			if st.MinSettleHeight < sv.MinSettleHeight {
-				st.MinSettleHeight = sv.MinSettleHeight
-			}
+		if st.MinSettleHeight < sv.MinSettleHeight {
+			st.MinSettleHeight = sv.MinSettleHeight
 		}
-
-		err = lstates.Set(laneId, laneState)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane", laneId)
-
-		st.LaneStates, err = lstates.Root()
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save lanes")
-	})
+	}
 	return nil
 }
 
@@ -313,13 +447,17 @@ func (pca Actor) Collect(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue
 	if st.SettlingAt == 0 || rt.CurrEpoch() < st.SettlingAt {
 		rt.Abortf(exitcode.ErrForbidden, "payment channel not settling or settled")
 	}
+	// Pending HTLCs must be claimed or reclaimed first, so their funds are paid to the right party.
+	if !st.Locked.IsZero() {
+		rt.Abortf(exitcode.ErrForbidden, "payment channel has pending htlcs of %v", st.Locked)
+	}
 
-	// send ToSend to "To"
+	// send ToSend, less any amount already withdrawn, to "To"
 	codeTo := rt.Send(
 		st.To,
 		builtin.MethodSend,
 		nil,
-		st.ToSend,
+		st.Withdrawable(),
 		&builtin.Discard{},
 	)
 	builtin.RequireSuccess(rt, codeTo, "Failed to send funds to `To`")
@@ -330,19 +468,239 @@ func (pca Actor) Collect(rt runtime.Runtime, _ *abi.EmptyValue) *abi.EmptyValue
 	return nil
 }
 
-// Returns the insertion index for a lane ID, with the matching lane state if found, or nil.
-func findLane(rt runtime.Runtime, ls *adt.Array, id uint64) *LaneState {
+type WithdrawParams struct {
+	Amount abi.TokenAmount
+}
+
+// Pays out up to the requested amount of redeemed funds to the recipient, without settling the channel.
+// The channel remains open for further vouchers, which must continue to redeem monotonically increasing
+// amounts on each lane.
+// Returns the amount withdrawn, which may be less than requested if less has been redeemed.
+func (pca Actor) Withdraw(rt runtime.Runtime, params *WithdrawParams) *abi.TokenAmount {
+	if params.Amount.LessThan(big.Zero()) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "negative amount %v", params.Amount)
+	}
+
+	var st State
+	amountWithdrawn := big.Zero()
+	rt.StateTransaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.To)
+
+		amountWithdrawn = big.Min(params.Amount, st.Withdrawable())
+		st.Withdrawn = big.Add(st.Withdrawn, amountWithdrawn)
+	})
+
+	code := rt.Send(st.To, builtin.MethodSend, nil, amountWithdrawn, &builtin.Discard{})
+	builtin.RequireSuccess(rt, code, "failed to send funds to `To`")
+	return &amountWithdrawn
+}
+
+type LockHTLCParams struct {
+	Sv SignedVoucher
+}
+
+// Locks a hash-time-locked payment, to be claimed by the recipient with the secret preimage.
+// The voucher must be signed by the sender and specify a secret hash and a deadline (TimeLockMax).
+// The payment is the amount by which the voucher exceeds its lane's redeemed amount. It is reserved
+// from the channel balance, and the channel cannot be collected until after the deadline.
+// No other voucher may update the lane, or merge it, while the payment is pending.
+func (pca Actor) LockHTLC(rt runtime.Runtime, params *LockHTLCParams) *abi.EmptyValue {
+	var st State
+	rt.StateReadonly(&st)
+	rt.ValidateImmediateCallerIs(st.To)
+	sv := &params.Sv
+
+	if st.SettlingAt != 0 && rt.CurrEpoch() >= st.SettlingAt {
+		rt.Abortf(ErrChannelStateUpdateAfterSettled, "no vouchers can be processed after SettlingAt epoch")
+	}
+
+	err := checkVoucherSignature(rt, st.From, sv)
+	builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalArgument), "invalid voucher")
+	err = checkVoucherTerms(rt, sv)
+	builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalArgument), "invalid voucher")
+	builtin.RequireParam(rt, len(sv.SecretHash) == HTLCSecretHashSize, "htlc voucher secret hash must be %d bytes, was %d",
+		HTLCSecretHashSize, len(sv.SecretHash))
+	builtin.RequireParam(rt, sv.TimeLockMax != 0, "htlc voucher must specify a deadline")
+	builtin.RequireParam(rt, len(sv.Merges) == 0, "htlc voucher cannot merge lanes")
+	err = verifyVoucherExtra(rt, sv)
+	builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalArgument), "invalid voucher")
+
+	rt.StateTransaction(&st, func() {
+		lstates, err := adt.AsArray(adt.AsStore(rt), st.LaneStates, LaneStatesAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
+		htlcs, err := adt.AsArray(adt.AsStore(rt), st.HTLCs, HTLCsAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load htlcs")
+
+		laneState, err := findLane(lstates, sv.Lane)
+		builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalState), "failed to load lane %d", sv.Lane)
+		err = requireNoHTLC(htlcs, sv.Lane)
+		builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalState), "cannot lock htlc")
+
+		redeemed := big.Zero()
+		if laneState != nil {
+			if laneState.Nonce >= sv.Nonce {
+				rt.Abortf(exitcode.ErrIllegalArgument, "voucher has an outdated nonce, existing nonce: %d, voucher nonce: %d, cannot lock",
+					laneState.Nonce, sv.Nonce)
+			}
+			redeemed = laneState.Redeemed
+		}
+
+		amount := big.Sub(sv.Amount, redeemed)
+		builtin.RequireParam(rt, amount.GreaterThan(big.Zero()), "htlc voucher amount %v must exceed lane %d redeemed amount %v",
+			sv.Amount, sv.Lane, redeemed)
+		locked := big.Add(st.Locked, amount)
+		if big.Add(st.Withdrawable(), locked).GreaterThan(rt.CurrentBalance()) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "not enough funds in channel to cover htlc")
+		}
+
+		err = htlcs.Set(sv.Lane, &HTLC{
+			Nonce:      sv.Nonce,
+			Amount:     amount,
+			SecretHash: sv.SecretHash,
+			Deadline:   sv.TimeLockMax,
+		})
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store htlc %d", sv.Lane)
+		st.HTLCs, err = htlcs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save htlcs")
+		st.Locked = locked
+
+		// The channel cannot be collected until after the recipient's last chance to claim.
+		settleHeight := sv.TimeLockMax + 1
+		if st.SettlingAt != 0 && st.SettlingAt < settleHeight {
+			st.SettlingAt = settleHeight
+		}
+		if st.MinSettleHeight < settleHeight {
+			st.MinSettleHeight = settleHeight
+		}
+	})
+	return nil
+}
+
+type ClaimHTLCParams struct {
+	Lane   uint64
+	Secret []byte
+}
+
+// Claims a pending hash-time-locked payment by presenting the secret preimage, at or before its deadline.
+// The payment is added to the lane's redeemed amount, as if its locking voucher had been redeemed.
+func (pca Actor) ClaimHTLC(rt runtime.Runtime, params *ClaimHTLCParams) *abi.EmptyValue {
+	if len(params.Secret) > MaxSecretSize {
+		rt.Abortf(exitcode.ErrIllegalArgument, "secret must be at most 256 bytes long")
+	}
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.To)
+
+		lstates, err := adt.AsArray(adt.AsStore(rt), st.LaneStates, LaneStatesAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lanes")
+		htlcs, err := adt.AsArray(adt.AsStore(rt), st.HTLCs, HTLCsAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load htlcs")
+
+		htlc := loadHTLC(rt, htlcs, params.Lane)
+		if rt.CurrEpoch() > htlc.Deadline {
+			rt.Abortf(exitcode.ErrForbidden, "htlc on lane %d expired at epoch %d", params.Lane, htlc.Deadline)
+		}
+		hashedSecret := rt.HashBlake2b(params.Secret)
+		if !bytes.Equal(hashedSecret[:], htlc.SecretHash) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "incorrect secret!")
+		}
+
+		laneState, err := findLane(lstates, params.Lane)
+		builtin.RequireNoErr(rt, err, exitcode.Unwrap(err, exitcode.ErrIllegalState), "failed to load lane %d", params.Lane)
+		if laneState == nil {
+			laneState = &LaneState{
+				Redeemed: big.Zero(),
+				Nonce:    0,
+			}
+		}
+		laneState.Nonce = htlc.Nonce
+		laneState.Redeemed = big.Add(laneState.Redeemed, htlc.Amount)
+		err = lstates.Set(params.Lane, laneState)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to store lane %d", params.Lane)
+		err = htlcs.Delete(params.Lane)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete htlc %d", params.Lane)
+
+		st.ToSend = big.Add(st.ToSend, htlc.Amount)
+		st.Locked = big.Sub(st.Locked, htlc.Amount)
+		st.LaneStates, err = lstates.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save lanes")
+		st.HTLCs, err = htlcs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save htlcs")
+	})
+	return nil
+}
+
+type ReclaimHTLCParams struct {
+	Lane uint64
+}
+
+// Releases an unclaimed hash-time-locked payment back to the sender after its deadline.
+// The payment's funds are no longer reserved, and remain in the channel to be returned to the sender
+// on Collect. The lane's redeemed amount is unchanged, and the locking voucher can no longer be redeemed.
+// No voucher from the recipient is required: the sender signed the deadline in the locking voucher,
+// after which the recipient can no longer claim, so the release depends only on the epoch.
+// Either party may release the payment, so that the recipient can collect a channel the sender abandons.
+func (pca Actor) ReclaimHTLC(rt runtime.Runtime, params *ReclaimHTLCParams) *abi.EmptyValue {
+	var st State
+	rt.StateTransaction(&st, func() {
+		rt.ValidateImmediateCallerIs(st.From, st.To)
+
+		htlcs, err := adt.AsArray(adt.AsStore(rt), st.HTLCs, HTLCsAmtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load htlcs")
+
+		htlc := loadHTLC(rt, htlcs, params.Lane)
+		if rt.CurrEpoch() <= htlc.Deadline {
+			rt.Abortf(exitcode.ErrForbidden, "htlc on lane %d may be claimed until epoch %d", params.Lane, htlc.Deadline)
+		}
+
+		err = htlcs.Delete(params.Lane)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete htlc %d", params.Lane)
+		st.HTLCs, err = htlcs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to save htlcs")
+		st.Locked = big.Sub(st.Locked, htlc.Amount)
+	})
+	return nil
+}
+
+// Returns the lane state for a lane ID if found, or nil.
+func findLane(ls *adt.Array, id uint64) (*LaneState, error) {
 	if id > MaxLane {
-		rt.Abortf(exitcode.ErrIllegalArgument, "maximum lane ID is 2^63-1")
+		return nil, exitcode.ErrIllegalArgument.Wrapf("maximum lane ID is 2^63-1")
 	}
 
 	var out LaneState
 	found, err := ls.Get(id, &out)
-	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load lane %d", id)
+	if err != nil {
+		return nil, exitcode.ErrIllegalState.Wrapf("failed to load lane %d: %w", id, err)
+	}
 
 	if !found {
-		return nil
+		return nil, nil
 	}
 
+	return &out, nil
+}
+
+// Returns an error if a lane has a pending HTLC.
+func requireNoHTLC(htlcs *adt.Array, lane uint64) error {
+	found, err := htlcs.Get(lane, nil)
+	if err != nil {
+		return exitcode.ErrIllegalState.Wrapf("failed to load htlc %d: %w", lane, err)
+	}
+	if found {
+		return exitcode.ErrForbidden.Wrapf("lane %d has a pending htlc", lane)
+	}
+	return nil
+}
+
+// Loads the pending HTLC for a lane, aborting if there is none.
+func loadHTLC(rt runtime.Runtime, htlcs *adt.Array, lane uint64) *HTLC {
+	var out HTLC
+	found, err := htlcs.Get(lane, &out)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load htlc %d", lane)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no pending htlc on lane %d", lane)
+	}
 	return &out
 }
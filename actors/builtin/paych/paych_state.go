@@ -26,6 +26,14 @@ type State struct {
 
 	// Collections of lane states for the channel, maintained in ID order.
 	LaneStates cid.Cid // AMT<LaneState>
+
+	// Amount of ToSend already paid out to `To` by `Withdraw()`, prior to settlement.
+	Withdrawn abi.TokenAmount
+
+	// Pending hash-time-locked payments, keyed by the lane of the voucher that locked them.
+	HTLCs cid.Cid // AMT<HTLC>
+	// Total amount of pending HTLCs, reserved from the channel balance.
+	Locked abi.TokenAmount
 }
 
 // The Lane state tracks the latest (highest) voucher nonce used to merge the lane
@@ -35,9 +43,34 @@ type LaneState struct {
 	Nonce    uint64
 }
 
+// A hash-time-locked payment, locked by a voucher with a secret hash and deadline.
+// The payment is added to its lane's redeemed amount if the recipient presents the secret preimage
+// at or before the deadline. Otherwise either party may release it after the deadline.
+type HTLC struct {
+	// Nonce of the locking voucher.
+	Nonce uint64
+	// Amount conditionally paid, in addition to the lane's redeemed amount when locked.
+	Amount abi.TokenAmount
+	// Blake2b-256 hash of the secret preimage.
+	SecretHash []byte
+	// Last epoch at which the payment may be claimed; the locking voucher's TimeLockMax.
+	Deadline abi.ChainEpoch
+}
+
 const LaneStatesAmtBitwidth = 3
+const HTLCsAmtBitwidth = 3
+
+// Returns the redeemed amount not yet paid out to `To`.
+func (st *State) Withdrawable() abi.TokenAmount {
+	return big.Sub(st.ToSend, st.Withdrawn)
+}
+
+// Returns the balance required to cover all redeemed amounts not yet paid out and all pending HTLCs.
+func (st *State) Committed() abi.TokenAmount {
+	return big.Add(st.Withdrawable(), st.Locked)
+}
 
-func ConstructState(from addr.Address, to addr.Address, emptyArrCid cid.Cid) *State {
+func ConstructState(from addr.Address, to addr.Address, emptyArrCid cid.Cid, emptyHTLCsCid cid.Cid) *State {
 	return &State{
 		From:            from,
 		To:              to,
@@ -45,5 +78,8 @@ func ConstructState(from addr.Address, to addr.Address, emptyArrCid cid.Cid) *St
 		SettlingAt:      0,
 		MinSettleHeight: 0,
 		LaneStates:      emptyArrCid,
+		Withdrawn:       big.Zero(),
+		HTLCs:           emptyHTLCsCid,
+		Locked:          big.Zero(),
 	}
 }
@@ -704,6 +704,111 @@ func TestActor_UpdateChannelStateSecretHash(t *testing.T) {
 	})
 }
 
+func TestActor_UpdateChannelStateBatch(t *testing.T) {
+	// Builds a voucher from the last voucher created by requireCreateChannelWithLanes.
+	voucher := func(sv *SignedVoucher, lane, nonce uint64, amount int64) UpdateChannelStateParams {
+		ucp := UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Lane = lane
+		ucp.Sv.Nonce = nonce
+		ucp.Sv.Amount = big.NewInt(amount)
+		return ucp
+	}
+
+	t.Run("redeems vouchers across lanes", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 3)
+		var st State
+		rt.GetState(&st)
+
+		vouchers := []UpdateChannelStateParams{
+			voucher(sv, 0, 2, 10),
+			voucher(sv, 1, 3, 20),
+			voucher(sv, 5, 1, 5), // new lane
+			voucher(sv, 0, 3, 12),
+		}
+		accepted := actor.updateChannelStateBatch(rt, st.To, vouchers, nil)
+		assert.Equal(t, []uint64{0, 1, 2, 3}, accepted)
+
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(12+20+3+5), st.ToSend)
+		assert.Equal(t, &LaneState{Redeemed: big.NewInt(12), Nonce: 3}, getLaneState(t, rt, st.LaneStates, 0))
+		assert.Equal(t, &LaneState{Redeemed: big.NewInt(20), Nonce: 3}, getLaneState(t, rt, st.LaneStates, 1))
+		assert.Equal(t, &LaneState{Redeemed: big.NewInt(5), Nonce: 1}, getLaneState(t, rt, st.LaneStates, 5))
+		assertLaneStatesLength(t, rt, st.LaneStates, 4)
+		actor.checkState(rt)
+	})
+
+	t.Run("skips invalid vouchers", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 2)
+		var st State
+		rt.GetState(&st)
+
+		expired := voucher(sv, 3, 1, 5)
+		expired.Sv.TimeLockMax = rt.Epoch() - 1
+		unsigned := voucher(sv, 4, 1, 5)
+		unsigned.Sv.Signature = nil
+		vouchers := []UpdateChannelStateParams{
+			voucher(sv, 0, 2, 10),
+			voucher(sv, 1, 2, 20), // outdated nonce
+			voucher(sv, 2, 1, 5),  // bad signature
+			expired,
+			unsigned,
+			voucher(sv, 0, 2, 15),     // nonce already used earlier in the batch
+			voucher(sv, 6, 1, 100000), // exceeds channel balance
+		}
+		accepted := actor.updateChannelStateBatch(rt, st.To, vouchers, map[int]error{2: fmt.Errorf("bad signature")})
+		assert.Equal(t, []uint64{0}, accepted)
+
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(10+2), st.ToSend)
+		assert.Equal(t, &LaneState{Redeemed: big.NewInt(10), Nonce: 2}, getLaneState(t, rt, st.LaneStates, 0))
+		assertLaneStatesLength(t, rt, st.LaneStates, 2)
+		actor.checkState(rt)
+	})
+
+	t.Run("fails if all vouchers are invalid", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 1)
+		var st State
+		rt.GetState(&st)
+
+		vouchers := []UpdateChannelStateParams{voucher(sv, 0, 1, 10)}
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.updateChannelStateBatch(rt, st.To, vouchers, nil)
+		})
+		actor.checkState(rt)
+	})
+
+	t.Run("fails with empty batch", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, 1)
+		var st State
+		rt.GetState(&st)
+
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			actor.updateChannelStateBatch(rt, st.To, nil, nil)
+		})
+	})
+
+	t.Run("fails after settling", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 1)
+		var st State
+		rt.GetState(&st)
+
+		rt.SetCaller(st.From, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.Call(actor.Settle, nil)
+		rt.Verify()
+		rt.SetEpoch(rt.Epoch() + SettleDelay)
+
+		rt.SetCaller(st.To, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(st.From, st.To)
+		rt.ExpectAbort(ErrChannelStateUpdateAfterSettled, func() {
+			rt.Call(actor.UpdateChannelStateBatch, &UpdateChannelStateBatchParams{
+				Vouchers: []UpdateChannelStateParams{voucher(sv, 0, 2, 10)},
+			})
+		})
+		rt.Verify()
+	})
+}
+
 func TestActor_Settle(t *testing.T) {
 	ep := abi.ChainEpoch(10)
 
@@ -863,6 +968,331 @@ func TestActor_Collect(t *testing.T) {
 	}
 }
 
+func TestActor_Withdraw(t *testing.T) {
+	t.Run("recipient withdraws redeemed funds without settling", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 3)
+		var st State
+		rt.GetState(&st)
+		require.Equal(t, big.NewInt(6), st.ToSend)
+
+		assert.Equal(t, big.NewInt(4), actor.withdraw(rt, big.NewInt(4), big.NewInt(4)))
+		// Only the remaining redeemed amount is paid out.
+		assert.Equal(t, big.NewInt(2), actor.withdraw(rt, big.NewInt(10), big.NewInt(2)))
+		withdrawn := actor.withdraw(rt, big.NewInt(10), big.Zero())
+		assert.True(t, withdrawn.IsZero())
+
+		// The channel remains open for further vouchers.
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Amount = big.NewInt(10)
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.Call(actor.UpdateChannelState, ucp)
+		rt.Verify()
+
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(13), st.ToSend)
+		assert.Equal(t, big.NewInt(6), st.Withdrawn)
+		actor.checkState(rt)
+
+		// Collect pays out only the amount not yet withdrawn.
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.Call(actor.Settle, nil)
+		rt.Verify()
+		rt.SetEpoch(rt.Epoch() + SettleDelay)
+
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectSend(actor.payee, builtin.MethodSend, nil, big.NewInt(7), nil, exitcode.Ok)
+		rt.ExpectDeleteActor(actor.payer)
+		rt.Call(actor.Collect, nil)
+		rt.Verify()
+	})
+
+	t.Run("vouchers may redeem up to the remaining balance after withdrawal", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 3)
+		actor.withdraw(rt, big.NewInt(6), big.NewInt(6))
+
+		// Redeem the entire remaining balance on lane 2.
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Amount = big.Add(rt.Balance(), big.NewInt(3))
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.Call(actor.UpdateChannelState, ucp)
+		rt.Verify()
+		actor.checkState(rt)
+
+		ucp.Sv.Nonce++
+		ucp.Sv.Amount = big.Add(ucp.Sv.Amount, big.NewInt(1))
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, ucp)
+		})
+		rt.Verify()
+	})
+
+	t.Run("voucher cannot reduce redeemed amount below amount withdrawn", func(t *testing.T) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 3)
+		actor.withdraw(rt, big.NewInt(6), big.NewInt(6))
+
+		// Merging lane 1 into lane 2 without increasing lane 2's amount reduces ToSend.
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Merges = []Merge{{Lane: 1, Nonce: 10}}
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.UpdateChannelState, ucp)
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("only the recipient may withdraw", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, 1)
+		rt.SetCaller(actor.payer, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payee)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.Withdraw, &WithdrawParams{Amount: big.NewInt(1)})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails with negative amount", func(t *testing.T) {
+		rt, actor, _ := requireCreateChannelWithLanes(t, 1)
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.Withdraw, &WithdrawParams{Amount: big.NewInt(-1)})
+		})
+		rt.Verify()
+	})
+}
+
+func TestActor_HTLC(t *testing.T) {
+	secret := []byte("Profesr")
+	secretHash := []byte("ProfesrXXXXXXXXXXXXXXXXXXXXXXXXX")
+	deadline := abi.ChainEpoch(100)
+
+	// Creates a channel with two lanes, redeemed 1 and 2, and a voucher locking a payment of 10 on lane 1.
+	setup := func(t *testing.T) (*mock.Runtime, *pcActorHarness, *SignedVoucher) {
+		rt, actor, sv := requireCreateChannelWithLanes(t, 2)
+		rt.SetHasher(func(data []byte) [32]byte {
+			var res [32]byte
+			copy(res[:], "XXXXXXXXXXXXXXXXXXXXXXXXXXXXXXXX")
+			copy(res[:], data)
+			return res
+		})
+		sv.Amount = big.NewInt(12)
+		sv.SecretHash = secretHash
+		sv.TimeLockMax = deadline
+		return rt, actor, sv
+	}
+
+	t.Run("recipient claims payment with secret before deadline", func(t *testing.T) {
+		rt, actor, sv := setup(t)
+		actor.lockHTLC(rt, sv)
+
+		var st State
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(3), st.ToSend)
+		assert.Equal(t, big.NewInt(10), st.Locked)
+		assert.Equal(t, deadline+1, st.MinSettleHeight)
+
+		// The lane cannot be updated while the payment is pending.
+		ucp := &UpdateChannelStateParams{Sv: *sv, Secret: secret}
+		ucp.Sv.Nonce++
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.UpdateChannelState, ucp)
+		})
+		rt.Verify()
+
+		rt.SetEpoch(deadline)
+		actor.claimHTLC(rt, sv.Lane, secret)
+
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(13), st.ToSend)
+		assert.True(t, st.Locked.IsZero())
+		assert.Equal(t, &LaneState{Redeemed: big.NewInt(12), Nonce: sv.Nonce}, getLaneState(t, rt, st.LaneStates, sv.Lane))
+		actor.checkState(rt)
+
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payee)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(actor.ClaimHTLC, &ClaimHTLCParams{Lane: sv.Lane, Secret: secret})
+		})
+	})
+
+	t.Run("claim fails with incorrect secret or after deadline", func(t *testing.T) {
+		rt, actor, sv := setup(t)
+		actor.lockHTLC(rt, sv)
+
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payee)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(actor.ClaimHTLC, &ClaimHTLCParams{Lane: sv.Lane, Secret: []byte("Magneto")})
+		})
+		rt.Verify()
+
+		rt.SetEpoch(deadline + 1)
+		rt.ExpectValidateCallerAddr(actor.payee)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.ClaimHTLC, &ClaimHTLCParams{Lane: sv.Lane, Secret: secret})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("sender reclaims payment after deadline", func(t *testing.T) {
+		rt, actor, sv := setup(t)
+		actor.lockHTLC(rt, sv)
+
+		rt.SetEpoch(deadline)
+		rt.SetCaller(actor.payer, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.ReclaimHTLC, &ReclaimHTLCParams{Lane: sv.Lane})
+		})
+		rt.Verify()
+
+		rt.SetEpoch(deadline + 1)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.Call(actor.ReclaimHTLC, &ReclaimHTLCParams{Lane: sv.Lane})
+		rt.Verify()
+
+		var st State
+		rt.GetState(&st)
+		assert.Equal(t, big.NewInt(3), st.ToSend)
+		assert.True(t, st.Locked.IsZero())
+		assert.Equal(t, &LaneState{Redeemed: big.NewInt(2), Nonce: 2}, getLaneState(t, rt, st.LaneStates, sv.Lane))
+		actor.checkState(rt)
+
+		// The lane accepts further vouchers.
+		ucp := &UpdateChannelStateParams{Sv: *sv}
+		ucp.Sv.Nonce++
+		ucp.Sv.Amount = big.NewInt(5)
+		ucp.Sv.SecretHash = nil
+		ucp.Sv.TimeLockMax = 0
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectVerifySignature(*ucp.Sv.Signature, actor.payer, voucherBytes(t, &ucp.Sv), nil)
+		rt.Call(actor.UpdateChannelState, ucp)
+		rt.Verify()
+		actor.checkState(rt)
+	})
+
+	t.Run("settlement is delayed until after the deadline", func(t *testing.T) {
+		rt, actor, sv := setup(t)
+		rt.SetCaller(actor.payer, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.Call(actor.Settle, nil)
+		rt.Verify()
+
+		var st State
+		rt.GetState(&st)
+		sv.TimeLockMax = st.SettlingAt + 100
+		actor.lockHTLC(rt, sv)
+
+		rt.GetState(&st)
+		assert.Equal(t, sv.TimeLockMax+1, st.SettlingAt)
+		assert.Equal(t, sv.TimeLockMax+1, st.MinSettleHeight)
+		actor.checkState(rt)
+
+		// The payment may still be claimed at the deadline, so the channel cannot yet be collected.
+		rt.SetEpoch(sv.TimeLockMax)
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.Collect, nil)
+		})
+		rt.Verify()
+
+		actor.claimHTLC(rt, sv.Lane, secret)
+		actor.checkState(rt)
+	})
+
+	t.Run("collect fails while payments are pending", func(t *testing.T) {
+		rt, actor, sv := setup(t)
+		actor.lockHTLC(rt, sv)
+
+		rt.SetCaller(actor.payer, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.Call(actor.Settle, nil)
+		rt.Verify()
+
+		var st State
+		rt.GetState(&st)
+		rt.SetEpoch(st.SettlingAt)
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "pending htlcs", func() {
+			rt.Call(actor.Collect, nil)
+		})
+		rt.Verify()
+
+		// The recipient may release the expired payment to collect the channel.
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.Call(actor.ReclaimHTLC, &ReclaimHTLCParams{Lane: sv.Lane})
+		rt.Verify()
+		actor.checkState(rt)
+
+		rt.ExpectValidateCallerAddr(actor.payer, actor.payee)
+		rt.ExpectSend(actor.payee, builtin.MethodSend, nil, st.ToSend, nil, exitcode.Ok)
+		rt.ExpectDeleteActor(actor.payer)
+		rt.Call(actor.Collect, nil)
+		rt.Verify()
+	})
+
+	t.Run("fails to lock invalid htlc vouchers", func(t *testing.T) {
+		rt, actor, sv := setup(t)
+
+		for _, mutate := range []func(sv *SignedVoucher){
+			func(sv *SignedVoucher) { sv.SecretHash = nil },
+			func(sv *SignedVoucher) { sv.SecretHash = secret },
+			func(sv *SignedVoucher) { sv.TimeLockMax = 0 },
+			func(sv *SignedVoucher) { sv.Merges = []Merge{{Lane: 0, Nonce: 10}} },
+			func(sv *SignedVoucher) { sv.Amount = big.NewInt(2) },      // no more than lane redeemed amount
+			func(sv *SignedVoucher) { sv.Nonce = 2 },                   // outdated nonce
+			func(sv *SignedVoucher) { sv.Amount = big.NewInt(100000) }, // exceeds channel balance
+		} {
+			invalid := *sv
+			mutate(&invalid)
+			rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+			rt.ExpectValidateCallerAddr(actor.payee)
+			rt.ExpectVerifySignature(*invalid.Signature, actor.payer, voucherBytes(t, &invalid), nil)
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(actor.LockHTLC, &LockHTLCParams{Sv: invalid})
+			})
+			rt.Verify()
+		}
+
+		// Only one payment may be pending on a lane.
+		actor.lockHTLC(rt, sv)
+		next := *sv
+		next.Nonce++
+		next.Amount = big.NewInt(20)
+		rt.SetCaller(actor.payee, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payee)
+		rt.ExpectVerifySignature(*next.Signature, actor.payer, voucherBytes(t, &next), nil)
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(actor.LockHTLC, &LockHTLCParams{Sv: next})
+		})
+		rt.Verify()
+
+		// Only the recipient may lock a payment.
+		rt.SetCaller(actor.payer, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(actor.payee)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(actor.LockHTLC, &LockHTLCParams{Sv: next})
+		})
+		rt.Verify()
+		actor.checkState(rt)
+	})
+}
+
 type pcActorHarness struct {
 	Actor
 	t testing.TB
@@ -958,6 +1388,59 @@ func (h *pcActorHarness) checkState(rt *mock.Runtime) {
 	assert.True(h.t, msgs.IsEmpty(), strings.Join(msgs.Messages(), "\n"))
 }
 
+// Submits a batch of vouchers from caller, expecting signature verification of each signed voucher
+// that passes the preceding checks, and returns the indices of accepted vouchers.
+// Signature verification fails for the indices in sigErrs.
+func (h *pcActorHarness) updateChannelStateBatch(rt *mock.Runtime, caller addr.Address, vouchers []UpdateChannelStateParams, sigErrs map[int]error) []uint64 {
+	signer := h.payer
+	if caller == h.payer {
+		signer = h.payee
+	}
+	rt.SetCaller(caller, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payer, h.payee)
+	for i := range vouchers {
+		if vouchers[i].Sv.Signature != nil {
+			vb, err := VoucherSigningBytes(&vouchers[i].Sv)
+			require.NoError(h.t, err)
+			rt.ExpectVerifySignature(*vouchers[i].Sv.Signature, signer, vb, sigErrs[i])
+		}
+	}
+	ret := rt.Call(h.UpdateChannelStateBatch, &UpdateChannelStateBatchParams{Vouchers: vouchers}).(*UpdateChannelStateBatchReturn)
+	rt.Verify()
+
+	accepted, err := ret.Accepted.All(uint64(len(vouchers)))
+	require.NoError(h.t, err)
+	return accepted
+}
+
+func (h *pcActorHarness) withdraw(rt *mock.Runtime, amount, expected abi.TokenAmount) abi.TokenAmount {
+	rt.SetCaller(h.payee, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payee)
+	rt.ExpectSend(h.payee, builtin.MethodSend, nil, expected, nil, exitcode.Ok)
+	ret := rt.Call(h.Withdraw, &WithdrawParams{Amount: amount}).(*abi.TokenAmount)
+	rt.Verify()
+	h.checkState(rt)
+	return *ret
+}
+
+func (h *pcActorHarness) lockHTLC(rt *mock.Runtime, sv *SignedVoucher) {
+	rt.SetCaller(h.payee, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payee)
+	vb, err := VoucherSigningBytes(sv)
+	require.NoError(h.t, err)
+	rt.ExpectVerifySignature(*sv.Signature, h.payer, vb, nil)
+	rt.Call(h.LockHTLC, &LockHTLCParams{Sv: *sv})
+	rt.Verify()
+	h.checkState(rt)
+}
+
+func (h *pcActorHarness) claimHTLC(rt *mock.Runtime, lane uint64, secret []byte) {
+	rt.SetCaller(h.payee, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.payee)
+	rt.Call(h.ClaimHTLC, &ClaimHTLCParams{Lane: lane, Secret: secret})
+	rt.Verify()
+}
+
 func verifyInitialState(t *testing.T, rt *mock.Runtime, sender, receiver addr.Address) {
 	var st State
 	rt.GetState(&st)
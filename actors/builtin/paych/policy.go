@@ -13,3 +13,9 @@ const SettleDelay = builtin.EpochsInHour * 12
 
 // Maximum size of a secret that can be submitted with a payment channel update (in bytes).
 const MaxSecretSize = 256
+
+// Size of the secret hash of a hash-time-locked voucher, that of a blake2b-256 digest.
+const HTLCSecretHashSize = 32
+
+// Maximum number of vouchers that can be redeemed in a single batch.
+const MaxBatchVouchers = 1024
@@ -21,7 +21,7 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 
 	acc.Require(st.From.Protocol() == address.ID, "from address is not ID address %v", st.From)
 	acc.Require(st.To.Protocol() == address.ID, "to address is not ID address %v", st.To)
-	acc.Require(st.SettlingAt >= st.MinSettleHeight,
+	acc.Require(st.SettlingAt == 0 || st.SettlingAt >= st.MinSettleHeight,
 		"channel is setting at epoch %d before min settle height %d", st.SettlingAt, st.MinSettleHeight)
 
 	if lanes, err := adt.AsArray(store, st.LaneStates, LaneStatesAmtBitwidth); err != nil {
@@ -36,8 +36,29 @@ func CheckStateInvariants(st *State, store adt.Store, balance abi.TokenAmount) (
 		acc.RequireNoError(err, "error iterating lanes")
 	}
 
-	acc.Require(balance.GreaterThanEqual(st.ToSend),
-		"channel has insufficient funds to send (%v < %v)", balance, st.ToSend)
+	locked := big.Zero()
+	if htlcs, err := adt.AsArray(store, st.HTLCs, HTLCsAmtBitwidth); err != nil {
+		acc.Addf("error loading htlcs: %v", err)
+	} else {
+		var htlc HTLC
+		err = htlcs.ForEach(&htlc, func(i int64) error {
+			acc.Require(htlc.Amount.GreaterThan(big.Zero()), "htlc %d amount is not greater than zero %v", i, htlc.Amount)
+			acc.Require(len(htlc.SecretHash) == HTLCSecretHashSize, "htlc %d secret hash has length %d", i, len(htlc.SecretHash))
+			acc.Require(htlc.Deadline < st.MinSettleHeight,
+				"htlc %d deadline %d is not before min settle height %d", i, htlc.Deadline, st.MinSettleHeight)
+			locked = big.Add(locked, htlc.Amount)
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating htlcs")
+	}
+	acc.Require(st.Locked.Equals(locked), "locked amount %v does not match sum of htlcs %v", st.Locked, locked)
+
+	acc.Require(st.Withdrawn.GreaterThanEqual(big.Zero()), "withdrawn amount %v is negative", st.Withdrawn)
+	acc.Require(st.Withdrawn.LessThanEqual(st.ToSend),
+		"withdrawn amount %v exceeds amount to send %v", st.Withdrawn, st.ToSend)
+
+	acc.Require(balance.GreaterThanEqual(st.Committed()),
+		"channel has insufficient funds to send (%v < %v)", balance, st.Committed())
 
 	return paychSummary, acc
 }
@@ -0,0 +1,144 @@
+package verifreg
+
+import (
+	"errors"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/cbor"
+	cid "github.com/ipfs/go-cid"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+// A HAMT-based map of HAMT-based maps, addressing each value by an outer and an inner key.
+// Inner maps are removed from the outer map when they become empty.
+type MapMap struct {
+	mp            *adt.Map
+	store         adt.Store
+	innerBitwidth int
+}
+
+// Interprets a store as a HAMT-based map of HAMT-based maps with root `r`.
+func AsMapMap(s adt.Store, r cid.Cid, outerBitwidth, innerBitwidth int) (*MapMap, error) {
+	m, err := adt.AsMap(s, r, outerBitwidth)
+	if err != nil {
+		return nil, err
+	}
+	return &MapMap{mp: m, store: s, innerBitwidth: innerBitwidth}, nil
+}
+
+// Writes a new empty map to the store and returns its CID.
+func StoreEmptyMapMap(s adt.Store, bitwidth int) (cid.Cid, error) {
+	return adt.StoreEmptyMap(s, bitwidth)
+}
+
+// Returns the root cid of the underlying HAMT.
+func (mm *MapMap) Root() (cid.Cid, error) {
+	return mm.mp.Root()
+}
+
+func (mm *MapMap) Get(outer, inner abi.Keyer, out cbor.Unmarshaler) (bool, error) {
+	in, found, err := mm.load(outer)
+	if err != nil || !found {
+		return false, err
+	}
+	return in.Get(inner, out)
+}
+
+func (mm *MapMap) Put(outer, inner abi.Keyer, v cbor.Marshaler) error {
+	in, found, err := mm.load(outer)
+	if err != nil {
+		return err
+	}
+	if !found {
+		if in, err = adt.MakeEmptyMap(mm.store, mm.innerBitwidth); err != nil {
+			return err
+		}
+	}
+	if err = in.Put(inner, v); err != nil {
+		return xerrors.Errorf("failed to put %v in map %v: %w", inner, outer, err)
+	}
+	return mm.flush(outer, in)
+}
+
+// Removes a value, returning whether it was present.
+func (mm *MapMap) TryDelete(outer, inner abi.Keyer) (bool, error) {
+	in, found, err := mm.load(outer)
+	if err != nil || !found {
+		return false, err
+	}
+	if found, err = in.TryDelete(inner); err != nil || !found {
+		return false, err
+	}
+
+	empty, err := isEmptyMap(in)
+	if err != nil {
+		return false, err
+	}
+	if empty {
+		return true, mm.mp.Delete(outer)
+	}
+	return true, mm.flush(outer, in)
+}
+
+// Iterates the values under an outer key, in no particular order.
+func (mm *MapMap) ForEachIn(outer abi.Keyer, out cbor.Unmarshaler, fn func(inner string) error) error {
+	in, found, err := mm.load(outer)
+	if err != nil || !found {
+		return err
+	}
+	return in.ForEach(out, fn)
+}
+
+// Iterates all values, in no particular order.
+func (mm *MapMap) ForEach(out cbor.Unmarshaler, fn func(outer, inner string) error) error {
+	var root cbg.CborCid
+	return mm.mp.ForEach(&root, func(outer string) error {
+		in, err := adt.AsMap(mm.store, cid.Cid(root), mm.innerBitwidth)
+		if err != nil {
+			return err
+		}
+		return in.ForEach(out, func(inner string) error {
+			return fn(outer, inner)
+		})
+	})
+}
+
+func (mm *MapMap) load(outer abi.Keyer) (*adt.Map, bool, error) {
+	var root cbg.CborCid
+	found, err := mm.mp.Get(outer, &root)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load map key %v: %w", outer, err)
+	}
+	if !found {
+		return nil, false, nil
+	}
+	in, err := adt.AsMap(mm.store, cid.Cid(root), mm.innerBitwidth)
+	if err != nil {
+		return nil, false, xerrors.Errorf("failed to load map %v: %w", outer, err)
+	}
+	return in, true, nil
+}
+
+func (mm *MapMap) flush(outer abi.Keyer, in *adt.Map) error {
+	root, err := in.Root()
+	if err != nil {
+		return xerrors.Errorf("failed to flush map %v: %w", outer, err)
+	}
+	newRoot := cbg.CborCid(root)
+	return mm.mp.Put(outer, &newRoot)
+}
+
+var errNotEmpty = errors.New("not empty")
+
+func isEmptyMap(m *adt.Map) (bool, error) {
+	err := m.ForEach(nil, func(string) error {
+		return errNotEmpty
+	})
+	if err == errNotEmpty {
+		return false, nil
+	}
+	return err == nil, err
+}
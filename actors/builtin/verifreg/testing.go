@@ -10,14 +10,20 @@ import (
 )
 
 type StateSummary struct {
-	Verifiers map[addr.Address]DataCap
-	Clients   map[addr.Address]DataCap
+	RootKey         addr.Address
+	Verifiers       map[addr.Address]DataCap
+	Clients         map[addr.Address]DataCap
+	Allocations     map[AllocationID]Allocation
+	Claims          map[ClaimID]Claim
+	Audits          map[addr.Address]VerifierAudit
+	DataCapBaseline DataCap
 }
 
 // Checks internal invariants of verified registry state.
 func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.MessageAccumulator) {
 	acc := &builtin.MessageAccumulator{}
 	acc.Require(st.RootKey.Protocol() == addr.ID, "root key %v should have ID protocol", st.RootKey)
+	acc.Require(st.DataCapBaseline.GreaterThanEqual(big.Zero()), "datacap baseline %v is negative", st.DataCapBaseline)
 
 	// Check verifiers
 	allVerifiers := map[addr.Address]DataCap{}
@@ -64,8 +70,99 @@ func CheckStateInvariants(st *State, store adt.Store) (*StateSummary, *builtin.M
 	}
 	// No need to iterate all clients; any overlap must have been one of all verifiers.
 
+	// Check the root key holds no datacap.
+	_, found := allVerifiers[st.RootKey]
+	acc.Require(!found, "root key %v is a verifier", st.RootKey)
+	_, found = allClients[st.RootKey]
+	acc.Require(!found, "root key %v is a client", st.RootKey)
+
+	// Check allocations
+	allAllocations := map[AllocationID]Allocation{}
+	if allocations, err := AsMapMap(store, st.Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading allocations: %v", err)
+	} else {
+		var alloc Allocation
+		err = allocations.ForEach(&alloc, func(outer, inner string) error {
+			client, err := addr.NewFromBytes([]byte(outer))
+			if err != nil {
+				return err
+			}
+			id, err := abi.ParseUIntKey(inner)
+			if err != nil {
+				return err
+			}
+			acc.Require(client.Protocol() == addr.ID, "allocation %d client %v should have ID protocol", id, client)
+			acc.Require(alloc.Client == client, "allocation %d keyed by client %v has client %v", id, client, alloc.Client)
+			acc.Require(alloc.Provider.Protocol() == addr.ID, "allocation %d provider %v should have ID protocol", id, alloc.Provider)
+			acc.Require(AllocationID(id) < st.NextAllocationId, "allocation %d not less than next allocation ID %d", id, st.NextAllocationId)
+			acc.Require(big.NewIntUnsigned(uint64(alloc.Size)).GreaterThanEqual(MinVerifiedDealSize),
+				"allocation %d size %d below minimum verified deal size", id, alloc.Size)
+			acc.Require(alloc.TermMin <= alloc.TermMax, "allocation %d term min %d exceeds term max %d", id, alloc.TermMin, alloc.TermMax)
+			allAllocations[AllocationID(id)] = alloc
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating allocations")
+	}
+
+	// Check claims
+	allClaims := map[ClaimID]Claim{}
+	if claims, err := AsMapMap(store, st.Claims, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading claims: %v", err)
+	} else {
+		var claim Claim
+		err = claims.ForEach(&claim, func(outer, inner string) error {
+			provider, err := addr.NewFromBytes([]byte(outer))
+			if err != nil {
+				return err
+			}
+			id, err := abi.ParseUIntKey(inner)
+			if err != nil {
+				return err
+			}
+			acc.Require(claim.Provider == provider, "claim %d keyed by provider %v has provider %v", id, provider, claim.Provider)
+			acc.Require(ClaimID(id) < st.NextAllocationId, "claim %d not less than next allocation ID %d", id, st.NextAllocationId)
+			_, found := allAllocations[ClaimID(id)]
+			acc.Require(!found, "claim %d is also an unclaimed allocation", id)
+			allClaims[ClaimID(id)] = claim
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating claims")
+	}
+
+	// Check verifier audits
+	allAudits := map[addr.Address]VerifierAudit{}
+	if audits, err := adt.AsMap(store, st.VerifierAudits, builtin.DefaultHamtBitwidth); err != nil {
+		acc.Addf("error loading verifier audits: %v", err)
+	} else {
+		var audit VerifierAudit
+		err = audits.ForEach(&audit, func(key string) error {
+			verifier, err := addr.NewFromBytes([]byte(key))
+			if err != nil {
+				return err
+			}
+			acc.Require(audit.Granted.GreaterThanEqual(big.Zero()), "verifier %v granted %v is negative", verifier, audit.Granted)
+			acc.Require(audit.Expiration >= 0, "verifier %v expiration %d is negative", verifier, audit.Expiration)
+			if clients, err := adt.AsSet(store, audit.Clients, builtin.DefaultHamtBitwidth); err != nil {
+				acc.Addf("error loading clients of verifier %v: %v", verifier, err)
+			} else {
+				keys, err := clients.CollectKeys()
+				acc.RequireNoError(err, "error iterating clients of verifier %v", verifier)
+				acc.Require(uint64(len(keys)) == audit.ClientCount, "verifier %v client count %d does not match %d clients",
+					verifier, audit.ClientCount, len(keys))
+			}
+			allAudits[verifier] = audit
+			return nil
+		})
+		acc.RequireNoError(err, "error iterating verifier audits")
+	}
+
 	return &StateSummary{
-		Verifiers: allVerifiers,
-		Clients:   allClients,
+		RootKey:         st.RootKey,
+		Verifiers:       allVerifiers,
+		Clients:         allClients,
+		Allocations:     allAllocations,
+		Claims:          allClaims,
+		Audits:          allAudits,
+		DataCapBaseline: st.DataCapBaseline,
 	}, acc
 }
@@ -2,12 +2,15 @@ package verifreg
 
 import (
 	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/cbor"
 	"github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	rtt "github.com/filecoin-project/go-state-types/rt"
+	market0 "github.com/filecoin-project/specs-actors/actors/builtin/market"
 	verifreg0 "github.com/filecoin-project/specs-actors/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
@@ -25,6 +28,15 @@ func (a Actor) Exports() []interface{} {
 		5:                         a.UseBytes,
 		6:                         a.RestoreBytes,
 		7:                         a.RemoveVerifiedClientDataCap,
+		8:                         a.CreateAllocations,
+		9:                         a.ClaimAllocations,
+		10:                        a.RemoveExpiredAllocations,
+		11:                        a.GetClaims,
+		12:                        a.SetVerifierExpiration,
+		13:                        a.GetVerifierAudit,
+		14:                        a.TransferDataCap,
+		15:                        a.AllocateDealDataCap,
+		16:                        a.RemoveExpiredClaims,
 	}
 }
 
@@ -97,8 +109,23 @@ func (a Actor) AddVerifier(rt runtime.Runtime, params *AddVerifierParams) *abi.E
 		err = verifiers.Put(abi.AddrKey(verifier), &params.Allowance)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verifier")
 
+		// Adding a verifier clears any expiration, which the root key may set again.
+		audits, err := adt.AsMap(adt.AsStore(rt), st.VerifierAudits, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifier audits")
+		var audit VerifierAudit
+		found, err = audits.Get(abi.AddrKey(verifier), &audit)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load audit for verifier %v", verifier)
+		if found && audit.Expiration != 0 {
+			audit.Expiration = 0
+			err = audits.Put(abi.AddrKey(verifier), &audit)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update audit for verifier %v", verifier)
+		}
+
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
+
+		st.VerifierAudits, err = audits.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifier audits")
 	})
 
 	return nil
@@ -120,6 +147,7 @@ func (a Actor) RemoveVerifier(rt runtime.Runtime, verifierAddr *addr.Address) *a
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove verifier")
 		builtin.RequireParam(rt, found, "no such verifier %v", verifierAddr)
 
+		// The verifier's audit record is retained, so its grants remain accountable after removal.
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
 	})
@@ -166,6 +194,15 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 			rt.Abortf(exitcode.ErrNotFound, "no such verifier %v", verifier)
 		}
 
+		audits, err := adt.AsMap(adt.AsStore(rt), st.VerifierAudits, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifier audits")
+
+		// Validate the verifier has not expired.
+		audit := loadVerifierAudit(rt, audits, verifier)
+		if audit.Expiration != 0 && rt.CurrEpoch() >= audit.Expiration {
+			rt.Abortf(exitcode.ErrForbidden, "verifier %v expired at epoch %d", verifier, audit.Expiration)
+		}
+
 		// Validate client to be added isn't a verifier
 		found, err = verifiers.Get(abi.AddrKey(client), nil)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier")
@@ -196,11 +233,19 @@ func (a Actor) AddVerifiedClient(rt runtime.Runtime, params *AddVerifiedClientPa
 		err = verifiedClients.Put(abi.AddrKey(client), &clientCap)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verified client %v with cap %d", client, clientCap)
 
+		// Record the grant for audit.
+		recordVerifierGrant(rt, &audit, client, params.Allowance)
+		err = audits.Put(abi.AddrKey(verifier), &audit)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update audit for verifier %v", verifier)
+
 		st.Verifiers, err = verifiers.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifiers")
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		st.VerifierAudits, err = audits.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifier audits")
 	})
 
 	return nil
@@ -230,31 +275,7 @@ func (a Actor) UseBytes(rt runtime.Runtime, params *UseBytesParams) *abi.EmptyVa
 		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
 
-		var vcCap DataCap
-		found, err := verifiedClients.Get(abi.AddrKey(client), &vcCap)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
-		if !found {
-			rt.Abortf(exitcode.ErrNotFound, "no such verified client %v", client)
-		}
-		builtin.RequireState(rt, vcCap.GreaterThanEqual(big.Zero()), "negative cap for client %v: %v", client, vcCap)
-
-		if params.DealSize.GreaterThan(vcCap) {
-			rt.Abortf(exitcode.ErrIllegalArgument, "DealSize %d exceeds allowable cap: %d for VerifiedClient %v", params.DealSize, vcCap, client)
-		}
-
-		newVcCap := big.Sub(vcCap, params.DealSize)
-		if newVcCap.LessThan(MinVerifiedDealSize) {
-			// Delete entry if remaining DataCap is less than MinVerifiedDealSize.
-			// Will be restored later if the deal did not get activated with a ProvenSector.
-			//
-			// NOTE: Technically, client could lose up to MinVerifiedDealSize worth of DataCap.
-			// See: https://github.com/filecoin-project/specs-actors/issues/727
-			err = verifiedClients.Delete(abi.AddrKey(client))
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", client)
-		} else {
-			err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
-			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", client, newVcCap)
-		}
+		useClientDataCap(rt, verifiedClients, client, params.DealSize)
 
 		st.VerifiedClients, err = verifiedClients.Root()
 		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
@@ -288,32 +309,7 @@ func (a Actor) RestoreBytes(rt runtime.Runtime, params *RestoreBytesParams) *abi
 	}
 
 	rt.StateTransaction(&st, func() {
-		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
-
-		verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers, builtin.DefaultHamtBitwidth)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
-
-		// validate we are NOT attempting to do this for a verifier
-		found, err := verifiers.Get(abi.AddrKey(client), nil)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier")
-		if found {
-			rt.Abortf(exitcode.ErrIllegalArgument, "cannot restore allowance for a verifier")
-		}
-
-		var vcCap DataCap
-		found, err = verifiedClients.Get(abi.AddrKey(client), &vcCap)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
-		if !found {
-			vcCap = big.Zero()
-		}
-
-		newVcCap := big.Add(vcCap, params.DealSize)
-		err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put verified client %v with %v", client, newVcCap)
-
-		st.VerifiedClients, err = verifiedClients.Root()
-		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+		restoreClientDataCap(rt, &st, client, params.DealSize)
 	})
 
 	return nil
@@ -404,3 +400,697 @@ func (a Actor) RemoveVerifiedClientDataCap(rt runtime.Runtime, params *RemoveDat
 		DataCapRemoved: removedDataCapAmount,
 	}
 }
+
+type TransferDataCapParams struct {
+	// The client to receive the DataCap.
+	To addr.Address
+	// The amount of DataCap to transfer.
+	Amount DataCap
+	// A verifier's signature over a TransferDataCapProposal.
+	// Optional, unless the recipient is not already a verified client.
+	VerifierRequest *RemoveDataCapRequest
+}
+
+// Transfers part of the calling verified client's DataCap to another client.
+// A transfer to an address which is not already a verified client must be co-signed by a verifier.
+func (a Actor) TransferDataCap(rt runtime.Runtime, params *TransferDataCapParams) *abi.EmptyValue {
+	// The caller will be verified by checking the verified clients table below.
+	rt.ValidateImmediateCallerAcceptAny()
+	from := rt.Caller()
+
+	if params.Amount.LessThan(MinVerifiedDealSize) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "transfer amount %d below MinVerifiedDealSize", params.Amount)
+	}
+
+	to, err := builtin.ResolveToIDAddr(rt, params.To)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve recipient address %v to ID address", params.To)
+	builtin.RequireParam(rt, to != from, "cannot transfer DataCap to self")
+
+	var st State
+	rt.StateTransaction(&st, func() {
+		if to == st.RootKey {
+			rt.Abortf(exitcode.ErrIllegalArgument, "cannot transfer DataCap to the root key")
+		}
+		if isVerifier(rt, st, to) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "cannot transfer DataCap to verifier %v", to)
+		}
+
+		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+		var fromCap DataCap
+		found, err := verifiedClients.Get(abi.AddrKey(from), &fromCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", from)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no such verified client %v", from)
+		}
+		if params.Amount.GreaterThan(fromCap) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "transfer amount %d exceeds DataCap %d of client %v", params.Amount, fromCap, from)
+		}
+		remaining := big.Sub(fromCap, params.Amount)
+		if !remaining.IsZero() && remaining.LessThan(MinVerifiedDealSize) {
+			rt.Abortf(exitcode.ErrIllegalArgument, "remaining DataCap %d below MinVerifiedDealSize", remaining)
+		}
+
+		var toCap DataCap
+		found, err = verifiedClients.Get(abi.AddrKey(to), &toCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", to)
+		if !found {
+			toCap = big.Zero()
+		}
+
+		if params.VerifierRequest != nil {
+			verifier, err := builtin.ResolveToIDAddr(rt, params.VerifierRequest.Verifier)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve verifier address %v to ID address", params.VerifierRequest.Verifier)
+			if !isVerifier(rt, st, verifier) {
+				rt.Abortf(exitcode.ErrIllegalArgument, "%v is not a verifier", params.VerifierRequest.Verifier)
+			}
+
+			audits, err := adt.AsMap(adt.AsStore(rt), st.VerifierAudits, builtin.DefaultHamtBitwidth)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifier audits")
+			audit := loadVerifierAudit(rt, audits, verifier)
+			if audit.Expiration != 0 && rt.CurrEpoch() >= audit.Expiration {
+				rt.Abortf(exitcode.ErrForbidden, "verifier %v expired at epoch %d", verifier, audit.Expiration)
+			}
+
+			proposalIDs, err := adt.AsMap(adt.AsStore(rt), st.RemoveDataCapProposalIDs, builtin.DefaultHamtBitwidth)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load datacap proposal ids")
+			id := useProposalID(rt, proposalIDs, verifier, from)
+			transferDataCapRequestIsValidOrAbort(rt, *params.VerifierRequest, id, from, to, params.Amount)
+
+			st.RemoveDataCapProposalIDs, err = proposalIDs.Root()
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush proposal ids")
+		} else if !found {
+			rt.Abortf(exitcode.ErrForbidden, "transfer to %v, which is not a verified client, requires a verifier signature", to)
+		}
+
+		if remaining.IsZero() {
+			err = verifiedClients.Delete(abi.AddrKey(from))
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", from)
+		} else {
+			err = verifiedClients.Put(abi.AddrKey(from), &remaining)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", from, remaining)
+		}
+
+		newToCap := big.Add(toCap, params.Amount)
+		err = verifiedClients.Put(abi.AddrKey(to), &newToCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", to, newToCap)
+
+		st.VerifiedClients, err = verifiedClients.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+	})
+
+	return nil
+}
+
+type AllocationRequest struct {
+	// The provider which may claim the allocation.
+	Provider addr.Address
+	// Identifier of the data to be committed.
+	Data cid.Cid `checked:"true"` // Checked in CreateAllocations, CommP
+	// The (padded) size of the data.
+	Size abi.PaddedPieceSize
+	// The minimum and maximum duration for which the provider must commit the data.
+	TermMin abi.ChainEpoch
+	TermMax abi.ChainEpoch
+	// The latest epoch by which the allocation may be claimed.
+	Expiration abi.ChainEpoch
+}
+
+type CreateAllocationsParams struct {
+	Allocations []AllocationRequest
+}
+
+type CreateAllocationsReturn struct {
+	AllocationIDs []AllocationID
+}
+
+// Allocates some of the calling verified client's DataCap to specific providers and pieces.
+// The allocated DataCap is deducted from the client's cap, and returned to it if the allocation expires unclaimed.
+func (a Actor) CreateAllocations(rt runtime.Runtime, params *CreateAllocationsParams) *CreateAllocationsReturn {
+	// The caller will be verified by checking the verified clients table below.
+	rt.ValidateImmediateCallerAcceptAny()
+	client := rt.Caller()
+	currEpoch := rt.CurrEpoch()
+
+	allocations := make([]Allocation, len(params.Allocations))
+	for i := range params.Allocations {
+		req := &params.Allocations[i]
+		allocations[i] = validateAllocationRequest(rt, i, client, req, currEpoch)
+		builtin.RequireParam(rt, req.Expiration <= currEpoch+MaximumVerifiedAllocationExpiration,
+			"allocation %d expiration %d exceeds maximum %d", i, req.Expiration, currEpoch+MaximumVerifiedAllocationExpiration)
+	}
+
+	ids := allocateClientDataCap(rt, client, allocations)
+	return &CreateAllocationsReturn{AllocationIDs: ids}
+}
+
+type AllocateDealDataCapParams struct {
+	// The client of the verified deal, whose DataCap is allocated.
+	Client addr.Address
+	// The allocation of DataCap to the deal's provider and piece, for the deal's term.
+	Allocation AllocationRequest
+	// An allocation previously created by the client for the deal, to use rather than allocating DataCap,
+	// or NoAllocationID.
+	ClientAllocationID AllocationID
+}
+
+type AllocateDealDataCapReturn struct {
+	AllocationID AllocationID
+}
+
+// Called by StorageMarketActor when a verified deal is published, to allocate the client's DataCap to the deal's
+// provider and piece. The deal claims the allocation when it is activated.
+// The allocation expires at the deal's start epoch, which the market bounds, rather than within MaximumVerifiedAllocationExpiration.
+// If the deal references an allocation created by the client, that allocation is transferred to the deal instead.
+func (a Actor) AllocateDealDataCap(rt runtime.Runtime, params *AllocateDealDataCapParams) *AllocateDealDataCapReturn {
+	rt.ValidateImmediateCallerIs(builtin.StorageMarketActorAddr)
+
+	client, err := builtin.ResolveToIDAddr(rt, params.Client)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve client address %v", params.Client)
+
+	alloc := validateAllocationRequest(rt, 0, client, &params.Allocation, rt.CurrEpoch())
+	if params.ClientAllocationID != NoAllocationID {
+		id := transferClientAllocation(rt, client, params.ClientAllocationID, &alloc)
+		return &AllocateDealDataCapReturn{AllocationID: id}
+	}
+	ids := allocateClientDataCap(rt, client, []Allocation{alloc})
+	return &AllocateDealDataCapReturn{AllocationID: ids[0]}
+}
+
+type ClaimAllocationRequest struct {
+	// The allocation to claim.
+	AllocationID AllocationID
+	// The client and provider of the activated deal.
+	Client   addr.Address
+	Provider addr.Address
+	// Identifier of the data committed.
+	Data cid.Cid `checked:"true"` // Checked by the market when publishing the deal, CommP
+	// The (padded) size of the data.
+	Size abi.PaddedPieceSize
+	// The epoch until which the data is committed, from the current epoch.
+	TermEnd abi.ChainEpoch
+	// The market deal committing the data.
+	DealID abi.DealID
+}
+
+type ClaimAllocationsParams struct {
+	Claims []ClaimAllocationRequest
+	// Whether to abort unless every request claims its allocation, rather than skipping requests which cannot.
+	AllOrNothing bool
+}
+
+type ClaimAllocationsReturn struct {
+	// Indices of the requests which claimed an allocation.
+	ClaimedAllocations bitfield.BitField
+	// The claims made, in the order of ClaimedAllocations.
+	ClaimIDs []ClaimID
+}
+
+// Called by StorageMarketActor when verified deals are activated, to claim the allocations made for them.
+// A request claims the identified allocation if it is an unexpired allocation of the request's client to its
+// provider for the same data and size, whose term bounds admit the request's term. Other requests are skipped,
+// or abort the call if AllOrNothing is set.
+func (a Actor) ClaimAllocations(rt runtime.Runtime, params *ClaimAllocationsParams) *ClaimAllocationsReturn {
+	rt.ValidateImmediateCallerIs(builtin.StorageMarketActorAddr)
+	currEpoch := rt.CurrEpoch()
+
+	var claimedIdxs []uint64
+	var claimIDs []ClaimID
+	var st State
+	rt.StateTransaction(&st, func() {
+		store := adt.AsStore(rt)
+		allocs, err := AsMapMap(store, st.Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load allocations")
+		claims, err := AsMapMap(store, st.Claims, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
+
+		for i, req := range params.Claims {
+			id := req.AllocationID
+			var alloc Allocation
+			found, err := allocs.Get(abi.AddrKey(req.Client), id, &alloc)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get allocation %d", id)
+			if !found || !allocationClaimable(&alloc, &req, currEpoch) {
+				if params.AllOrNothing {
+					rt.Abortf(exitcode.ErrIllegalArgument, "allocation %d of client %v not claimable by deal %d", id, req.Client, req.DealID)
+				}
+				rt.Log(rtt.INFO, "allocation %d of client %v not claimable by deal %d", id, req.Client, req.DealID)
+				continue
+			}
+
+			_, err = allocs.TryDelete(abi.AddrKey(alloc.Client), id)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove allocation %d", id)
+			err = claims.Put(abi.AddrKey(alloc.Provider), id, &Claim{
+				Provider:  alloc.Provider,
+				Client:    alloc.Client,
+				Data:      alloc.Data,
+				Size:      alloc.Size,
+				TermMin:   alloc.TermMin,
+				TermMax:   alloc.TermMax,
+				TermStart: currEpoch,
+				DealID:    req.DealID,
+			})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put claim %d", id)
+
+			claimedIdxs = append(claimedIdxs, uint64(i))
+			claimIDs = append(claimIDs, id)
+		}
+
+		st.Allocations, err = allocs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush allocations")
+		st.Claims, err = claims.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush claims")
+	})
+
+	return &ClaimAllocationsReturn{
+		ClaimedAllocations: bitfield.NewFromSet(claimedIdxs),
+		ClaimIDs:           claimIDs,
+	}
+}
+
+type RemoveExpiredAllocationsParams struct {
+	// The client whose allocations to remove.
+	Client addr.Address
+	// The allocations to remove, or empty to remove all of the client's expired allocations.
+	AllocationIDs []AllocationID
+}
+
+type RemoveExpiredAllocationsReturn struct {
+	// The allocations which were removed.
+	Removed []AllocationID
+	// The DataCap returned to the client.
+	DataCapRecovered DataCap
+}
+
+// Removes a client's allocations which have expired unclaimed, restoring their DataCap to the client.
+// Allocations which are not found or not yet expired are skipped.
+// May be called by anyone.
+func (a Actor) RemoveExpiredAllocations(rt runtime.Runtime, params *RemoveExpiredAllocationsParams) *RemoveExpiredAllocationsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	currEpoch := rt.CurrEpoch()
+
+	client, err := builtin.ResolveToIDAddr(rt, params.Client)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve client address %v", params.Client)
+
+	var removed []AllocationID
+	recovered := big.Zero()
+	var st State
+	rt.StateTransaction(&st, func() {
+		store := adt.AsStore(rt)
+		allocs, err := AsMapMap(store, st.Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load allocations")
+
+		toRemove := params.AllocationIDs
+		if len(toRemove) == 0 {
+			var alloc Allocation
+			err = allocs.ForEachIn(abi.AddrKey(client), &alloc, func(key string) error {
+				id, err := abi.ParseUIntKey(key)
+				if err != nil {
+					return err
+				}
+				toRemove = append(toRemove, AllocationID(id))
+				return nil
+			})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate allocations of %v", client)
+		}
+
+		for _, id := range toRemove {
+			var alloc Allocation
+			found, err := allocs.Get(abi.AddrKey(client), id, &alloc)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get allocation %d", id)
+			if !found {
+				rt.Log(rtt.INFO, "no allocation %d of client %v, skipping", id, client)
+				continue
+			}
+			if alloc.Expiration >= currEpoch {
+				rt.Log(rtt.INFO, "allocation %d expires at %d, skipping", id, alloc.Expiration)
+				continue
+			}
+
+			_, err = allocs.TryDelete(abi.AddrKey(client), id)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove allocation %d", id)
+			removed = append(removed, id)
+			recovered = big.Add(recovered, big.NewIntUnsigned(uint64(alloc.Size)))
+		}
+
+		if !recovered.IsZero() {
+			restoreClientDataCap(rt, &st, client, recovered)
+		}
+
+		st.Allocations, err = allocs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush allocations")
+	})
+
+	return &RemoveExpiredAllocationsReturn{
+		Removed:          removed,
+		DataCapRecovered: recovered,
+	}
+}
+
+type RemoveExpiredClaimsParams struct {
+	// The provider whose claims to remove.
+	Provider addr.Address
+	// The claims to remove, or empty to remove all of the provider's expired claims.
+	ClaimIDs []ClaimID
+}
+
+type RemoveExpiredClaimsReturn struct {
+	// The claims which were removed.
+	Removed []ClaimID
+}
+
+// Removes a provider's claims whose maximum term has elapsed, after which the provider is no longer committed
+// to storing the claimed data. Claims which are not found or not yet expired are skipped.
+// May be called by anyone.
+func (a Actor) RemoveExpiredClaims(rt runtime.Runtime, params *RemoveExpiredClaimsParams) *RemoveExpiredClaimsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+	currEpoch := rt.CurrEpoch()
+
+	provider, err := builtin.ResolveToIDAddr(rt, params.Provider)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve provider address %v", params.Provider)
+
+	var removed []ClaimID
+	var st State
+	rt.StateTransaction(&st, func() {
+		claims, err := AsMapMap(adt.AsStore(rt), st.Claims, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
+
+		toRemove := params.ClaimIDs
+		if len(toRemove) == 0 {
+			var claim Claim
+			err = claims.ForEachIn(abi.AddrKey(provider), &claim, func(key string) error {
+				id, err := abi.ParseUIntKey(key)
+				if err != nil {
+					return err
+				}
+				toRemove = append(toRemove, ClaimID(id))
+				return nil
+			})
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to iterate claims of %v", provider)
+		}
+
+		for _, id := range toRemove {
+			var claim Claim
+			found, err := claims.Get(abi.AddrKey(provider), id, &claim)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get claim %d", id)
+			if !found {
+				rt.Log(rtt.INFO, "no claim %d of provider %v, skipping", id, provider)
+				continue
+			}
+			if claim.TermStart+claim.TermMax >= currEpoch {
+				rt.Log(rtt.INFO, "claim %d expires at %d, skipping", id, claim.TermStart+claim.TermMax)
+				continue
+			}
+
+			_, err = claims.TryDelete(abi.AddrKey(provider), id)
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove claim %d", id)
+			removed = append(removed, id)
+		}
+
+		st.Claims, err = claims.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush claims")
+	})
+
+	return &RemoveExpiredClaimsReturn{Removed: removed}
+}
+
+type GetClaimsParams struct {
+	Provider addr.Address
+	ClaimIDs []ClaimID
+}
+
+type GetClaimsReturn struct {
+	Claims []Claim
+}
+
+// Returns a provider's claims of allocations.
+func (a Actor) GetClaims(rt runtime.Runtime, params *GetClaimsParams) *GetClaimsReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	provider, err := builtin.ResolveToIDAddr(rt, params.Provider)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve provider address %v", params.Provider)
+
+	var st State
+	rt.StateReadonly(&st)
+	claims, err := AsMapMap(adt.AsStore(rt), st.Claims, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load claims")
+
+	ret := make([]Claim, len(params.ClaimIDs))
+	for i, id := range params.ClaimIDs {
+		found, err := claims.Get(abi.AddrKey(provider), id, &ret[i])
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get claim %d", id)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no claim %d by provider %v", id, provider)
+		}
+	}
+	return &GetClaimsReturn{Claims: ret}
+}
+
+type SetVerifierExpirationParams struct {
+	Verifier addr.Address
+	// The epoch from which the verifier may no longer add verified clients, or zero for no expiry.
+	Expiration abi.ChainEpoch
+}
+
+// Sets or extends the epoch after which a verifier may no longer add verified clients.
+// Called by the root key holder.
+func (a Actor) SetVerifierExpiration(rt runtime.Runtime, params *SetVerifierExpirationParams) *abi.EmptyValue {
+	verifier, err := builtin.ResolveToIDAddr(rt, params.Verifier)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve verifier address %v to ID address", params.Verifier)
+
+	var st State
+	rt.StateReadonly(&st)
+	rt.ValidateImmediateCallerIs(st.RootKey)
+
+	builtin.RequireParam(rt, params.Expiration >= 0, "negative expiration %d", params.Expiration)
+	builtin.RequireParam(rt, params.Expiration == 0 || params.Expiration > rt.CurrEpoch(),
+		"expiration %d must be after current epoch %d", params.Expiration, rt.CurrEpoch())
+
+	rt.StateTransaction(&st, func() {
+		if !isVerifier(rt, st, verifier) {
+			rt.Abortf(exitcode.ErrNotFound, "no such verifier %v", verifier)
+		}
+
+		audits, err := adt.AsMap(adt.AsStore(rt), st.VerifierAudits, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifier audits")
+
+		audit := loadVerifierAudit(rt, audits, verifier)
+		audit.Expiration = params.Expiration
+		err = audits.Put(abi.AddrKey(verifier), &audit)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update audit for verifier %v", verifier)
+
+		st.VerifierAudits, err = audits.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifier audits")
+	})
+
+	return nil
+}
+
+type GetVerifierAuditReturn struct {
+	// The verifier's remaining DataCap.
+	Allowance DataCap
+	// The cumulative DataCap granted to clients.
+	Granted DataCap
+	// The number of distinct clients granted DataCap.
+	Clients uint64
+	// The epoch from which the verifier may no longer add verified clients, or zero for no expiry.
+	Expiration abi.ChainEpoch
+}
+
+// Returns a summary of a verifier's allowance and grants, for audit.
+// A removed verifier's grants remain available, with zero allowance.
+func (a Actor) GetVerifierAudit(rt runtime.Runtime, verifierAddr *addr.Address) *GetVerifierAuditReturn {
+	rt.ValidateImmediateCallerAcceptAny()
+
+	verifier, err := builtin.ResolveToIDAddr(rt, *verifierAddr)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve verifier address %v to ID address", *verifierAddr)
+
+	var st State
+	rt.StateReadonly(&st)
+
+	verifiers, err := adt.AsMap(adt.AsStore(rt), st.Verifiers, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifiers")
+
+	allowance := big.Zero()
+	isVerifier, err := verifiers.Get(abi.AddrKey(verifier), &allowance)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier %v", verifier)
+
+	audits, err := adt.AsMap(adt.AsStore(rt), st.VerifierAudits, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifier audits")
+	hasAudit, err := audits.Get(abi.AddrKey(verifier), nil)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load audit for verifier %v", verifier)
+	if !isVerifier && !hasAudit {
+		rt.Abortf(exitcode.ErrNotFound, "no such verifier %v", verifier)
+	}
+	audit := loadVerifierAudit(rt, audits, verifier)
+
+	return &GetVerifierAuditReturn{
+		Allowance:  allowance,
+		Granted:    audit.Granted,
+		Clients:    audit.ClientCount,
+		Expiration: audit.Expiration,
+	}
+}
+
+// Deducts DataCap from a verified client.
+// Deletes the client if its remaining DataCap is smaller than the minimum VerifiedDealSize.
+func useClientDataCap(rt runtime.Runtime, verifiedClients *adt.Map, client addr.Address, amount DataCap) {
+	var vcCap DataCap
+	found, err := verifiedClients.Get(abi.AddrKey(client), &vcCap)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
+	if !found {
+		rt.Abortf(exitcode.ErrNotFound, "no such verified client %v", client)
+	}
+	builtin.RequireState(rt, vcCap.GreaterThanEqual(big.Zero()), "negative cap for client %v: %v", client, vcCap)
+
+	if amount.GreaterThan(vcCap) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "DealSize %d exceeds allowable cap: %d for VerifiedClient %v", amount, vcCap, client)
+	}
+
+	newVcCap := big.Sub(vcCap, amount)
+	if newVcCap.LessThan(MinVerifiedDealSize) {
+		// Delete entry if remaining DataCap is less than MinVerifiedDealSize.
+		// Will be restored later if the deal did not get activated with a ProvenSector.
+		//
+		// NOTE: Technically, client could lose up to MinVerifiedDealSize worth of DataCap.
+		// See: https://github.com/filecoin-project/specs-actors/issues/727
+		err = verifiedClients.Delete(abi.AddrKey(client))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to delete verified client %v", client)
+	} else {
+		err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to update verified client %v with %v", client, newVcCap)
+	}
+}
+
+// Adds DataCap to a client, creating a new entry if the client has been deleted.
+// The client must not be a verifier.
+func restoreClientDataCap(rt runtime.Runtime, st *State, client addr.Address, amount DataCap) {
+	if isVerifier(rt, *st, client) {
+		rt.Abortf(exitcode.ErrIllegalArgument, "cannot restore allowance for a verifier")
+	}
+
+	verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+	var vcCap DataCap
+	found, err := verifiedClients.Get(abi.AddrKey(client), &vcCap)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verified client %v", client)
+	if !found {
+		vcCap = big.Zero()
+	}
+
+	newVcCap := big.Add(vcCap, amount)
+	err = verifiedClients.Put(abi.AddrKey(client), &newVcCap)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put verified client %v with %v", client, newVcCap)
+
+	st.VerifiedClients, err = verifiedClients.Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+}
+
+// Checks whether a request may claim an allocation: the allocation must be unexpired, for the request's
+// provider, data and size, with term bounds admitting the request's term.
+func allocationClaimable(alloc *Allocation, req *ClaimAllocationRequest, currEpoch abi.ChainEpoch) bool {
+	if alloc.Provider != req.Provider || !alloc.Data.Equals(req.Data) || alloc.Size != req.Size {
+		return false
+	}
+	term := req.TermEnd - currEpoch
+	return alloc.Expiration >= currEpoch && term >= alloc.TermMin && term <= alloc.TermMax
+}
+
+// Validates a request to allocate a client's DataCap, returning the allocation.
+// The maximum expiration depends on the caller and is checked separately.
+func validateAllocationRequest(rt runtime.Runtime, i int, client addr.Address, req *AllocationRequest, currEpoch abi.ChainEpoch) Allocation {
+	provider, err := builtin.ResolveToIDAddr(rt, req.Provider)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalArgument, "failed to resolve provider address %v", req.Provider)
+
+	builtin.RequireParam(rt, req.Size.Validate() == nil, "allocation %d size %d is invalid", i, req.Size)
+	size := big.NewIntUnsigned(uint64(req.Size))
+	builtin.RequireParam(rt, size.GreaterThanEqual(MinVerifiedDealSize), "allocation %d size %d below minimum %d", i, req.Size, MinVerifiedDealSize)
+	builtin.RequireParam(rt, req.Data.Defined(), "allocation %d data undefined", i)
+	builtin.RequireParam(rt, req.Data.Prefix() == market0.PieceCIDPrefix, "allocation %d data had wrong prefix", i)
+	builtin.RequireParam(rt, req.TermMin >= MinimumVerifiedAllocationTerm, "allocation %d term min %d below minimum %d", i, req.TermMin, MinimumVerifiedAllocationTerm)
+	builtin.RequireParam(rt, req.TermMax <= MaximumVerifiedAllocationTerm, "allocation %d term max %d above maximum %d", i, req.TermMax, MaximumVerifiedAllocationTerm)
+	builtin.RequireParam(rt, req.TermMin <= req.TermMax, "allocation %d term min %d exceeds term max %d", i, req.TermMin, req.TermMax)
+	builtin.RequireParam(rt, req.Expiration > currEpoch, "allocation %d expiration %d has already passed at %d", i, req.Expiration, currEpoch)
+
+	return Allocation{
+		Client:     client,
+		Provider:   provider,
+		Data:       req.Data,
+		Size:       req.Size,
+		TermMin:    req.TermMin,
+		TermMax:    req.TermMax,
+		Expiration: req.Expiration,
+	}
+}
+
+// Transfers an existing allocation of a client to a deal requiring an allocation `req`, returning its new ID.
+// The allocation must admit every claim that the requested allocation would. It is recorded under a new ID,
+// so that the client's ID can be referenced by no other deal.
+func transferClientAllocation(rt runtime.Runtime, client addr.Address, id AllocationID, req *Allocation) AllocationID {
+	var newID AllocationID
+	var st State
+	rt.StateTransaction(&st, func() {
+		allocs, err := AsMapMap(adt.AsStore(rt), st.Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load allocations")
+
+		var alloc Allocation
+		found, err := allocs.Get(abi.AddrKey(client), id, &alloc)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get allocation %d", id)
+		if !found {
+			rt.Abortf(exitcode.ErrNotFound, "no allocation %d of client %v", id, client)
+		}
+		builtin.RequireParam(rt, alloc.Provider == req.Provider && alloc.Data.Equals(req.Data) && alloc.Size == req.Size,
+			"allocation %d does not match the deal's provider, data and size", id)
+		builtin.RequireParam(rt, alloc.TermMin <= req.TermMin && alloc.TermMax >= req.TermMax,
+			"allocation %d term [%d, %d] does not admit the deal's term [%d, %d]", id, alloc.TermMin, alloc.TermMax, req.TermMin, req.TermMax)
+		builtin.RequireParam(rt, alloc.Expiration >= req.Expiration,
+			"allocation %d expiration %d precedes the deal's start %d", id, alloc.Expiration, req.Expiration)
+
+		_, err = allocs.TryDelete(abi.AddrKey(client), id)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to remove allocation %d", id)
+		newID = st.NextAllocationId
+		st.NextAllocationId++
+		err = allocs.Put(abi.AddrKey(client), newID, &alloc)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put allocation %d", newID)
+
+		st.Allocations, err = allocs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush allocations")
+	})
+	return newID
+}
+
+// Deducts the total size of allocations from a client's DataCap and records them, returning their IDs.
+func allocateClientDataCap(rt runtime.Runtime, client addr.Address, allocations []Allocation) []AllocationID {
+	totalSize := big.Zero()
+	for _, alloc := range allocations {
+		totalSize = big.Add(totalSize, big.NewIntUnsigned(uint64(alloc.Size)))
+	}
+
+	ids := make([]AllocationID, len(allocations))
+	var st State
+	rt.StateTransaction(&st, func() {
+		verifiedClients, err := adt.AsMap(adt.AsStore(rt), st.VerifiedClients, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verified clients")
+
+		useClientDataCap(rt, verifiedClients, client, totalSize)
+
+		allocs, err := AsMapMap(adt.AsStore(rt), st.Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load allocations")
+
+		for i := range allocations {
+			ids[i] = st.NextAllocationId
+			st.NextAllocationId++
+
+			err = allocs.Put(abi.AddrKey(client), ids[i], &allocations[i])
+			builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to put allocation %d", ids[i])
+		}
+
+		st.VerifiedClients, err = verifiedClients.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verified clients")
+
+		st.Allocations, err = allocs.Root()
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush allocations")
+	})
+	return ids
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/filecoin-project/go-address"
 	addr "github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime"
@@ -21,6 +22,7 @@ import (
 type DataCap = abi.StoragePower
 
 const SignatureDomainSeparation_RemoveDataCap = "fil_removedatacap:"
+const SignatureDomainSeparation_TransferDataCap = "fil_transferdatacap:"
 
 type RmDcProposalID struct {
 	ProposalID uint64
@@ -42,10 +44,93 @@ type State struct {
 	//specific client. Unique proposal ids ensure that removal proposals cannot be replayed.√
 	// AddrPairKey is constructed as <verifier address, client address>, both using ID addresses.
 	RemoveDataCapProposalIDs cid.Cid // HAMT[AddrPairKey]RmDcProposalID
+
+	// Allocations of datacap by clients to specific providers and pieces, not yet claimed.
+	Allocations cid.Cid // HAMT[addr.Address]HAMT[AllocationID]Allocation
+
+	// Next allocation identifier to use.
+	// Claims are identified by the ID of the allocation they claimed.
+	NextAllocationId AllocationID
+
+	// Allocations claimed by providers, retained for auditing.
+	Claims cid.Cid // HAMT[addr.Address]HAMT[ClaimID]Claim
+
+	// Audit records of the allowance verifiers have granted, and their expiry.
+	VerifierAudits cid.Cid // HAMT[addr.Address]VerifierAudit
+
+	// Datacap held by clients and in verified deals when audit records began, which was granted
+	// without a record. Together with the audit records, this bounds the datacap in circulation.
+	DataCapBaseline DataCap
 }
 
 var MinVerifiedDealSize = abi.NewStoragePower(1 << 20)
 
+// The minimum and maximum term of the data committed by an allocation.
+const MinimumVerifiedAllocationTerm = abi.ChainEpoch(180 * builtin.EpochsInDay)
+const MaximumVerifiedAllocationTerm = abi.ChainEpoch(5 * builtin.EpochsInYear)
+
+// The maximum period, from its creation, after which an unclaimed allocation expires.
+const MaximumVerifiedAllocationExpiration = abi.ChainEpoch(60 * builtin.EpochsInDay)
+
+type AllocationID uint64
+type ClaimID = AllocationID
+
+// Allocation IDs are assigned from 1, so the zero ID identifies no allocation.
+const NoAllocationID = AllocationID(0)
+
+func (id AllocationID) Key() string {
+	return abi.UIntKey(uint64(id)).Key()
+}
+
+// An allocation of a client's datacap to a provider for storing a piece.
+type Allocation struct {
+	// The client which allocated the datacap.
+	Client addr.Address
+	// The provider which may claim the allocation.
+	Provider addr.Address
+	// Identifier of the data to be committed.
+	Data cid.Cid
+	// The (padded) size of the data.
+	Size abi.PaddedPieceSize
+	// The minimum and maximum duration for which the provider must commit the data.
+	TermMin abi.ChainEpoch
+	TermMax abi.ChainEpoch
+	// The latest epoch by which the allocation may be claimed.
+	Expiration abi.ChainEpoch
+}
+
+// A provider's claim of an allocation, committing to store the allocated piece.
+type Claim struct {
+	// The provider storing the data.
+	Provider addr.Address
+	// The client which allocated the datacap.
+	Client addr.Address
+	// Identifier of the data committed.
+	Data cid.Cid
+	// The (padded) size of the data.
+	Size abi.PaddedPieceSize
+	// The minimum and maximum duration for which the provider committed the data.
+	TermMin abi.ChainEpoch
+	TermMax abi.ChainEpoch
+	// The epoch at which the data was committed.
+	TermStart abi.ChainEpoch
+	// The market deal which claimed the allocation.
+	DealID abi.DealID
+}
+
+// A record of a verifier's activity, kept for governance audit.
+type VerifierAudit struct {
+	// The cumulative DataCap granted to clients.
+	Granted DataCap
+	// The number of distinct clients granted DataCap.
+	ClientCount uint64
+	// The distinct clients granted DataCap.
+	Clients cid.Cid // HAMT[addr.Address]EmptyValue
+	// The epoch from which the verifier may no longer add verified clients.
+	// Zero if the verifier does not expire.
+	Expiration abi.ChainEpoch
+}
+
 // rootKeyAddress comes from genesis.
 func ConstructState(store adt.Store, rootKeyAddress addr.Address) (*State, error) {
 	emptyMapCid, err := adt.StoreEmptyMap(store, builtin.DefaultHamtBitwidth)
@@ -53,11 +138,21 @@ func ConstructState(store adt.Store, rootKeyAddress addr.Address) (*State, error
 		return nil, xerrors.Errorf("failed to create empty map: %w", err)
 	}
 
+	emptyMapMapCid, err := StoreEmptyMapMap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to create empty map of maps: %w", err)
+	}
+
 	return &State{
 		RootKey:                  rootKeyAddress,
 		Verifiers:                emptyMapCid,
 		VerifiedClients:          emptyMapCid,
 		RemoveDataCapProposalIDs: emptyMapCid,
+		Allocations:              emptyMapMapCid,
+		NextAllocationId:         1,
+		Claims:                   emptyMapMapCid,
+		VerifierAudits:           emptyMapCid,
+		DataCapBaseline:          big.Zero(),
 	}, nil
 }
 
@@ -72,6 +167,19 @@ type RemoveDataCapProposal struct {
 	RemovalProposalID RmDcProposalID
 }
 
+// A verifier who wants to co-sign a transfer of DataCap between clients should sign a TransferDataCapProposal
+// and send the signed proposal to the transferring client.
+type TransferDataCapProposal struct {
+	// From and To are the ID addresses of the transferring and receiving clients.
+	From addr.Address
+	To   addr.Address
+	// Amount is the amount of DataCap to be transferred.
+	Amount DataCap
+	// ProposalID is the counter of proposals sent by the Verifier for the transferring client.
+	// The counter is shared with datacap removal proposals.
+	ProposalID RmDcProposalID
+}
+
 // A verifier who wants to submit a request should send their RemoveDataCapRequest to the RKH.
 type RemoveDataCapRequest struct {
 	// Verifier is the verifier address used for VerifierSignature.
@@ -91,6 +199,36 @@ func isVerifier(rt runtime.Runtime, st State, address addr.Address) bool {
 	return ok
 }
 
+// Loads a verifier's audit record, or an empty record if the verifier has none.
+func loadVerifierAudit(rt runtime.Runtime, audits *adt.Map, verifier addr.Address) VerifierAudit {
+	var audit VerifierAudit
+	found, err := audits.Get(abi.AddrKey(verifier), &audit)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load audit for verifier %v", verifier)
+	if !found {
+		emptyClients, err := adt.StoreEmptyMap(adt.AsStore(rt), builtin.DefaultHamtBitwidth)
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to create empty client set")
+		return VerifierAudit{Granted: big.Zero(), Clients: emptyClients}
+	}
+	return audit
+}
+
+// Records a grant of DataCap by a verifier to a client in the verifier's audit record.
+func recordVerifierGrant(rt runtime.Runtime, audit *VerifierAudit, client addr.Address, amount DataCap) {
+	clients, err := adt.AsSet(adt.AsStore(rt), audit.Clients, builtin.DefaultHamtBitwidth)
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to load verifier clients")
+	found, err := clients.Has(abi.AddrKey(client))
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to get verifier client %v", client)
+	if !found {
+		err = clients.Put(abi.AddrKey(client))
+		builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to add verifier client %v", client)
+		audit.ClientCount++
+	}
+
+	audit.Granted = big.Add(audit.Granted, amount)
+	audit.Clients, err = clients.Root()
+	builtin.RequireNoErr(rt, err, exitcode.ErrIllegalState, "failed to flush verifier clients")
+}
+
 ////////////////////////////////////////////////////////////////////////////////
 // State utility functions
 ////////////////////////////////////////////////////////////////////////////////
@@ -111,6 +249,24 @@ func removeDataCapRequestIsValidOrAbort(rt runtime.Runtime, request RemoveDataCa
 	}
 }
 
+func transferDataCapRequestIsValidOrAbort(rt runtime.Runtime, request RemoveDataCapRequest, id RmDcProposalID, from, to address.Address, amount DataCap) {
+	proposal := TransferDataCapProposal{
+		From:       from,
+		To:         to,
+		Amount:     amount,
+		ProposalID: id,
+	}
+	buf := bytes.Buffer{}
+	buf.WriteString(SignatureDomainSeparation_TransferDataCap)
+	if err := proposal.MarshalCBOR(&buf); err != nil {
+		rt.Abortf(exitcode.ErrSerialization, "transfer datacap request failed to marshal request: %s", err)
+	}
+
+	if err := rt.VerifySignature(request.VerifierSignature, request.Verifier, buf.Bytes()); err != nil {
+		rt.Abortf(exitcode.ErrIllegalArgument, "transfer datacap request signature is invalid: %s", err)
+	}
+}
+
 func useProposalID(rt runtime.Runtime, proposalIDs *adt.Map, verifier, client address.Address) RmDcProposalID {
 	var id RmDcProposalID
 	idExists, err := proposalIDs.Get(abi.NewAddrPairKey(verifier, client), &id)
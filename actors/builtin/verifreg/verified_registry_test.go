@@ -1,17 +1,22 @@
 package verifreg_test
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/crypto"
 	"github.com/filecoin-project/go-state-types/exitcode"
+	cid "github.com/ipfs/go-cid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/v8/support/mock"
@@ -749,6 +754,642 @@ func TestRestoreBytes(t *testing.T) {
 	})
 }
 
+func TestAllocations(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	client := tutil.NewIDAddr(t, 201)
+	client2 := tutil.NewIDAddr(t, 202)
+	verifier := tutil.NewIDAddr(t, 301)
+	provider := tutil.NewIDAddr(t, 401)
+	provider2 := tutil.NewIDAddr(t, 402)
+
+	clientCap := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(4))
+	size := abi.PaddedPieceSize(1 << 20)
+	data := tutil.MakeCID("1", &market.PieceCIDPrefix)
+	currEpoch := abi.ChainEpoch(100)
+	expiration := currEpoch + 10*builtin.EpochsInDay
+	termMin := verifreg.MinimumVerifiedAllocationTerm
+	termMax := termMin + 100*builtin.EpochsInDay
+
+	request := func(provider address.Address, data cid.Cid) verifreg.AllocationRequest {
+		return verifreg.AllocationRequest{
+			Provider:   provider,
+			Data:       data,
+			Size:       size,
+			TermMin:    termMin,
+			TermMax:    termMax,
+			Expiration: expiration,
+		}
+	}
+	claimRequest := func(id verifreg.AllocationID, client, provider address.Address, data cid.Cid, termEnd abi.ChainEpoch, dealID abi.DealID) verifreg.ClaimAllocationRequest {
+		return verifreg.ClaimAllocationRequest{
+			AllocationID: id,
+			Client:       client,
+			Provider:     provider,
+			Data:         data,
+			Size:         size,
+			TermEnd:      termEnd,
+			DealID:       dealID,
+		}
+	}
+	setup := func(t *testing.T) (*mock.Runtime, *verifRegActorTestHarness) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifier, client, verifreg.MinVerifiedDealSize, clientCap)
+		rt.SetEpoch(currEpoch)
+		return rt, ac
+	}
+
+	t.Run("create allocations deducts client datacap", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		ids := ac.createAllocations(rt, client, request(provider, data), request(provider2, data))
+		assert.Equal(t, []verifreg.AllocationID{1, 2}, ids)
+		assert.EqualValues(t, big.Sub(clientCap, big.NewInt(2*int64(size))), ac.getClientCap(rt, client))
+
+		alloc := ac.getAllocation(rt, client, 2)
+		assert.Equal(t, client, alloc.Client)
+		assert.Equal(t, provider2, alloc.Provider)
+		assert.Equal(t, data, alloc.Data)
+		assert.Equal(t, expiration, alloc.Expiration)
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to create invalid allocations", func(t *testing.T) {
+		for name, modify := range map[string]func(*verifreg.AllocationRequest){ // nolint:nomaprange
+			"size below minimum":        func(r *verifreg.AllocationRequest) { r.Size = size / 2 },
+			"data not a piece":          func(r *verifreg.AllocationRequest) { r.Data = tutil.MakeCID("1", nil) },
+			"term min below minimum":    func(r *verifreg.AllocationRequest) { r.TermMin = verifreg.MinimumVerifiedAllocationTerm - 1 },
+			"term max above maximum":    func(r *verifreg.AllocationRequest) { r.TermMax = verifreg.MaximumVerifiedAllocationTerm + 1 },
+			"term min exceeds term max": func(r *verifreg.AllocationRequest) { r.TermMax = r.TermMin - 1 },
+			"already expired":           func(r *verifreg.AllocationRequest) { r.Expiration = currEpoch },
+			"expiration too far": func(r *verifreg.AllocationRequest) {
+				r.Expiration = currEpoch + verifreg.MaximumVerifiedAllocationExpiration + 1
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rt, ac := setup(t)
+				req := request(provider, data)
+				modify(&req)
+
+				rt.SetCaller(client, builtin.AccountActorCodeID)
+				rt.ExpectValidateCallerAny()
+				rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+					rt.Call(ac.CreateAllocations, &verifreg.CreateAllocationsParams{Allocations: []verifreg.AllocationRequest{req}})
+				})
+				rt.Verify()
+				ac.checkState(rt)
+			})
+		}
+	})
+
+	t.Run("fails to create allocations exceeding client datacap", func(t *testing.T) {
+		rt, ac := setup(t)
+		reqs := make([]verifreg.AllocationRequest, 5)
+		for i := range reqs {
+			reqs[i] = request(provider, data)
+		}
+
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.CreateAllocations, &verifreg.CreateAllocationsParams{Allocations: reqs})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to create allocations from a non-client", func(t *testing.T) {
+		rt, ac := setup(t)
+		rt.SetCaller(client2, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.CreateAllocations, &verifreg.CreateAllocationsParams{Allocations: []verifreg.AllocationRequest{request(provider, data)}})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("claims matching allocations", func(t *testing.T) {
+		rt, ac := setup(t)
+		otherData := tutil.MakeCID("2", &market.PieceCIDPrefix)
+		ids := ac.createAllocations(rt, client, request(provider, data), request(provider, otherData))
+
+		rt.SetEpoch(currEpoch + 1)
+		termEnd := currEpoch + 1 + termMin
+		ret := ac.claimAllocations(rt,
+			claimRequest(ids[0], client, provider, data, termEnd, 10),
+			claimRequest(ids[1], client, provider2, otherData, termEnd, 11),            // wrong provider
+			claimRequest(ids[1], client, provider, data, termEnd, 12),                  // wrong data
+			claimRequest(ids[1], client, provider, otherData, currEpoch+termMin, 13),   // term too short
+			claimRequest(ids[1], client, provider, otherData, currEpoch+termMax+2, 14), // term too long
+			claimRequest(ids[1], client2, provider, otherData, termEnd, 15),            // wrong client
+			claimRequest(99, client, provider, otherData, termEnd, 16),                 // no such allocation
+			claimRequest(ids[0], client, provider, data, termEnd, 17),                  // already claimed
+			claimRequest(ids[1], client, provider, otherData, termEnd, 18),
+		)
+		claimed, err := ret.ClaimedAllocations.All(1 << 20)
+		require.NoError(t, err)
+		assert.Equal(t, []uint64{0, 8}, claimed)
+		assert.Equal(t, []verifreg.ClaimID{ids[0], ids[1]}, ret.ClaimIDs)
+
+		claims := ac.getClaims(rt, provider, ids...)
+		assert.Equal(t, verifreg.Claim{
+			Provider:  provider,
+			Client:    client,
+			Data:      data,
+			Size:      size,
+			TermMin:   termMin,
+			TermMax:   termMax,
+			TermStart: currEpoch + 1,
+			DealID:    10,
+		}, claims[0])
+		assert.Equal(t, abi.DealID(18), claims[1].DealID)
+		ac.assertNoAllocation(rt, client, ids...)
+		ac.checkState(rt)
+	})
+
+	t.Run("expired allocations cannot be claimed", func(t *testing.T) {
+		rt, ac := setup(t)
+		ids := ac.createAllocations(rt, client, request(provider, data))
+
+		rt.SetEpoch(expiration + 1)
+		ret := ac.claimAllocations(rt, claimRequest(ids[0], client, provider, data, expiration+1+termMin, 10))
+		assert.Empty(t, ret.ClaimIDs)
+		ac.getAllocation(rt, client, ids[0])
+		ac.checkState(rt)
+	})
+
+	t.Run("claims all or nothing", func(t *testing.T) {
+		rt, ac := setup(t)
+		otherData := tutil.MakeCID("2", &market.PieceCIDPrefix)
+		ids := ac.createAllocations(rt, client, request(provider, data), request(provider, otherData))
+
+		termEnd := currEpoch + termMin
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.ExpectAbortContainsMessage(exitcode.ErrIllegalArgument, "not claimable", func() {
+			rt.Call(ac.ClaimAllocations, &verifreg.ClaimAllocationsParams{Claims: []verifreg.ClaimAllocationRequest{
+				claimRequest(ids[0], client, provider, data, termEnd, 10),
+				claimRequest(ids[1], client, provider2, otherData, termEnd, 11), // wrong provider
+			}, AllOrNothing: true})
+		})
+		rt.Verify()
+
+		// Neither allocation was claimed.
+		ac.getAllocation(rt, client, ids[0])
+		ac.getAllocation(rt, client, ids[1])
+
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		ret := rt.Call(ac.ClaimAllocations, &verifreg.ClaimAllocationsParams{Claims: []verifreg.ClaimAllocationRequest{
+			claimRequest(ids[0], client, provider, data, termEnd, 10),
+			claimRequest(ids[1], client, provider, otherData, termEnd, 11),
+		}, AllOrNothing: true}).(*verifreg.ClaimAllocationsReturn)
+		rt.Verify()
+		assert.Equal(t, []verifreg.ClaimID{ids[0], ids[1]}, ret.ClaimIDs)
+		ac.assertNoAllocation(rt, client, ids...)
+		ac.checkState(rt)
+	})
+
+	t.Run("only the market may claim allocations", func(t *testing.T) {
+		rt, ac := setup(t)
+		rt.SetCaller(provider, builtin.StorageMinerActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.ClaimAllocations, &verifreg.ClaimAllocationsParams{})
+		})
+		rt.Verify()
+	})
+
+	t.Run("fails to get claims which do not exist", func(t *testing.T) {
+		rt, ac := setup(t)
+		ac.createAllocations(rt, client, request(provider, data))
+
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.GetClaims, &verifreg.GetClaimsParams{Provider: provider, ClaimIDs: []verifreg.ClaimID{1}})
+		})
+		rt.Verify()
+	})
+
+	t.Run("removes expired allocations and restores datacap", func(t *testing.T) {
+		rt, ac := setup(t)
+		ids := ac.createAllocations(rt, client, request(provider, data), request(provider2, data))
+		// A later allocation which expires later.
+		rt.SetEpoch(currEpoch + 1)
+		later := request(provider, data)
+		later.Expiration = expiration + 1
+		laterIDs := ac.createAllocations(rt, client, later)
+		assert.EqualValues(t, big.Sub(clientCap, big.NewInt(3*int64(size))), ac.getClientCap(rt, client))
+
+		// Nothing has expired yet.
+		rt.SetEpoch(expiration)
+		ret := ac.removeExpiredAllocations(rt, client)
+		assert.Empty(t, ret.Removed)
+		assert.EqualValues(t, big.Zero(), ret.DataCapRecovered)
+
+		rt.SetEpoch(expiration + 1)
+		ret = ac.removeExpiredAllocations(rt, client)
+		assert.ElementsMatch(t, ids, ret.Removed)
+		assert.EqualValues(t, big.NewInt(2*int64(size)), ret.DataCapRecovered)
+		assert.EqualValues(t, big.Sub(clientCap, big.NewInt(int64(size))), ac.getClientCap(rt, client))
+		ac.assertNoAllocation(rt, client, ids...)
+
+		rt.SetEpoch(expiration + 2)
+		ret = ac.removeExpiredAllocations(rt, client, append(laterIDs, 99)...)
+		assert.Equal(t, laterIDs, ret.Removed)
+		assert.EqualValues(t, clientCap, ac.getClientCap(rt, client))
+		ac.checkState(rt)
+	})
+
+	t.Run("allocates datacap for a deal", func(t *testing.T) {
+		rt, ac := setup(t)
+		// A deal's allocation expires at its start epoch, which may be later than a client's allocation.
+		req := request(provider, data)
+		req.Expiration = currEpoch + verifreg.MaximumVerifiedAllocationExpiration + 1
+		id := ac.allocateDealDataCap(rt, client, req)
+		assert.Equal(t, verifreg.AllocationID(1), id)
+		assert.EqualValues(t, big.Sub(clientCap, big.NewInt(int64(size))), ac.getClientCap(rt, client))
+		assert.Equal(t, req.Expiration, ac.getAllocation(rt, client, id).Expiration)
+
+		ret := ac.claimAllocations(rt, claimRequest(id, client, provider, data, currEpoch+termMin, 10))
+		assert.Equal(t, []verifreg.ClaimID{id}, ret.ClaimIDs)
+		ac.checkState(rt)
+	})
+
+	t.Run("transfers a client's allocation to a deal", func(t *testing.T) {
+		rt, ac := setup(t)
+		ids := ac.createAllocations(rt, client, request(provider, data))
+		capAfterCreate := ac.getClientCap(rt, client)
+
+		// The deal's term and expiration lie within the client allocation's bounds.
+		req := request(provider, data)
+		req.TermMax = termMin + 10
+		req.Expiration = expiration - 1
+		allocate := func(id verifreg.AllocationID) *verifreg.AllocateDealDataCapReturn {
+			rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+			rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+			params := &verifreg.AllocateDealDataCapParams{Client: client, Allocation: req, ClientAllocationID: id}
+			ret := rt.Call(ac.AllocateDealDataCap, params).(*verifreg.AllocateDealDataCapReturn)
+			rt.Verify()
+			return ret
+		}
+		id := allocate(ids[0]).AllocationID
+		assert.Equal(t, ids[0]+1, id)
+		assert.EqualValues(t, capAfterCreate, ac.getClientCap(rt, client))
+
+		// The allocation is moved to its new ID, unchanged.
+		ac.assertNoAllocation(rt, client, ids[0])
+		alloc := ac.getAllocation(rt, client, id)
+		assert.Equal(t, termMax, alloc.TermMax)
+		assert.Equal(t, expiration, alloc.Expiration)
+
+		// The client's ID cannot be referenced by another deal.
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.AllocateDealDataCap, &verifreg.AllocateDealDataCapParams{Client: client, Allocation: req, ClientAllocationID: ids[0]})
+		})
+		rt.Verify()
+
+		ret := ac.claimAllocations(rt, claimRequest(id, client, provider, data, currEpoch+termMin, 10))
+		assert.Equal(t, []verifreg.ClaimID{id}, ret.ClaimIDs)
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to transfer a client allocation which does not admit the deal", func(t *testing.T) {
+		for name, modify := range map[string]func(*verifreg.AllocationRequest){ // nolint:nomaprange
+			"different provider": func(r *verifreg.AllocationRequest) { r.Provider = provider2 },
+			"different data":     func(r *verifreg.AllocationRequest) { r.Data = tutil.MakeCID("2", &market.PieceCIDPrefix) },
+			"term max above":     func(r *verifreg.AllocationRequest) { r.TermMax = termMax + 1 },
+			"expiration after":   func(r *verifreg.AllocationRequest) { r.Expiration = expiration + 1 },
+		} {
+			t.Run(name, func(t *testing.T) {
+				rt, ac := setup(t)
+				ids := ac.createAllocations(rt, client, request(provider, data))
+				req := request(provider, data)
+				modify(&req)
+
+				rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+				rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+				rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+					rt.Call(ac.AllocateDealDataCap, &verifreg.AllocateDealDataCapParams{Client: client, Allocation: req, ClientAllocationID: ids[0]})
+				})
+				rt.Verify()
+				ac.getAllocation(rt, client, ids[0])
+				ac.checkState(rt)
+			})
+		}
+	})
+
+	t.Run("fails to allocate deal datacap beyond the client's cap", func(t *testing.T) {
+		rt, ac := setup(t)
+		req := request(provider, data)
+		req.Size = abi.PaddedPieceSize(8 << 20)
+
+		rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.AllocateDealDataCap, &verifreg.AllocateDealDataCapParams{Client: client, Allocation: req})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("only the market may allocate deal datacap", func(t *testing.T) {
+		rt, ac := setup(t)
+		rt.SetCaller(client, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.AllocateDealDataCap, &verifreg.AllocateDealDataCapParams{Client: client, Allocation: request(provider, data)})
+		})
+		rt.Verify()
+	})
+
+	t.Run("removes claims whose maximum term has elapsed", func(t *testing.T) {
+		rt, ac := setup(t)
+		ids := ac.createAllocations(rt, client, request(provider, data), request(provider, data))
+		ac.claimAllocations(rt, claimRequest(ids[0], client, provider, data, currEpoch+termMin, 10))
+		rt.SetEpoch(currEpoch + 1)
+		ac.claimAllocations(rt, claimRequest(ids[1], client, provider, data, currEpoch+1+termMin, 11))
+
+		rt.SetEpoch(currEpoch + termMax)
+		ret := ac.removeExpiredClaims(rt, provider)
+		assert.Empty(t, ret.Removed)
+
+		rt.SetEpoch(currEpoch + termMax + 1)
+		ret = ac.removeExpiredClaims(rt, provider)
+		assert.Equal(t, ids[:1], ret.Removed)
+		ac.getClaims(rt, provider, ids[1])
+
+		rt.SetEpoch(currEpoch + termMax + 2)
+		ret = ac.removeExpiredClaims(rt, provider, ids[1], 99)
+		assert.Equal(t, ids[1:], ret.Removed)
+		ac.checkState(rt)
+	})
+
+	t.Run("removing expired allocations restores datacap to a deleted client", func(t *testing.T) {
+		rt, ac := setup(t)
+		reqs := make([]verifreg.AllocationRequest, 4)
+		for i := range reqs {
+			reqs[i] = request(provider, data)
+		}
+		ids := ac.createAllocations(rt, client, reqs...)
+		ac.assertClientRemoved(rt, client)
+
+		rt.SetEpoch(expiration + 1)
+		ret := ac.removeExpiredAllocations(rt, client, ids[0])
+		assert.Equal(t, ids[:1], ret.Removed)
+		assert.EqualValues(t, big.NewInt(int64(size)), ac.getClientCap(rt, client))
+		ac.checkState(rt)
+	})
+}
+
+func TestVerifierAudit(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	verifierAddr := tutil.NewIDAddr(t, 301)
+	clientAddr := tutil.NewIDAddr(t, 201)
+	clientAddr2 := tutil.NewIDAddr(t, 202)
+	clientAllowance := verifreg.MinVerifiedDealSize
+	verifierAllowance := big.Mul(clientAllowance, big.NewInt(3))
+
+	t.Run("records grants to clients", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+
+		audit := ac.getVerifierAudit(rt, verifierAddr)
+		assert.EqualValues(t, verifierAllowance, audit.Allowance)
+		assert.EqualValues(t, big.Zero(), audit.Granted)
+		assert.Equal(t, uint64(0), audit.Clients)
+		assert.Equal(t, abi.ChainEpoch(0), audit.Expiration)
+
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance, clientAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr2, clientAllowance, clientAllowance)
+
+		audit = ac.getVerifierAudit(rt, verifierAddr)
+		assert.EqualValues(t, clientAllowance, audit.Allowance)
+		assert.EqualValues(t, big.Mul(clientAllowance, big.NewInt(2)), audit.Granted)
+		assert.Equal(t, uint64(2), audit.Clients)
+		ac.checkState(rt)
+	})
+
+	t.Run("expired verifier cannot add clients until extended", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+
+		rt.SetEpoch(100)
+		ac.setVerifierExpiration(rt, verifierAddr, 200)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance, clientAllowance)
+
+		rt.SetEpoch(200)
+		rt.SetCaller(verifierAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbortContainsMessage(exitcode.ErrForbidden, "expired", func() {
+			rt.Call(ac.AddVerifiedClient, mkClientParams(clientAddr2, clientAllowance))
+		})
+		rt.Verify()
+
+		ac.setVerifierExpiration(rt, verifierAddr, 300)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr2, clientAllowance, clientAllowance)
+		assert.Equal(t, abi.ChainEpoch(300), ac.getVerifierAudit(rt, verifierAddr).Expiration)
+
+		// Clearing the expiration removes the limit.
+		ac.setVerifierExpiration(rt, verifierAddr, 0)
+		rt.SetEpoch(1000)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr2, clientAllowance, big.Mul(clientAllowance, big.NewInt(2)))
+		assert.Equal(t, uint64(2), ac.getVerifierAudit(rt, verifierAddr).Clients)
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to set invalid expiration", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+		rt.SetEpoch(100)
+
+		// not the root key
+		rt.SetCaller(verifierAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(root)
+		rt.ExpectAbort(exitcode.SysErrForbidden, func() {
+			rt.Call(ac.SetVerifierExpiration, &verifreg.SetVerifierExpirationParams{Verifier: verifierAddr, Expiration: 200})
+		})
+		rt.Verify()
+
+		// not in the future
+		rt.SetCaller(root, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAddr(root)
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.SetVerifierExpiration, &verifreg.SetVerifierExpirationParams{Verifier: verifierAddr, Expiration: 100})
+		})
+		rt.Verify()
+
+		// not a verifier
+		rt.ExpectValidateCallerAddr(root)
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.SetVerifierExpiration, &verifreg.SetVerifierExpirationParams{Verifier: clientAddr, Expiration: 200})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("removing a verifier retains its audit", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance, clientAllowance)
+		ac.removeVerifier(rt, verifierAddr)
+
+		audit := ac.getVerifierAudit(rt, verifierAddr)
+		assert.EqualValues(t, big.Zero(), audit.Allowance)
+		assert.EqualValues(t, clientAllowance, audit.Granted)
+		assert.Equal(t, uint64(1), audit.Clients)
+		ac.checkState(rt)
+
+		// A verifier added again continues its record.
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr2, clientAllowance, clientAllowance)
+		audit = ac.getVerifierAudit(rt, verifierAddr)
+		assert.EqualValues(t, big.Mul(clientAllowance, big.NewInt(2)), audit.Granted)
+		assert.Equal(t, uint64(2), audit.Clients)
+		ac.checkState(rt)
+	})
+
+	t.Run("adding a verifier again clears its expiration", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+		rt.SetEpoch(100)
+		ac.setVerifierExpiration(rt, verifierAddr, 200)
+		ac.removeVerifier(rt, verifierAddr)
+
+		rt.SetEpoch(300)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+		assert.Equal(t, abi.ChainEpoch(0), ac.getVerifierAudit(rt, verifierAddr).Expiration)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance, clientAllowance)
+		ac.checkState(rt)
+	})
+
+	t.Run("counts a client granted more than once as one client", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.addVerifier(rt, verifierAddr, verifierAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance, clientAllowance)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr, clientAllowance, big.Mul(clientAllowance, big.NewInt(2)))
+
+		audit := ac.getVerifierAudit(rt, verifierAddr)
+		assert.EqualValues(t, big.Mul(clientAllowance, big.NewInt(2)), audit.Granted)
+		assert.Equal(t, uint64(1), audit.Clients)
+		ac.checkState(rt)
+	})
+
+	t.Run("no audit for an address that was never a verifier", func(t *testing.T) {
+		rt, ac := basicVerifRegSetup(t, root)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.GetVerifierAudit, &verifierAddr)
+		})
+		rt.Verify()
+	})
+}
+
+func TestTransferDataCap(t *testing.T) {
+	root := tutil.NewIDAddr(t, 101)
+	clientAddr := tutil.NewIDAddr(t, 201)
+	clientAddr2 := tutil.NewIDAddr(t, 202)
+	verifierAddr := tutil.NewIDAddr(t, 301)
+	verifierAddr2 := tutil.NewIDAddr(t, 302)
+	clientAllowance := big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(3))
+
+	setup := func(t *testing.T) (*mock.Runtime, *verifRegActorTestHarness) {
+		rt, ac := basicVerifRegSetup(t, root)
+		ac.generateAndAddVerifierAndVerifiedClient(rt, verifierAddr, clientAddr, verifreg.MinVerifiedDealSize, clientAllowance)
+		ac.addVerifier(rt, verifierAddr2, verifreg.MinVerifiedDealSize)
+		return rt, ac
+	}
+
+	t.Run("transfers to an existing client without a verifier", func(t *testing.T) {
+		rt, ac := setup(t)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr2, verifreg.MinVerifiedDealSize, verifreg.MinVerifiedDealSize)
+
+		ac.transferDataCap(rt, clientAddr, &verifreg.TransferDataCapParams{To: clientAddr2, Amount: verifreg.MinVerifiedDealSize})
+		assert.EqualValues(t, big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2)), ac.getClientCap(rt, clientAddr))
+		assert.EqualValues(t, big.Mul(verifreg.MinVerifiedDealSize, big.NewInt(2)), ac.getClientCap(rt, clientAddr2))
+
+		// transferring all remaining datacap removes the client
+		ac.transferDataCap(rt, clientAddr, &verifreg.TransferDataCapParams{To: clientAddr2, Amount: ac.getClientCap(rt, clientAddr)})
+		ac.assertClientRemoved(rt, clientAddr)
+		assert.EqualValues(t, big.Add(clientAllowance, verifreg.MinVerifiedDealSize), ac.getClientCap(rt, clientAddr2))
+		ac.checkState(rt)
+	})
+
+	t.Run("transfers to a new client with a verifier signature", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		params := &verifreg.TransferDataCapParams{
+			To:              clientAddr2,
+			Amount:          verifreg.MinVerifiedDealSize,
+			VerifierRequest: &verifreg.RemoveDataCapRequest{Verifier: verifierAddr2, VerifierSignature: crypto.Signature{Type: crypto.SigTypeBLS, Data: []byte("sig")}},
+		}
+		ac.expectTransferSignature(rt, params, clientAddr, 0, nil)
+		ac.transferDataCap(rt, clientAddr, params)
+		assert.EqualValues(t, verifreg.MinVerifiedDealSize, ac.getClientCap(rt, clientAddr2))
+
+		// the signature cannot be replayed
+		ac.expectTransferSignature(rt, params, clientAddr, 1, xerrors.New("bad signature"))
+		rt.SetCaller(clientAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.TransferDataCap, params)
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to transfer to a new client without a verifier signature", func(t *testing.T) {
+		rt, ac := setup(t)
+		rt.SetCaller(clientAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrForbidden, func() {
+			rt.Call(ac.TransferDataCap, &verifreg.TransferDataCapParams{To: clientAddr2, Amount: verifreg.MinVerifiedDealSize})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to transfer amounts below minimum on either side", func(t *testing.T) {
+		rt, ac := setup(t)
+		ac.addVerifiedClient(rt, verifierAddr, clientAddr2, verifreg.MinVerifiedDealSize, verifreg.MinVerifiedDealSize)
+
+		for _, amount := range []verifreg.DataCap{
+			big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1)),                           // below minimum
+			big.Sub(clientAllowance, big.Sub(verifreg.MinVerifiedDealSize, big.NewInt(1))), // leaves less than minimum
+			big.Add(clientAllowance, big.NewInt(1)),                                        // exceeds datacap
+		} {
+			rt.SetCaller(clientAddr, builtin.AccountActorCodeID)
+			rt.ExpectValidateCallerAny()
+			rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+				rt.Call(ac.TransferDataCap, &verifreg.TransferDataCapParams{To: clientAddr2, Amount: amount})
+			})
+			rt.Verify()
+		}
+		ac.checkState(rt)
+	})
+
+	t.Run("fails to transfer to a verifier or from a non-client", func(t *testing.T) {
+		rt, ac := setup(t)
+
+		rt.SetCaller(clientAddr, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrIllegalArgument, func() {
+			rt.Call(ac.TransferDataCap, &verifreg.TransferDataCapParams{To: verifierAddr2, Amount: verifreg.MinVerifiedDealSize})
+		})
+		rt.Verify()
+
+		rt.SetCaller(clientAddr2, builtin.AccountActorCodeID)
+		rt.ExpectValidateCallerAny()
+		rt.ExpectAbort(exitcode.ErrNotFound, func() {
+			rt.Call(ac.TransferDataCap, &verifreg.TransferDataCapParams{To: clientAddr, Amount: verifreg.MinVerifiedDealSize})
+		})
+		rt.Verify()
+		ac.checkState(rt)
+	})
+}
+
 type verifRegActorTestHarness struct {
 	rootkey address.Address
 	verifreg.Actor
@@ -943,6 +1584,125 @@ func (h *verifRegActorTestHarness) assertClientRemoved(rt *mock.Runtime, a addre
 	assert.False(h.t, found)
 }
 
+func (h *verifRegActorTestHarness) createAllocations(rt *mock.Runtime, client address.Address, reqs ...verifreg.AllocationRequest) []verifreg.AllocationID {
+	rt.SetCaller(client, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+
+	ret := rt.Call(h.CreateAllocations, &verifreg.CreateAllocationsParams{Allocations: reqs}).(*verifreg.CreateAllocationsReturn)
+	rt.Verify()
+	require.Len(h.t, ret.AllocationIDs, len(reqs))
+	return ret.AllocationIDs
+}
+
+func (h *verifRegActorTestHarness) claimAllocations(rt *mock.Runtime, reqs ...verifreg.ClaimAllocationRequest) *verifreg.ClaimAllocationsReturn {
+	rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+	rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+
+	ret := rt.Call(h.ClaimAllocations, &verifreg.ClaimAllocationsParams{Claims: reqs}).(*verifreg.ClaimAllocationsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) removeExpiredAllocations(rt *mock.Runtime, client address.Address, ids ...verifreg.AllocationID) *verifreg.RemoveExpiredAllocationsReturn {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 999), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+
+	params := &verifreg.RemoveExpiredAllocationsParams{Client: client, AllocationIDs: ids}
+	ret := rt.Call(h.RemoveExpiredAllocations, params).(*verifreg.RemoveExpiredAllocationsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) allocateDealDataCap(rt *mock.Runtime, client address.Address, req verifreg.AllocationRequest) verifreg.AllocationID {
+	rt.SetCaller(builtin.StorageMarketActorAddr, builtin.StorageMarketActorCodeID)
+	rt.ExpectValidateCallerAddr(builtin.StorageMarketActorAddr)
+
+	params := &verifreg.AllocateDealDataCapParams{Client: client, Allocation: req}
+	ret := rt.Call(h.AllocateDealDataCap, params).(*verifreg.AllocateDealDataCapReturn)
+	rt.Verify()
+	return ret.AllocationID
+}
+
+func (h *verifRegActorTestHarness) removeExpiredClaims(rt *mock.Runtime, provider address.Address, ids ...verifreg.ClaimID) *verifreg.RemoveExpiredClaimsReturn {
+	rt.SetCaller(tutil.NewIDAddr(h.t, 999), builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+
+	params := &verifreg.RemoveExpiredClaimsParams{Provider: provider, ClaimIDs: ids}
+	ret := rt.Call(h.RemoveExpiredClaims, params).(*verifreg.RemoveExpiredClaimsReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) getClaims(rt *mock.Runtime, provider address.Address, ids ...verifreg.ClaimID) []verifreg.Claim {
+	rt.ExpectValidateCallerAny()
+
+	ret := rt.Call(h.GetClaims, &verifreg.GetClaimsParams{Provider: provider, ClaimIDs: ids}).(*verifreg.GetClaimsReturn)
+	rt.Verify()
+	require.Len(h.t, ret.Claims, len(ids))
+	return ret.Claims
+}
+
+func (h *verifRegActorTestHarness) getAllocation(rt *mock.Runtime, client address.Address, id verifreg.AllocationID) verifreg.Allocation {
+	allocs, err := verifreg.AsMapMap(adt.AsStore(rt), h.state(rt).Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+
+	var alloc verifreg.Allocation
+	found, err := allocs.Get(abi.AddrKey(client), id, &alloc)
+	require.NoError(h.t, err)
+	require.True(h.t, found)
+	return alloc
+}
+
+func (h *verifRegActorTestHarness) assertNoAllocation(rt *mock.Runtime, client address.Address, ids ...verifreg.AllocationID) {
+	allocs, err := verifreg.AsMapMap(adt.AsStore(rt), h.state(rt).Allocations, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(h.t, err)
+
+	for _, id := range ids {
+		found, err := allocs.Get(abi.AddrKey(client), id, nil)
+		require.NoError(h.t, err)
+		assert.False(h.t, found)
+	}
+}
+
+func (h *verifRegActorTestHarness) setVerifierExpiration(rt *mock.Runtime, verifier address.Address, expiration abi.ChainEpoch) {
+	rt.SetCaller(h.rootkey, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAddr(h.rootkey)
+
+	ret := rt.Call(h.SetVerifierExpiration, &verifreg.SetVerifierExpirationParams{Verifier: verifier, Expiration: expiration})
+	rt.Verify()
+	assert.Nil(h.t, ret)
+}
+
+func (h *verifRegActorTestHarness) getVerifierAudit(rt *mock.Runtime, verifier address.Address) *verifreg.GetVerifierAuditReturn {
+	rt.ExpectValidateCallerAny()
+
+	ret := rt.Call(h.GetVerifierAudit, &verifier).(*verifreg.GetVerifierAuditReturn)
+	rt.Verify()
+	return ret
+}
+
+func (h *verifRegActorTestHarness) transferDataCap(rt *mock.Runtime, from address.Address, params *verifreg.TransferDataCapParams) {
+	rt.SetCaller(from, builtin.AccountActorCodeID)
+	rt.ExpectValidateCallerAny()
+
+	ret := rt.Call(h.TransferDataCap, params)
+	rt.Verify()
+	assert.Nil(h.t, ret)
+}
+
+func (h *verifRegActorTestHarness) expectTransferSignature(rt *mock.Runtime, params *verifreg.TransferDataCapParams, from address.Address, id uint64, result error) {
+	proposal := verifreg.TransferDataCapProposal{
+		From:       from,
+		To:         params.To,
+		Amount:     params.Amount,
+		ProposalID: verifreg.RmDcProposalID{ProposalID: id},
+	}
+	buf := bytes.Buffer{}
+	buf.WriteString(verifreg.SignatureDomainSeparation_TransferDataCap)
+	require.NoError(h.t, proposal.MarshalCBOR(&buf))
+	rt.ExpectVerifySignature(params.VerifierRequest.VerifierSignature, params.VerifierRequest.Verifier, buf.Bytes(), result)
+}
+
 func mkVerifierParams(a address.Address, allowance verifreg.DataCap) *verifreg.AddVerifierParams {
 	return &verifreg.AddVerifierParams{Address: a, Allowance: allowance}
 }
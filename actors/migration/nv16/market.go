@@ -6,6 +6,8 @@ import (
 
 	cid "github.com/ipfs/go-cid"
 	cbor "github.com/ipfs/go-ipld-cbor"
+	cbg "github.com/whyrusleeping/cbor-gen"
+	"golang.org/x/xerrors"
 
 	"github.com/filecoin-project/go-state-types/abi"
 
@@ -41,6 +43,36 @@ func (m marketMigrator) migrateState(ctx context.Context, store cbor.IpldStore,
 		return nil, err
 	}
 
+	dealOpsCidOut, err := RescheduleDealOps(ctx, wrappedStore, inState.DealOpsByEpoch, proposalsCidOut, inState.States, inState.LastCron)
+	if err != nil {
+		return nil, err
+	}
+
+	dealsByPieceCidOut, err := IndexDealsByPiece(ctx, wrappedStore, proposalsCidOut, inState.States)
+	if err != nil {
+		return nil, err
+	}
+
+	dealsByPartyCidOut, err := IndexDealsByParty(ctx, wrappedStore, proposalsCidOut)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyAutoWithdrawMapCid, err := adt.StoreEmptyMap(wrappedStore, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	preCommittedDealsCidOut, err := MarkUnactivatedDealsPreCommitted(ctx, wrappedStore, proposalsCidOut, inState.States)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyDealAllocationsMapCid, err := adt.StoreEmptyMap(wrappedStore, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
 	outState := market.State{
 		Proposals:                     proposalsCidOut,
 		States:                        inState.States,
@@ -48,8 +80,13 @@ func (m marketMigrator) migrateState(ctx context.Context, store cbor.IpldStore,
 		EscrowTable:                   inState.EscrowTable,
 		LockedTable:                   inState.LockedTable,
 		NextID:                        inState.NextID,
-		DealOpsByEpoch:                inState.DealOpsByEpoch,
+		DealOpsByEpoch:                dealOpsCidOut,
 		LastCron:                      inState.LastCron,
+		DealsByPiece:                  dealsByPieceCidOut,
+		DealsByParty:                  dealsByPartyCidOut,
+		AutoWithdrawRecipients:        emptyAutoWithdrawMapCid,
+		PreCommittedDeals:             preCommittedDealsCidOut,
+		DealAllocationIds:             emptyDealAllocationsMapCid,
 		TotalClientLockedCollateral:   inState.TotalClientLockedCollateral,
 		TotalProviderLockedCollateral: inState.TotalProviderLockedCollateral,
 		TotalClientStorageFee:         inState.TotalClientStorageFee,
@@ -169,6 +206,167 @@ func UpdatePendingProposals(ctx context.Context, store adt.Store, changedProposa
 	return pendingProposalsCid, nil
 }
 
+// RescheduleDealOps drops the periodic payment updates of active deals, whose payment is now settled on demand.
+// A deal which has received its first update and is not slashed is rescheduled once, for its expiration.
+// Deals awaiting their first update, and slashed deals, keep their scheduled epochs.
+func RescheduleDealOps(ctx context.Context, store adt.Store, dealOpsRoot cid.Cid, proposalsRoot cid.Cid, statesRoot cid.Cid, lastCron abi.ChainEpoch) (cid.Cid, error) {
+	proposals, err := market.AsDealProposalArray(store, proposalsRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	states, err := market.AsDealStateArray(store, statesRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	dealOpsIn, err := adt.AsMap(store, dealOpsRoot, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+	dealOpsOut, err := market.MakeEmptySetMultimap(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var setRoot cbg.CborCid
+	err = dealOpsIn.ForEach(&setRoot, func(key string) error {
+		epochKey, err := abi.ParseUIntKey(key)
+		if err != nil {
+			return err
+		}
+		epoch := abi.ChainEpoch(epochKey)
+		set, err := adt.AsSet(store, cid.Cid(setRoot), builtin.DefaultHamtBitwidth)
+		if err != nil {
+			return err
+		}
+		return set.ForEach(func(k string) error {
+			id, err := abi.ParseUIntKey(k)
+			if err != nil {
+				return err
+			}
+			dealID := abi.DealID(id)
+
+			nextEpoch := epoch
+			state, found, err := states.Get(dealID)
+			if err != nil {
+				return err
+			}
+			if found && state.LastUpdatedEpoch != market.EpochUndefined && state.SlashEpoch == market.EpochUndefined {
+				proposal, found, err := proposals.Get(dealID)
+				if err != nil {
+					return err
+				}
+				if !found {
+					return xerrors.Errorf("missing proposal for scheduled deal %d", dealID)
+				}
+				// A deal past its end epoch awaits expiry at the next cron tick.
+				expiry := proposal.EndEpoch
+				if expiry <= lastCron {
+					expiry = lastCron + 1
+				}
+				nextEpoch = market.GenRandNextEpoch(expiry, dealID)
+			}
+			return dealOpsOut.Put(nextEpoch, dealID)
+		})
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	return dealOpsOut.Root()
+}
+
+// IndexDealsByPiece builds the index of deals by piece, including every deal which has not been terminated.
+// The index is left empty if not enabled.
+func IndexDealsByPiece(ctx context.Context, store adt.Store, proposalsRoot cid.Cid, statesRoot cid.Cid) (cid.Cid, error) {
+	proposals, err := market.AsDealProposalArray(store, proposalsRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	states, err := market.AsDealStateArray(store, statesRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	index, err := market.MakeEmptyPieceDealIndex(store)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if !market.PieceDealIndexEnabled {
+		return index.Root()
+	}
+
+	var proposal market.DealProposal
+	err = proposals.ForEach(&proposal, func(id int64) error {
+		dealID := abi.DealID(id)
+		state, found, err := states.Get(dealID)
+		if err != nil {
+			return err
+		}
+		if found && state.SlashEpoch != market.EpochUndefined {
+			return nil
+		}
+		return index.Put(proposal.PieceCID, dealID)
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	return index.Root()
+}
+
+// IndexDealsByParty builds the index of every deal proposal by its client and provider.
+func IndexDealsByParty(ctx context.Context, store adt.Store, proposalsRoot cid.Cid) (cid.Cid, error) {
+	proposals, err := market.AsDealProposalArray(store, proposalsRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	index, err := market.MakeEmptyPartyDealIndex(store)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var proposal market.DealProposal
+	err = proposals.ForEach(&proposal, func(id int64) error {
+		return index.Put(&proposal, abi.DealID(id))
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	return index.Root()
+}
+
+// MarkUnactivatedDealsPreCommitted builds the set of pre-committed deals.
+// Pre-commitments of deals were not recorded before this version, so every deal not yet activated
+// is conservatively treated as pre-committed, and cannot be cancelled by its client.
+func MarkUnactivatedDealsPreCommitted(ctx context.Context, store adt.Store, proposalsRoot cid.Cid, statesRoot cid.Cid) (cid.Cid, error) {
+	proposals, err := market.AsDealProposalArray(store, proposalsRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	states, err := market.AsDealStateArray(store, statesRoot)
+	if err != nil {
+		return cid.Undef, err
+	}
+	preCommitted, err := adt.MakeEmptySet(store, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var proposal market.DealProposal
+	err = proposals.ForEach(&proposal, func(id int64) error {
+		_, found, err := states.Get(abi.DealID(id))
+		if err != nil {
+			return err
+		}
+		if found {
+			return nil
+		}
+		return preCommitted.Put(abi.UIntKey(uint64(id)))
+	})
+	if err != nil {
+		return cid.Undef, err
+	}
+	return preCommitted.Root()
+}
+
 // An adt.Map key that just preserves the underlying string.
 type StringKey string
 
@@ -0,0 +1,91 @@
+package nv16
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+)
+
+type minerMigrator struct {
+	OutCodeCID cid.Cid
+}
+
+func (m minerMigrator) migratedCodeCID() cid.Cid {
+	return m.OutCodeCID
+}
+
+func (m minerMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState miner7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+
+	infoOut, err := migrateMinerInfo(ctx, store, inState.Info)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to migrate miner info: %w", err)
+	}
+
+	outState := miner.State{
+		Info:                       infoOut,
+		PreCommitDeposits:          inState.PreCommitDeposits,
+		LockedFunds:                inState.LockedFunds,
+		VestingFunds:               inState.VestingFunds,
+		FeeDebt:                    inState.FeeDebt,
+		InitialPledge:              inState.InitialPledge,
+		PreCommittedSectors:        inState.PreCommittedSectors,
+		PreCommittedSectorsCleanUp: inState.PreCommittedSectorsCleanUp,
+		AllocatedSectors:           inState.AllocatedSectors,
+		Sectors:                    inState.Sectors,
+		ProvingPeriodStart:         inState.ProvingPeriodStart,
+		CurrentDeadline:            inState.CurrentDeadline,
+		Deadlines:                  inState.Deadlines,
+		EarlyTerminations:          inState.EarlyTerminations,
+		DeadlineCronActive:         inState.DeadlineCronActive,
+		SectorHistory:              nil, // Recording is opt-in
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.migratedCodeCID(),
+		newHead:    newHead,
+	}, err
+}
+
+// Rewrites miner info with an empty unlocked reserve policy.
+func migrateMinerInfo(ctx context.Context, store cbor.IpldStore, infoIn cid.Cid) (cid.Cid, error) {
+	var inInfo miner7.MinerInfo
+	if err := store.Get(ctx, infoIn, &inInfo); err != nil {
+		return cid.Undef, err
+	}
+
+	var pendingWorkerKey *miner.WorkerKeyChange
+	if inInfo.PendingWorkerKey != nil {
+		pendingWorkerKey = &miner.WorkerKeyChange{
+			NewWorker:   inInfo.PendingWorkerKey.NewWorker,
+			EffectiveAt: inInfo.PendingWorkerKey.EffectiveAt,
+		}
+	}
+
+	outInfo := miner.MinerInfo{
+		Owner:                      inInfo.Owner,
+		Worker:                     inInfo.Worker,
+		ControlAddresses:           inInfo.ControlAddresses,
+		PendingWorkerKey:           pendingWorkerKey,
+		PeerId:                     inInfo.PeerId,
+		Multiaddrs:                 inInfo.Multiaddrs,
+		WindowPoStProofType:        inInfo.WindowPoStProofType,
+		SectorSize:                 inInfo.SectorSize,
+		WindowPoStPartitionSectors: inInfo.WindowPoStPartitionSectors,
+		ConsensusFaultElapsed:      inInfo.ConsensusFaultElapsed,
+		PendingOwnerAddress:        inInfo.PendingOwnerAddress,
+		MinimumUnlockedReserve:     big.Zero(),
+	}
+	return store.Put(ctx, &outInfo)
+}
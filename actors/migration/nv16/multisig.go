@@ -0,0 +1,78 @@
+package nv16
+
+import (
+	"context"
+
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+
+	multisig7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/multisig"
+	adt7 "github.com/filecoin-project/specs-actors/v7/actors/util/adt"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+type multisigMigrator struct {
+	OutCodeCID cid.Cid
+}
+
+func (m multisigMigrator) migratedCodeCID() cid.Cid {
+	return m.OutCodeCID
+}
+
+func (m multisigMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState multisig7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+
+	pendingTxns, err := migratePendingTxns(ctx, store, inState.PendingTxns)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to migrate pending transactions: %w", err)
+	}
+
+	outState := multisig.State{
+		Signers:               inState.Signers,
+		NumApprovalsThreshold: inState.NumApprovalsThreshold,
+		NextTxnID:             inState.NextTxnID,
+		InitialBalance:        inState.InitialBalance,
+		StartEpoch:            inState.StartEpoch,
+		UnlockDuration:        inState.UnlockDuration,
+		PendingTxns:           pendingTxns,
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.migratedCodeCID(),
+		newHead:    newHead,
+	}, err
+}
+
+// Re-writes pending transactions with no expiration.
+func migratePendingTxns(ctx context.Context, store cbor.IpldStore, root cid.Cid) (cid.Cid, error) {
+	inTxns, err := adt7.AsMap(adt7.WrapStore(ctx, store), root, builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+	outTxns, err := adt.MakeEmptyMap(adt.WrapStore(ctx, store), builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	var inTxn multisig7.Transaction
+	if err = inTxns.ForEach(&inTxn, func(key string) error {
+		return outTxns.Put(multisig.StringKey(key), &multisig.Transaction{
+			To:       inTxn.To,
+			Value:    inTxn.Value,
+			Method:   inTxn.Method,
+			Params:   inTxn.Params,
+			Approved: inTxn.Approved,
+		})
+	}); err != nil {
+		return cid.Undef, err
+	}
+	return outTxns.Root()
+}
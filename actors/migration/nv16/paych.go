@@ -0,0 +1,52 @@
+package nv16
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/big"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+
+	paych7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/paych"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/paych"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+type paychMigrator struct {
+	OutCodeCID cid.Cid
+}
+
+func (m paychMigrator) migratedCodeCID() cid.Cid {
+	return m.OutCodeCID
+}
+
+func (m paychMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState paych7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+
+	emptyArrCid, err := adt.StoreEmptyArray(adt.WrapStore(ctx, store), paych.HTLCsAmtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	outState := paych.State{
+		From:            inState.From,
+		To:              inState.To,
+		ToSend:          inState.ToSend,
+		SettlingAt:      inState.SettlingAt,
+		MinSettleHeight: inState.MinSettleHeight,
+		LaneStates:      inState.LaneStates,
+		Withdrawn:       big.Zero(),
+		HTLCs:           emptyArrCid,
+		Locked:          big.Zero(),
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.migratedCodeCID(),
+		newHead:    newHead,
+	}, err
+}
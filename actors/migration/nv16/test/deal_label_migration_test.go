@@ -211,9 +211,24 @@ func TestDealLabelMigration(t *testing.T) {
 	require.NoError(t, checkLabel(oldProposals, proposals, deal5ID))
 	require.NoError(t, checkLabel(oldProposals, proposals, deal6ID))
 
+	// cronned deals are rescheduled for their expiry, while other deals keep their first scheduled epoch
+	dealOps, err := market.AsSetMultimap(adtStore, marketState.DealOpsByEpoch, builtin.DefaultHamtBitwidth, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	dealEnd := dealStart + 365*builtin.EpochsInDay
+	require.False(t, dealOpScheduled(t, dealOps, deal1CronTime+market.DealUpdatesInterval, deal1ID))
+	require.True(t, dealOpScheduled(t, dealOps, market.GenRandNextEpoch(dealEnd, deal1ID), deal1ID))
+	require.False(t, dealOpScheduled(t, dealOps, deal2CronTime+market.DealUpdatesInterval, deal2ID))
+	require.True(t, dealOpScheduled(t, dealOps, market.GenRandNextEpoch(dealEnd, deal2ID), deal2ID))
+	require.True(t, dealOpScheduled(t, dealOps, deal3CronTime, deal3ID))
+	require.True(t, dealOpScheduled(t, dealOps, deal4CronTime, deal4ID))
+	require.True(t, dealOpScheduled(t, dealOps, deal5CronTime, deal5ID))
+	require.True(t, dealOpScheduled(t, dealOps, deal6CronTime, deal6ID))
+
 	var market8State market.State
 	require.NoError(t, v8.GetState(builtin.StorageMarketActorAddr, &market8State))
-	market.CheckStateInvariants(&market8State, v8.Store(), oldMarketActor.Balance, v.GetEpoch()+1)
+	summary, _ := market.CheckStateInvariants(&market8State, v8.Store(), oldMarketActor.Balance, v.GetEpoch()+1)
+	// every deal is indexed by its piece
+	require.Equal(t, uint64(6), summary.PieceIndexCount)
 
 }
 
@@ -301,6 +316,15 @@ func checkMarketProposalsEtcState(t *testing.T, proposals *adt.Array, states *ad
 	require.Equal(t, inPendingProposals, found)
 }
 
+func dealOpScheduled(t *testing.T, dealOps *market.SetMultimap, epoch abi.ChainEpoch, dealID abi.DealID) bool {
+	found := false
+	require.NoError(t, dealOps.ForEach(epoch, func(id abi.DealID) error {
+		found = found || id == dealID
+		return nil
+	}))
+	return found
+}
+
 func checkLabel(v7Proposals *adt.Array, v8Proposals *adt.Array, dealID abi.DealID) error {
 	var dealprop7 market7.DealProposal
 	var dealprop8 market.DealProposal
@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/rt"
+	"github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"github.com/stretchr/testify/require"
+
+	ipld2 "github.com/filecoin-project/specs-actors/v2/support/ipld"
+	miner7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/miner"
+	power7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/power"
+	vm7 "github.com/filecoin-project/specs-actors/v7/support/vm"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/exported"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v8/actors/migration/nv16"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+	"github.com/filecoin-project/specs-actors/v8/support/vm7Util"
+)
+
+func TestMinerMigration(t *testing.T) {
+	ctx := context.Background()
+	log := nv16.TestLogger{TB: t}
+	bs := ipld2.NewSyncBlockStoreInMemory()
+	v := vm7.NewVMWithSingletons(ctx, t, bs)
+
+	addrs := vm7.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	worker := addrs[0]
+
+	// create miner
+	params := power7.CreateMinerParams{
+		Owner:               worker,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	}
+	ret := vm7.ApplyOk(t, v, worker, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &params)
+	minerAddrs, ok := ret.(*power.CreateMinerReturn)
+	require.True(t, ok)
+	v = vm7Util.AdvanceToEpochWithCron(t, v, 200)
+
+	adtStore := adt.WrapStore(ctx, cbor.NewCborStore(bs))
+	var miner7State miner7.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &miner7State))
+	info7, err := miner7State.GetInfo(adtStore)
+	require.NoError(t, err)
+
+	startRoot := v.StateRoot()
+	manifestCid := makeTestManifest(t, adtStore)
+	nextRoot, err := nv16.MigrateStateTree(ctx, adtStore, manifestCid, startRoot, v.GetEpoch(), nv16.Config{MaxWorkers: 1}, log, nv16.NewMemMigrationCache())
+	require.NoError(t, err)
+
+	lookup := map[cid.Cid]rt.VMActor{}
+	for _, ba := range exported.BuiltinActors() {
+		lookup[ba.Code()] = ba
+	}
+	v8, err := vm.NewVMAtEpoch(ctx, lookup, v.Store(), nextRoot, v.GetEpoch())
+	require.NoError(t, err)
+
+	// Miner info is migrated with no unlocked reserve, and sector history is not recorded.
+	var minerState miner.State
+	require.NoError(t, v8.GetState(minerAddrs.IDAddress, &minerState))
+	info, err := minerState.GetInfo(adtStore)
+	require.NoError(t, err)
+	require.Equal(t, info7.Owner, info.Owner)
+	require.Equal(t, info7.Worker, info.Worker)
+	require.Equal(t, info7.PeerId, info.PeerId)
+	require.Equal(t, info7.WindowPoStProofType, info.WindowPoStProofType)
+	require.Equal(t, info7.ConsensusFaultElapsed, info.ConsensusFaultElapsed)
+	require.Equal(t, big.Zero(), info.MinimumUnlockedReserve)
+	require.Nil(t, minerState.SectorHistory)
+
+	stateTree, err := v8.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v8.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v8.GetEpoch()-1)
+	require.NoError(t, err)
+	require.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
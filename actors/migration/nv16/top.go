@@ -93,15 +93,11 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, actorsManifest
 
 	// simple code migrations
 	var simpleMigrations = map[string]cid.Cid{
-		"init":             builtin7.InitActorCodeID,
-		"cron":             builtin7.CronActorCodeID,
-		"account":          builtin7.AccountActorCodeID,
-		"storagepower":     builtin7.StoragePowerActorCodeID,
-		"storageminer":     builtin7.StorageMinerActorCodeID,
-		"paymentchannel":   builtin7.PaymentChannelActorCodeID,
-		"multisig":         builtin7.MultisigActorCodeID,
-		"reward":           builtin7.RewardActorCodeID,
-		"verifiedregistry": builtin7.VerifiedRegistryActorCodeID,
+		"init":         builtin7.InitActorCodeID,
+		"cron":         builtin7.CronActorCodeID,
+		"account":      builtin7.AccountActorCodeID,
+		"storagepower": builtin7.StoragePowerActorCodeID,
+		"reward":       builtin7.RewardActorCodeID,
 	}
 
 	for name, code7Cid := range simpleMigrations { //nolint:nomaprange
@@ -124,6 +120,30 @@ func MigrateStateTree(ctx context.Context, store cbor.IpldStore, actorsManifest
 		return cid.Undef, xerrors.Errorf("code cid for market actor not found in manifest")
 	}
 	migrations[builtin7.StorageMarketActorCodeID] = marketMigrator{market8Cid}
+	miner8Cid, ok := manifest.Get("storageminer")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for miner actor not found in manifest")
+	}
+	migrations[builtin7.StorageMinerActorCodeID] = minerMigrator{miner8Cid}
+	verifreg8Cid, ok := manifest.Get("verifiedregistry")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for verified registry actor not found in manifest")
+	}
+	dataCapBaseline, err := DataCapBaseline(ctx, adtStore, actorsRootIn)
+	if err != nil {
+		return cid.Undef, xerrors.Errorf("failed to compute datacap baseline: %w", err)
+	}
+	migrations[builtin7.VerifiedRegistryActorCodeID] = verifregMigrator{verifreg8Cid, dataCapBaseline}
+	multisig8Cid, ok := manifest.Get("multisig")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for multisig actor not found in manifest")
+	}
+	migrations[builtin7.MultisigActorCodeID] = multisigMigrator{multisig8Cid}
+	paych8Cid, ok := manifest.Get("paymentchannel")
+	if !ok {
+		return cid.Undef, xerrors.Errorf("code cid for payment channel actor not found in manifest")
+	}
+	migrations[builtin7.PaymentChannelActorCodeID] = paychMigrator{paych8Cid}
 
 	if len(migrations)+len(deferredCodeIDs) != len(exported.BuiltinActors()) {
 		return cid.Undef, xerrors.Errorf("incomplete migration specification with %d code CIDs", len(migrations))
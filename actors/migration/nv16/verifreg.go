@@ -0,0 +1,139 @@
+package nv16
+
+import (
+	"context"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	cid "github.com/ipfs/go-cid"
+	cbor "github.com/ipfs/go-ipld-cbor"
+	"golang.org/x/xerrors"
+
+	builtin7 "github.com/filecoin-project/specs-actors/v7/actors/builtin"
+	market7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/market"
+	verifreg7 "github.com/filecoin-project/specs-actors/v7/actors/builtin/verifreg"
+	states7 "github.com/filecoin-project/specs-actors/v7/actors/states"
+	adt7 "github.com/filecoin-project/specs-actors/v7/actors/util/adt"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+)
+
+type verifregMigrator struct {
+	OutCodeCID      cid.Cid
+	DataCapBaseline abi.StoragePower
+}
+
+func (m verifregMigrator) migratedCodeCID() cid.Cid {
+	return m.OutCodeCID
+}
+
+func (m verifregMigrator) migrateState(ctx context.Context, store cbor.IpldStore, in actorMigrationInput) (*actorMigrationResult, error) {
+	var inState verifreg7.State
+	if err := store.Get(ctx, in.head, &inState); err != nil {
+		return nil, err
+	}
+
+	emptyMapCid, err := adt.StoreEmptyMap(adt.WrapStore(ctx, store), builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	emptyMapMapCid, err := verifreg.StoreEmptyMapMap(adt.WrapStore(ctx, store), builtin.DefaultHamtBitwidth)
+	if err != nil {
+		return nil, err
+	}
+
+	outState := verifreg.State{
+		RootKey:                  inState.RootKey,
+		Verifiers:                inState.Verifiers,
+		VerifiedClients:          inState.VerifiedClients,
+		RemoveDataCapProposalIDs: inState.RemoveDataCapProposalIDs,
+		Allocations:              emptyMapMapCid,
+		NextAllocationId:         1,
+		Claims:                   emptyMapMapCid,
+		VerifierAudits:           emptyMapCid,
+		DataCapBaseline:          m.DataCapBaseline,
+	}
+
+	newHead, err := store.Put(ctx, &outState)
+	return &actorMigrationResult{
+		newCodeCID: m.migratedCodeCID(),
+		newHead:    newHead,
+	}, err
+}
+
+// DataCapBaseline computes the datacap granted before verifier audits were recorded: the unspent datacap
+// of verified clients plus the datacap held in unslashed verified deals.
+func DataCapBaseline(ctx context.Context, store adt7.Store, actorsRootIn cid.Cid) (abi.StoragePower, error) {
+	actorsIn, err := states7.LoadTree(store, actorsRootIn)
+	if err != nil {
+		return big.Zero(), err
+	}
+
+	verifregActor, found, err := actorsIn.GetActor(builtin7.VerifiedRegistryActorAddr)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if !found {
+		return big.Zero(), xerrors.Errorf("verified registry actor not found")
+	}
+	var verifregState verifreg7.State
+	if err := store.Get(ctx, verifregActor.Head, &verifregState); err != nil {
+		return big.Zero(), err
+	}
+
+	marketActor, found, err := actorsIn.GetActor(builtin7.StorageMarketActorAddr)
+	if err != nil {
+		return big.Zero(), err
+	}
+	if !found {
+		return big.Zero(), xerrors.Errorf("storage market actor not found")
+	}
+	var marketState market7.State
+	if err := store.Get(ctx, marketActor.Head, &marketState); err != nil {
+		return big.Zero(), err
+	}
+
+	baseline := big.Zero()
+	clients, err := adt7.AsMap(store, verifregState.VerifiedClients, builtin7.DefaultHamtBitwidth)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to load verified clients: %w", err)
+	}
+	var dataCap abi.StoragePower
+	if err := clients.ForEach(&dataCap, func(_ string) error {
+		baseline = big.Add(baseline, dataCap)
+		return nil
+	}); err != nil {
+		return big.Zero(), xerrors.Errorf("failed to iterate verified clients: %w", err)
+	}
+
+	proposals, err := market7.AsDealProposalArray(store, marketState.Proposals)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to load deal proposals: %w", err)
+	}
+	states, err := market7.AsDealStateArray(store, marketState.States)
+	if err != nil {
+		return big.Zero(), xerrors.Errorf("failed to load deal states: %w", err)
+	}
+	var proposal market7.DealProposal
+	if err := proposals.ForEach(&proposal, func(id int64) error {
+		if !proposal.VerifiedDeal {
+			return nil
+		}
+		dealState, found, err := states.Get(abi.DealID(id))
+		if err != nil {
+			return xerrors.Errorf("failed to load deal state %d: %w", id, err)
+		}
+		if found && dealState.SlashEpoch != market.EpochUndefined {
+			return nil
+		}
+		baseline = big.Add(baseline, big.NewIntUnsigned(uint64(proposal.PieceSize)))
+		return nil
+	}); err != nil {
+		return big.Zero(), xerrors.Errorf("failed to iterate deal proposals: %w", err)
+	}
+	return baseline, nil
+}
@@ -143,9 +143,9 @@ func CheckStateInvariants(tree *Tree, expectedBalanceTotal abi.TokenAmount, prio
 
 	CheckMinersAgainstPower(acc, minerSummaries, powerSummary)
 	CheckDealStatesAgainstSectors(acc, minerSummaries, marketSummary)
+	CheckVerifiedDealsAgainstRegistry(acc, verifregSummary, marketSummary)
 
 	_ = initSummary
-	_ = verifregSummary
 	_ = cronSummary
 	_ = marketSummary
 	_ = rewardSummary
@@ -201,6 +201,60 @@ func CheckMinersAgainstPower(acc *builtin.MessageAccumulator, minerSummaries map
 	}
 }
 
+func CheckVerifiedDealsAgainstRegistry(acc *builtin.MessageAccumulator, verifregSummary *verifreg.StateSummary, marketSummary *market.StateSummary) {
+	// Datacap spent on verified deals is drawn from the registry's verified clients, which
+	// exclude the root key and only spend in units of at least the minimum verified deal size.
+	for dealID, deal := range marketSummary.Deals { // nolint:nomaprange
+		if !deal.VerifiedDeal {
+			continue
+		}
+		acc.Require(big.NewIntUnsigned(uint64(deal.PieceSize)).GreaterThanEqual(verifreg.MinVerifiedDealSize),
+			"verified deal %d size %d below minimum verified deal size", dealID, deal.PieceSize)
+	}
+	for client, dataCap := range marketSummary.VerifiedDealDataCap { // nolint:nomaprange
+		acc.Require(client != verifregSummary.RootKey, "root key %v holds datacap %v in verified deals", client, dataCap)
+	}
+
+	// Datacap is conserved: clients can hold no more datacap, whether unspent, allocated or in active verified
+	// deals, than verifiers have granted them plus the baseline which predates audit records.
+	// Datacap restored to a client on deal expiry or termination returns to its unspent datacap, and the
+	// remainder of a terminated deal's datacap is consumed.
+	granted := verifregSummary.DataCapBaseline
+	for _, audit := range verifregSummary.Audits { // nolint:nomaprange
+		granted = big.Add(granted, audit.Granted)
+	}
+	held := big.Zero()
+	for _, dataCap := range verifregSummary.Clients { // nolint:nomaprange
+		held = big.Add(held, dataCap)
+	}
+	for _, alloc := range verifregSummary.Allocations { // nolint:nomaprange
+		held = big.Add(held, big.NewIntUnsigned(uint64(alloc.Size)))
+	}
+	for _, dataCap := range marketSummary.VerifiedDealDataCap { // nolint:nomaprange
+		held = big.Add(held, dataCap)
+	}
+	acc.Require(held.LessThanEqual(granted), "datacap held %v exceeds datacap granted %v", held, granted)
+
+	// The allocation of a deal is pending until the deal is activated, when it is claimed.
+	// A claim may later be removed from the registry once its term has ended.
+	for dealID, allocationID := range marketSummary.DealAllocations { // nolint:nomaprange
+		deal, found := marketSummary.Deals[dealID]
+		if !found {
+			continue
+		}
+		if deal.SectorStartEpoch == abi.ChainEpoch(-1) {
+			alloc, found := verifregSummary.Allocations[allocationID]
+			acc.Require(found, "deal %d pending allocation %d not found in verified registry", dealID, allocationID)
+			if found {
+				acc.Require(alloc.Client == deal.Client && alloc.Provider == deal.Provider && alloc.Size == deal.PieceSize,
+					"deal %d does not match its pending allocation %d", dealID, allocationID)
+			}
+		} else if claim, found := verifregSummary.Claims[allocationID]; found {
+			acc.Require(claim.DealID == dealID, "deal %d allocation %d claimed by deal %d", dealID, allocationID, claim.DealID)
+		}
+	}
+}
+
 func CheckDealStatesAgainstSectors(acc *builtin.MessageAccumulator, minerSummaries map[addr.Address]*miner.StateSummary, marketSummary *market.StateSummary) {
 	// Check that all active deals are included within a non-terminated sector.
 	// We cannot check that all deals referenced within a sector are in the market, because deals
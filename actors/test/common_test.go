@@ -22,6 +22,7 @@ import (
 
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
 	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
 	"github.com/filecoin-project/specs-actors/v8/support/vm"
 )
@@ -109,6 +110,16 @@ func createMiner(t *testing.T, v *vm.VM, owner, worker addr.Address, wPoStProof
 
 func publishDeal(t *testing.T, v *vm.VM, provider, dealClient, minerID addr.Address, dealLabel string,
 	pieceSize abi.PaddedPieceSize, verifiedDeal bool, dealStart abi.ChainEpoch, dealLifetime abi.ChainEpoch,
+) *market.PublishStorageDealsReturn {
+	return publishDealClaimingAllocation(t, v, provider, dealClient, minerID, dealLabel, pieceSize, verifiedDeal,
+		dealStart, dealLifetime, verifreg.NoAllocationID)
+}
+
+// Publishes a deal which claims an allocation created by its client, or allocates the client's datacap
+// if clientAllocationID is verifreg.NoAllocationID.
+func publishDealClaimingAllocation(t *testing.T, v *vm.VM, provider, dealClient, minerID addr.Address, dealLabel string,
+	pieceSize abi.PaddedPieceSize, verifiedDeal bool, dealStart abi.ChainEpoch, dealLifetime abi.ChainEpoch,
+	clientAllocationID verifreg.AllocationID,
 ) *market.PublishStorageDealsReturn {
 	label, err := market.NewLabelFromString("label")
 	assert.NoError(t, err)
@@ -140,6 +151,9 @@ func publishDeal(t *testing.T, v *vm.VM, provider, dealClient, minerID addr.Addr
 			},
 		}},
 	}
+	if clientAllocationID != verifreg.NoAllocationID {
+		publishDealParams.AllocationIDs = []verifreg.AllocationID{clientAllocationID}
+	}
 	result := vm.RequireApplyMessage(t, v, provider, builtin.StorageMarketActorAddr, big.Zero(), builtin.MethodsMarket.PublishStorageDeals, &publishDealParams, t.Name())
 	require.Equal(t, exitcode.Ok, result.Code)
 
@@ -152,7 +166,7 @@ func publishDeal(t *testing.T, v *vm.VM, provider, dealClient, minerID addr.Addr
 	if verifiedDeal {
 		expectedPublishSubinvocations = append(expectedPublishSubinvocations, vm.ExpectInvocation{
 			To:             builtin.VerifiedRegistryActorAddr,
-			Method:         builtin.MethodsVerifiedRegistry.UseBytes,
+			Method:         builtin.MethodsVerifiedRegistry.AllocateDealDataCap,
 			SubInvocations: []vm.ExpectInvocation{},
 		})
 	}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	addr "github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/minio/blake2b-simd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	init_ "github.com/filecoin-project/specs-actors/v8/actors/builtin/init"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/multisig"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestMultisigBatchIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+
+	alice := vm.RequireNormalizeAddress(t, addrs[0], v)
+	bob := vm.RequireNormalizeAddress(t, addrs[1], v)
+	chuck := vm.RequireNormalizeAddress(t, addrs[2], v)
+
+	getBalance := func(a addr.Address) abi.TokenAmount {
+		act, found, err := v.GetActor(a)
+		require.NoError(t, err)
+		require.True(t, found)
+		return act.Balance
+	}
+
+	paramBuf := new(bytes.Buffer)
+	require.NoError(t, (&multisig.ConstructorParams{
+		Signers:               []addr.Address{alice, bob},
+		NumApprovalsThreshold: 2,
+	}).MarshalCBOR(paramBuf))
+	ret := vm.ApplyOk(t, v, alice, builtin.InitActorAddr, vm.FIL, builtin.MethodsInit.Exec, &init_.ExecParams{
+		CodeCID:           builtin.MultisigActorCodeID,
+		ConstructorParams: paramBuf.Bytes(),
+	})
+	multisigAddr := ret.(*init_.ExecReturn).IDAddress
+
+	addSigner := func(signer addr.Address) []byte {
+		buf := new(bytes.Buffer)
+		require.NoError(t, (&multisig.AddSignerParams{Signer: signer}).MarshalCBOR(buf))
+		return buf.Bytes()
+	}
+	proposeBatch := func(calls ...multisig.BatchedCall) []byte {
+		ret := vm.ApplyOk(t, v, alice, multisigAddr, big.Zero(), builtin.MethodsMultisig.ProposeBatch, &multisig.ProposeBatchParams{Calls: calls})
+		require.False(t, ret.(*multisig.ProposeReturn).Applied)
+
+		buf := new(bytes.Buffer)
+		require.NoError(t, (&multisig.ExecuteBatchParams{Calls: calls}).MarshalCBOR(buf))
+		hash, err := multisig.ComputeProposalHash(&multisig.Transaction{
+			To:       multisigAddr,
+			Value:    big.Zero(),
+			Method:   builtin.MethodsMultisig.ExecuteBatch,
+			Params:   buf.Bytes(),
+			Approved: []addr.Address{alice},
+		}, blake2b.Sum256)
+		require.NoError(t, err)
+		return hash
+	}
+
+	chuckBalance := getBalance(chuck)
+	sendValue := big.Mul(big.NewInt(3), builtin.OneNanoFIL)
+
+	// a batch whose second call fails has no effect
+	hash := proposeBatch(multisig.BatchedCall{
+		To:     chuck,
+		Value:  sendValue,
+		Method: builtin.MethodSend,
+	}, multisig.BatchedCall{
+		To:     multisigAddr,
+		Value:  big.Zero(),
+		Method: builtin.MethodsMultisig.AddSigner,
+		Params: addSigner(bob), // already a signer
+	})
+	ret = vm.ApplyOk(t, v, bob, multisigAddr, big.Zero(), builtin.MethodsMultisig.Approve, &multisig.TxnIDParams{ID: 0, ProposalHash: hash})
+	approveRet := ret.(*multisig.ApproveReturn)
+	assert.True(t, approveRet.Applied)
+	assert.Equal(t, exitcode.ErrForbidden, approveRet.Code)
+	assert.Equal(t, chuckBalance, getBalance(chuck))
+
+	// a batch whose calls all succeed applies them all
+	hash = proposeBatch(multisig.BatchedCall{
+		To:     chuck,
+		Value:  sendValue,
+		Method: builtin.MethodSend,
+	}, multisig.BatchedCall{
+		To:     multisigAddr,
+		Value:  big.Zero(),
+		Method: builtin.MethodsMultisig.AddSigner,
+		Params: addSigner(chuck),
+	})
+	ret = vm.ApplyOk(t, v, bob, multisigAddr, big.Zero(), builtin.MethodsMultisig.Approve, &multisig.TxnIDParams{ID: 1, ProposalHash: hash})
+	approveRet = ret.(*multisig.ApproveReturn)
+	assert.True(t, approveRet.Applied)
+	assert.Equal(t, exitcode.Ok, approveRet.Code)
+	assert.Equal(t, big.Add(chuckBalance, sendValue), getBalance(chuck))
+
+	var st multisig.State
+	require.NoError(t, v.GetState(multisigAddr, &st))
+	assert.Equal(t, []addr.Address{alice, bob, chuck}, st.Signers)
+}
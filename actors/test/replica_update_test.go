@@ -141,6 +141,63 @@ func TestUpgradeAndMissPoSt(t *testing.T) {
 	require.Equal(t, uint64(ss), minerPower.Raw.Uint64())
 }
 
+// Tests that a miner recording sector history records the sealed CIDs replaced by an upgrade
+func TestUpgradeRecordsSectorHistory(t *testing.T) {
+	ctx := context.Background()
+	blkStore := ipld.NewBlockStoreInMemory()
+	v := vm.NewVMWithSingletons(ctx, t, blkStore)
+	addrs := vm.CreateAccounts(ctx, t, v, 1, big.Mul(big.NewInt(100_000), big.NewInt(1e18)), 93837778)
+
+	// create miner
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+	wPoStProof, err := sealProof.RegisteredWindowPoStProof()
+	require.NoError(t, err)
+	owner, worker := addrs[0], addrs[0]
+	minerAddrs := createMiner(t, v, owner, worker, wPoStProof, big.Mul(big.NewInt(10_000), vm.FIL))
+
+	vm.ApplyOk(t, v, owner, minerAddrs.RobustAddress, big.Zero(),
+		builtin.MethodsMiner.ChangeSectorHistoryRecording,
+		&miner.ChangeSectorHistoryRecordingParams{Enabled: true})
+
+	// advance vm so we can have seal randomness epoch in the past
+	v, err = v.WithEpoch(abi.ChainEpoch(200))
+	require.NoError(t, err)
+
+	v, deadlineIndex, partitionIndex, sectorNumber := createSector(t, v, worker, minerAddrs.IDAddress, 100, sealProof)
+	oldSectorInfo := vm.SectorInfo(t, v, minerAddrs.IDAddress, sectorNumber)
+
+	// make some deals
+	dealIDs := createDeals(t, 1, v, worker, worker, minerAddrs.IDAddress, sealProof)
+
+	// replicaUpdate the sector
+	replicaUpdate := miner.ReplicaUpdate{
+		SectorID:           sectorNumber,
+		Deadline:           deadlineIndex,
+		Partition:          partitionIndex,
+		NewSealedSectorCID: tutil.MakeCID("replica", &miner.SealedCIDPrefix),
+		Deals:              dealIDs,
+		UpdateProofType:    abi.RegisteredUpdateProof_StackedDrg32GiBV1,
+	}
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(),
+		builtin.MethodsMiner.ProveReplicaUpdates,
+		&miner.ProveReplicaUpdatesParams{Updates: []miner.ReplicaUpdate{replicaUpdate}})
+
+	var st miner.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &st))
+	history, found, err := st.GetSectorHistory(v.Store(), sectorNumber)
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, history.Events, 2)
+
+	assert.Equal(t, miner.SectorEventActivated, history.Events[0].Type)
+	assert.Equal(t, oldSectorInfo.SealedCID, *history.Events[0].NewSealedCID)
+
+	assert.Equal(t, miner.SectorEventReplicaUpdated, history.Events[1].Type)
+	assert.Equal(t, v.GetEpoch(), history.Events[1].Epoch)
+	assert.Equal(t, oldSectorInfo.SealedCID, *history.Events[1].OldSealedCID)
+	assert.Equal(t, replicaUpdate.NewSealedSectorCID, *history.Events[1].NewSealedCID)
+}
+
 // ---- Failure cases ----
 
 // Tests that a sector in an immutable deadline cannot be upgraded
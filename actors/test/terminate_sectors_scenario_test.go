@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-bitfield"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
@@ -16,6 +17,7 @@ import (
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
 	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
 	"github.com/filecoin-project/specs-actors/v8/actors/runtime/proof"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
 	"github.com/filecoin-project/specs-actors/v8/support/ipld"
 	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
 	"github.com/filecoin-project/specs-actors/v8/support/vm"
@@ -149,6 +151,10 @@ func TestTerminateSectors(t *testing.T) {
 	v, err = v.WithEpoch(v.GetEpoch() + 1)
 	require.NoError(t, err)
 
+	verifiedIDAddr, found := v.NormalizeAddress(verifiedClient)
+	require.True(t, found)
+	dataCapBefore := verifiedClientDataCap(t, v, verifiedIDAddr)
+
 	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.TerminateSectors, &miner.TerminateSectorsParams{
 		Terminations: []miner.TerminationDeclaration{{
 			Deadline:  dlInfo.Index,
@@ -171,6 +177,9 @@ func TestTerminateSectors(t *testing.T) {
 		},
 	}.Matches(t, v.LastInvocation())
 
+	// the verified deals claimed their allocations, so their datacap is held by the claims and not restored
+	assert.Equal(t, dataCapBefore, verifiedClientDataCap(t, v, verifiedIDAddr))
+
 	// expect power, market and miner to be in base state
 	minerBalances := vm.GetMinerBalances(t, v, minerAddrs.IDAddress)
 	assert.Equal(t, big.Zero(), minerBalances.InitialPledge)
@@ -210,8 +219,6 @@ func TestTerminateSectors(t *testing.T) {
 		Amount:                  withdrawal,
 	})
 
-	verifiedIDAddr, found := v.NormalizeAddress(verifiedClient)
-	require.True(t, found)
 	vm.ExpectInvocation{
 		To:     builtin.StorageMarketActorAddr,
 		Method: builtin.MethodsMarket.WithdrawBalance,
@@ -233,3 +240,17 @@ func TestTerminateSectors(t *testing.T) {
 	assert.True(t, big.Mul(big.NewInt(58), vm.FIL).LessThan(valueWithdrawn))
 	assert.True(t, big.Mul(big.NewInt(59), vm.FIL).GreaterThan(valueWithdrawn))
 }
+
+func verifiedClientDataCap(t *testing.T, v *vm.VM, client address.Address) verifreg.DataCap {
+	var st verifreg.State
+	require.NoError(t, v.GetState(builtin.VerifiedRegistryActorAddr, &st))
+	verifiedClients, err := adt.AsMap(v.Store(), st.VerifiedClients, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var dataCap verifreg.DataCap
+	found, err := verifiedClients.Get(abi.AddrKey(client), &dataCap)
+	require.NoError(t, err)
+	if !found {
+		return big.Zero()
+	}
+	return dataCap
+}
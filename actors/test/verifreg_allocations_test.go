@@ -0,0 +1,313 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cbg "github.com/whyrusleeping/cbor-gen"
+
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/market"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/miner"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/power"
+	"github.com/filecoin-project/specs-actors/v8/actors/builtin/verifreg"
+	"github.com/filecoin-project/specs-actors/v8/actors/states"
+	"github.com/filecoin-project/specs-actors/v8/actors/util/adt"
+	"github.com/filecoin-project/specs-actors/v8/support/ipld"
+	tutil "github.com/filecoin-project/specs-actors/v8/support/testing"
+	"github.com/filecoin-project/specs-actors/v8/support/vm"
+)
+
+func TestVerifiedDealActivationClaimsAllocation(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	owner, verifier, client := addrs[0], addrs[1], addrs[2]
+	worker := owner
+
+	sectorNumber := abi.SectorNumber(100)
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+
+	ret := vm.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &power.CreateMinerParams{
+		Owner:               owner,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	})
+	minerAddrs, ok := ret.(*power.CreateMinerReturn)
+	require.True(t, ok)
+
+	// register verifier then verified client
+	clientCap := abi.NewStoragePower(1 << 34)
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &verifreg.AddVerifierParams{
+		Address:   verifier,
+		Allowance: clientCap,
+	})
+	vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient, &verifreg.AddVerifiedClientParams{
+		Address:   client,
+		Allowance: clientCap,
+	})
+
+	clientID, found := v.NormalizeAddress(client)
+	require.True(t, found)
+
+	// the miner publishes a verified deal, allocating the client's datacap to the miner for the piece
+	pieceSize := abi.PaddedPieceSize(1 << 32)
+	collateral := big.Mul(big.NewInt(3), vm.FIL)
+	vm.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, collateral, builtin.MethodsMarket.AddBalance, &client)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(64), vm.FIL), builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+
+	dealStart := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	dealLifetime := abi.ChainEpoch(200 * builtin.EpochsInDay)
+	dealIDs := publishDeal(t, v, worker, client, minerAddrs.IDAddress, "deal1", pieceSize, true, dealStart, dealLifetime).IDs
+	assert.Equal(t, big.Sub(clientCap, big.NewIntUnsigned(uint64(pieceSize))), verifiedClientDataCap(t, v, clientID))
+
+	var marketState market.State
+	require.NoError(t, v.GetState(builtin.StorageMarketActorAddr, &marketState))
+	dealAllocations, err := adt.AsMap(v.Store(), marketState.DealAllocationIds, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var allocationID cbg.CborInt
+	found, err = dealAllocations.Get(abi.UIntKey(uint64(dealIDs[0])), &allocationID)
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// precommit, prove and confirm the sector, activating the deal
+	vm.ApplyOk(t, v, owner, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.PreCommitSector, &miner.PreCommitSectorParams{
+		SealProof:     sealProof,
+		SectorNumber:  sectorNumber,
+		SealedCID:     tutil.MakeCID("100", &miner.SealedCIDPrefix),
+		SealRandEpoch: v.GetEpoch() - 1,
+		DealIDs:       dealIDs,
+		Expiration:    v.GetEpoch() + 220*builtin.EpochsInDay,
+	})
+	proveTime := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	v, _ = vm.AdvanceByDeadlineTillEpoch(t, v, minerAddrs.IDAddress, proveTime)
+	v, err = v.WithEpoch(proveTime)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSector, &miner.ProveCommitSectorParams{
+		SectorNumber: sectorNumber,
+	})
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	state, found := vm.GetDealState(t, v, dealIDs[0])
+	require.True(t, found)
+	assert.Equal(t, proveTime, state.SectorStartEpoch)
+
+	// activation claimed the deal's allocation, earning the sector verified weight
+	claimIDs := []verifreg.ClaimID{verifreg.ClaimID(allocationID)}
+	ret = vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.GetClaims, &verifreg.GetClaimsParams{
+		Provider: minerAddrs.IDAddress,
+		ClaimIDs: claimIDs,
+	})
+	claims := ret.(*verifreg.GetClaimsReturn).Claims
+	require.Len(t, claims, 1)
+	assert.Equal(t, clientID, claims[0].Client)
+	assert.Equal(t, dealIDs[0], claims[0].DealID)
+	assert.Equal(t, proveTime, claims[0].TermStart)
+
+	var minerState miner.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &minerState))
+	sector, found, err := minerState.GetSector(v.Store(), sectorNumber)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, big.Mul(big.NewIntUnsigned(uint64(pieceSize)), big.NewInt(int64(dealLifetime))), sector.VerifiedDealWeight)
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+
+	// the claim is retained until its maximum term has elapsed, then anyone may remove it
+	ret = vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.RemoveExpiredClaims, &verifreg.RemoveExpiredClaimsParams{
+		Provider: minerAddrs.IDAddress,
+	})
+	assert.Empty(t, ret.(*verifreg.RemoveExpiredClaimsReturn).Removed)
+
+	v, err = v.WithEpoch(claims[0].TermStart + claims[0].TermMax + 1)
+	require.NoError(t, err)
+	ret = vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.RemoveExpiredClaims, &verifreg.RemoveExpiredClaimsParams{
+		Provider: minerAddrs.IDAddress,
+	})
+	assert.Equal(t, claimIDs, ret.(*verifreg.RemoveExpiredClaimsReturn).Removed)
+}
+
+func TestVerifiedDealClaimsClientAllocation(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	owner, verifier, client := addrs[0], addrs[1], addrs[2]
+	worker := owner
+
+	sectorNumber := abi.SectorNumber(100)
+	sealProof := abi.RegisteredSealProof_StackedDrg32GiBV1_1
+
+	ret := vm.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &power.CreateMinerParams{
+		Owner:               owner,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	})
+	minerAddrs, ok := ret.(*power.CreateMinerReturn)
+	require.True(t, ok)
+
+	clientCap := abi.NewStoragePower(1 << 34)
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &verifreg.AddVerifierParams{
+		Address:   verifier,
+		Allowance: clientCap,
+	})
+	vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient, &verifreg.AddVerifiedClientParams{
+		Address:   client,
+		Allowance: clientCap,
+	})
+	clientID, found := v.NormalizeAddress(client)
+	require.True(t, found)
+
+	// the client allocates datacap to the miner for the piece
+	pieceSize := abi.PaddedPieceSize(1 << 32)
+	dealStart := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	dealLifetime := abi.ChainEpoch(200 * builtin.EpochsInDay)
+	ret = vm.ApplyOk(t, v, client, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.CreateAllocations, &verifreg.CreateAllocationsParams{
+		Allocations: []verifreg.AllocationRequest{{
+			Provider:   minerAddrs.IDAddress,
+			Data:       tutil.MakeCID("deal1", &market.PieceCIDPrefix),
+			Size:       pieceSize,
+			TermMin:    dealLifetime,
+			TermMax:    verifreg.MaximumVerifiedAllocationTerm,
+			Expiration: dealStart,
+		}},
+	})
+	clientAllocationID := ret.(*verifreg.CreateAllocationsReturn).AllocationIDs[0]
+	assert.Equal(t, big.Sub(clientCap, big.NewIntUnsigned(uint64(pieceSize))), verifiedClientDataCap(t, v, clientID))
+
+	// the miner publishes a verified deal for the piece, which takes the client's allocation rather than
+	// allocating more datacap
+	vm.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(3), vm.FIL), builtin.MethodsMarket.AddBalance, &client)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(64), vm.FIL), builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+	dealIDs := publishDealClaimingAllocation(t, v, worker, client, minerAddrs.IDAddress, "deal1", pieceSize, true, dealStart, dealLifetime, clientAllocationID).IDs
+	assert.Equal(t, big.Sub(clientCap, big.NewIntUnsigned(uint64(pieceSize))), verifiedClientDataCap(t, v, clientID))
+
+	var marketState market.State
+	require.NoError(t, v.GetState(builtin.StorageMarketActorAddr, &marketState))
+	dealAllocations, err := adt.AsMap(v.Store(), marketState.DealAllocationIds, builtin.DefaultHamtBitwidth)
+	require.NoError(t, err)
+	var allocationID cbg.CborInt
+	found, err = dealAllocations.Get(abi.UIntKey(uint64(dealIDs[0])), &allocationID)
+	require.NoError(t, err)
+	require.True(t, found)
+	// the allocation was moved to a new ID, so that no other deal may take it
+	assert.NotEqual(t, clientAllocationID, verifreg.AllocationID(allocationID))
+
+	// precommit, prove and confirm the sector, activating the deal
+	vm.ApplyOk(t, v, owner, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.PreCommitSector, &miner.PreCommitSectorParams{
+		SealProof:     sealProof,
+		SectorNumber:  sectorNumber,
+		SealedCID:     tutil.MakeCID("100", &miner.SealedCIDPrefix),
+		SealRandEpoch: v.GetEpoch() - 1,
+		DealIDs:       dealIDs,
+		Expiration:    v.GetEpoch() + 220*builtin.EpochsInDay,
+	})
+	proveTime := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	v, _ = vm.AdvanceByDeadlineTillEpoch(t, v, minerAddrs.IDAddress, proveTime)
+	v, err = v.WithEpoch(proveTime)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, worker, minerAddrs.RobustAddress, big.Zero(), builtin.MethodsMiner.ProveCommitSector, &miner.ProveCommitSectorParams{
+		SectorNumber: sectorNumber,
+	})
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+
+	state, found := vm.GetDealState(t, v, dealIDs[0])
+	require.True(t, found)
+	assert.Equal(t, proveTime, state.SectorStartEpoch)
+
+	// activation claimed the client's allocation, earning the sector verified weight
+	ret = vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.GetClaims, &verifreg.GetClaimsParams{
+		Provider: minerAddrs.IDAddress,
+		ClaimIDs: []verifreg.ClaimID{verifreg.ClaimID(allocationID)},
+	})
+	claims := ret.(*verifreg.GetClaimsReturn).Claims
+	require.Len(t, claims, 1)
+	assert.Equal(t, clientID, claims[0].Client)
+	assert.Equal(t, dealIDs[0], claims[0].DealID)
+	assert.Equal(t, verifreg.MaximumVerifiedAllocationTerm, claims[0].TermMax)
+
+	var minerState miner.State
+	require.NoError(t, v.GetState(minerAddrs.IDAddress, &minerState))
+	sector, found, err := minerState.GetSector(v.Store(), sectorNumber)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, big.Mul(big.NewIntUnsigned(uint64(pieceSize)), big.NewInt(int64(dealLifetime))), sector.VerifiedDealWeight)
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+}
+
+func TestTimedOutVerifiedDealAllocationReclaimed(t *testing.T) {
+	ctx := context.Background()
+	v := vm.NewVMWithSingletons(ctx, t, ipld.NewBlockStoreInMemory())
+	addrs := vm.CreateAccounts(ctx, t, v, 3, big.Mul(big.NewInt(10_000), vm.FIL), 93837778)
+	owner, verifier, client := addrs[0], addrs[1], addrs[2]
+	worker := owner
+
+	ret := vm.ApplyOk(t, v, owner, builtin.StoragePowerActorAddr, big.Mul(big.NewInt(1_000), vm.FIL), builtin.MethodsPower.CreateMiner, &power.CreateMinerParams{
+		Owner:               owner,
+		Worker:              worker,
+		WindowPoStProofType: abi.RegisteredPoStProof_StackedDrgWindow32GiBV1,
+		Peer:                abi.PeerID("not really a peer id"),
+	})
+	minerAddrs, ok := ret.(*power.CreateMinerReturn)
+	require.True(t, ok)
+
+	clientCap := abi.NewStoragePower(1 << 34)
+	vm.ApplyOk(t, v, vm.VerifregRoot, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifier, &verifreg.AddVerifierParams{
+		Address:   verifier,
+		Allowance: clientCap,
+	})
+	vm.ApplyOk(t, v, verifier, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.AddVerifiedClient, &verifreg.AddVerifiedClientParams{
+		Address:   client,
+		Allowance: clientCap,
+	})
+	clientID, found := v.NormalizeAddress(client)
+	require.True(t, found)
+
+	pieceSize := abi.PaddedPieceSize(1 << 32)
+	vm.ApplyOk(t, v, client, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(3), vm.FIL), builtin.MethodsMarket.AddBalance, &client)
+	vm.ApplyOk(t, v, worker, builtin.StorageMarketActorAddr, big.Mul(big.NewInt(64), vm.FIL), builtin.MethodsMarket.AddBalance, &minerAddrs.IDAddress)
+
+	dealStart := v.GetEpoch() + miner.PreCommitChallengeDelay + 1
+	publishDeal(t, v, worker, client, minerAddrs.IDAddress, "deal1", pieceSize, true, dealStart, 200*builtin.EpochsInDay)
+	assert.Equal(t, big.Sub(clientCap, big.NewIntUnsigned(uint64(pieceSize))), verifiedClientDataCap(t, v, clientID))
+
+	// the deal times out unactivated, leaving its allocation to expire
+	v, err := v.WithEpoch(dealStart + market.DealUpdatesInterval)
+	require.NoError(t, err)
+	vm.ApplyOk(t, v, builtin.SystemActorAddr, builtin.CronActorAddr, big.Zero(), builtin.MethodsCron.EpochTick, nil)
+	assert.Equal(t, big.Sub(clientCap, big.NewIntUnsigned(uint64(pieceSize))), verifiedClientDataCap(t, v, clientID))
+
+	stateTree, err := v.GetStateTree()
+	require.NoError(t, err)
+	totalBalance, err := v.GetTotalActorBalance()
+	require.NoError(t, err)
+	acc, err := states.CheckStateInvariants(stateTree, totalBalance, v.GetEpoch())
+	require.NoError(t, err)
+	assert.True(t, acc.IsEmpty(), strings.Join(acc.Messages(), "\n"))
+
+	// the client reclaims the expired allocation's datacap
+	ret = vm.ApplyOk(t, v, client, builtin.VerifiedRegistryActorAddr, big.Zero(), builtin.MethodsVerifiedRegistry.RemoveExpiredAllocations, &verifreg.RemoveExpiredAllocationsParams{
+		Client: client,
+	})
+	assert.Len(t, ret.(*verifreg.RemoveExpiredAllocationsReturn).Removed, 1)
+	assert.Equal(t, clientCap, verifiedClientDataCap(t, v, clientID))
+}
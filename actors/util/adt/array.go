@@ -120,6 +120,24 @@ func (a *Array) ForEach(out cbor.Unmarshaler, fn func(i int64) error) error {
 	})
 }
 
+// Iterates the entries in the array from index `start`, in ascending order of index, deserializing each value
+// in turn into `out` and then calling a function.
+// Iteration halts if the function returns an error.
+// If the output parameter is nil, deserialization is skipped.
+func (a *Array) ForEachFrom(start uint64, out cbor.Unmarshaler, fn func(i int64) error) error {
+	return a.root.ForEachAt(a.store.Context(), start, func(k uint64, val *cbg.Deferred) error {
+		if out != nil {
+			if deferred, ok := out.(*cbg.Deferred); ok {
+				// fast-path deferred -> deferred to avoid re-decoding.
+				*deferred = *val
+			} else if err := out.UnmarshalCBOR(bytes.NewReader(val.Raw)); err != nil {
+				return err
+			}
+		}
+		return fn(int64(k))
+	})
+}
+
 func (a *Array) Length() uint64 {
 	return a.root.Len()
 }
@@ -103,8 +103,11 @@ func main() {
 	if err := gen.WriteTupleEncodersToFile("./actors/builtin/multisig/cbor_gen.go", "multisig",
 		// actor state
 		multisig.State{},
-		//multisig.Transaction{}, // Aliased from v0
+		multisig.Transaction{},
 		//multisig.ProposalHashData{}, // Aliased from v0
+		multisig.ExpiringProposalHashData{},
+		multisig.ApprovalRule{},
+		multisig.VestingStep{},
 		// method params and returns
 		// multisig.ConstructorParams{}, // Aliased from v2
 		//multisig.ProposeParams{}, // Aliased from v0
@@ -116,6 +119,16 @@ func main() {
 		//multisig.ChangeNumApprovalsThresholdParams{}, // Aliased from v0
 		//multisig.SwapSignerParams{}, // Aliased from v0
 		//multisig.LockBalanceParams{}, // Aliased from v0
+		multisig.ProposeWithExpirationParams{},
+		multisig.RemoveExpiredTransactionsParams{},
+		multisig.RemoveExpiredTransactionsReturn{},
+		multisig.BatchedCall{},
+		multisig.ProposeBatchParams{},
+		multisig.ExecuteBatchParams{},
+		multisig.ExecuteBatchReturn{},
+		multisig.ChangeSignerWeightParams{},
+		multisig.SetApprovalRulesParams{},
+		multisig.LockVestingScheduleParams{},
 	); err != nil {
 		panic(err)
 	}
@@ -124,11 +137,18 @@ func main() {
 		// actor state
 		paych.State{},
 		paych.LaneState{},
+		paych.HTLC{},
 		// method params and returns
 		//paych.ConstructorParams{}, // Aliased from v0
 		//paych.UpdateChannelStateParams{}, // Aliased from v7
 		//paych.SignedVoucher{},            // Aliased from v7
 		//paych.ModVerifyParams{}, // Aliased from v0
+		paych.UpdateChannelStateBatchParams{},
+		paych.UpdateChannelStateBatchReturn{},
+		paych.WithdrawParams{},
+		paych.LockHTLCParams{},
+		paych.ClaimHTLCParams{},
+		paych.ReclaimHTLCParams{},
 		// other types
 		//paych.Merge{}, // Aliased from v0
 	); err != nil {
@@ -158,17 +178,42 @@ func main() {
 		market.DealState{},
 		// method params and returns
 		//market.WithdrawBalanceParams{}, // Aliased from v0
-		market.PublishStorageDealsParams{},
-		//market.PublishStorageDealsReturn{}, // Aliased from v6
 		//market.ActivateDealsParams{}, // Aliased from v0
 		//market.VerifyDealsForActivationParams{}, // Aliased from v3
 		//market.VerifyDealsForActivationReturn{}, // Aliased from v3
 		//market.ComputeDataCommitmentParams{}, // Aliased from v5
 		//market.ComputeDataCommitmentReturn{}, // Aliased from v5
 		//market.OnMinerSectorsTerminateParams{}, // Aliased from v0
+		market.SettleDealPaymentsParams{},
+		market.SettleDealPaymentsReturn{},
+		market.GetBalanceReturn{},
+		market.DealQueryParams{},
+		market.GetDealDataCommitmentReturn{},
+		market.GetDealTermReturn{},
+		market.GetDealActivationReturn{},
+		market.CancelDealsParams{},
+		market.ExtendDealsParams{},
+		market.ExtendDealsReturn{},
+		market.GetDealsForPieceParams{},
+		market.GetDealsForPieceReturn{},
+		market.SetAutoWithdrawParams{},
+		market.GetEscrowUnlockScheduleParams{},
+		market.GetEscrowUnlockScheduleReturn{},
+		market.BatchActivateDealsParams{},
+		market.BatchActivateDealsReturn{},
+		market.OnMinerSectorsPreCommitParams{},
+		market.OnMinerPreCommitsRemovedParams{},
 		// other types
 		market.DealProposal{},       // Changed in v7
 		market.ClientDealProposal{}, // Changed in v7
+		market.DealSettlementSummary{},
+		market.DealSettlementError{},
+		market.DealExtension{},
+		market.PieceDeal{},
+		market.EscrowUnlock{},
+		market.ClientDealExtension{},
+		market.SectorDealsActivation{},
+		market.SectorDealsActivated{},
 		// market.SectorDeals{},     // Aliased from v3
 		// market.SectorWeights{},   // Aliased from v3
 	); err != nil {
@@ -214,6 +259,15 @@ func main() {
 		// miner.DisputeWindowedPoStParams{}, // Aliased from v3
 		//miner.PreCommitSectorBatchParams{}, // Aliased from v5
 		//miner.ProveReplicaUpdatesParams{}, // Aliased from v7
+		miner.CancelPreCommitsParams{},
+		miner.GetTerminationFeesParams{},
+		miner.GetTerminationFeesReturn{},
+		miner.ChangeMinimumUnlockedReserveParams{},
+		miner.ChangeSectorHistoryRecordingParams{},
+		miner.DeclareFaultsBySectorParams{},
+		miner.DeclareFaultsRecoveredBySectorParams{},
+		miner.SectorDeclarationsReturn{},
+		miner.ExtendSectorDealsParams{},
 		// other types
 		//miner.FaultDeclaration{}, // Aliased from v0
 		//miner.RecoveryDeclaration{}, // Aliased from v0
@@ -221,6 +275,10 @@ func main() {
 		//miner.TerminationDeclaration{}, // Aliased from v0
 		//miner.PoStPartition{}, // Aliased from v0
 		//miner.ReplicaUpdate{}, // Aliased from v7
+		miner.SectorTerminationFee{},
+		miner.SectorHistory{},
+		miner.SectorEvent{},
+		miner.SectorDeclarationError{},
 	); err != nil {
 		panic(err)
 	}
@@ -236,10 +294,31 @@ func main() {
 		//verifreg.RestoreBytesParams{}, // Aliased from v0
 		verifreg.RemoveDataCapParams{}, // New in v7
 		verifreg.RemoveDataCapReturn{}, // New in v7
+		verifreg.CreateAllocationsParams{},
+		verifreg.CreateAllocationsReturn{},
+		verifreg.ClaimAllocationsParams{},
+		verifreg.ClaimAllocationsReturn{},
+		verifreg.RemoveExpiredAllocationsParams{},
+		verifreg.RemoveExpiredAllocationsReturn{},
+		verifreg.GetClaimsParams{},
+		verifreg.GetClaimsReturn{},
+		verifreg.AllocateDealDataCapParams{},
+		verifreg.AllocateDealDataCapReturn{},
+		verifreg.RemoveExpiredClaimsParams{},
+		verifreg.RemoveExpiredClaimsReturn{},
+		verifreg.SetVerifierExpirationParams{},
+		verifreg.GetVerifierAuditReturn{},
+		verifreg.TransferDataCapParams{},
 		// other types
 		verifreg.RemoveDataCapRequest{},  // New in v7
 		verifreg.RemoveDataCapProposal{}, // New in v7
 		verifreg.RmDcProposalID{},        // New in v7
+		verifreg.Allocation{},
+		verifreg.Claim{},
+		verifreg.AllocationRequest{},
+		verifreg.ClaimAllocationRequest{},
+		verifreg.VerifierAudit{},
+		verifreg.TransferDataCapProposal{},
 	); err != nil {
 		panic(err)
 	}
@@ -146,15 +146,17 @@ func (dca *DealClientAgent) createDeal(s SimState, provider DealProvider) error
 		dealEnd = dealStart + market.DealMinDuration
 	}
 
-	// lower expected balance in anticipation of market actor locking storage fee
+	// lower expected balance in anticipation of market actor locking storage fee and collateral
 	storageFee := big.Mul(big.NewInt(int64(dealEnd-dealStart)), price)
+	clientCollateral, _ := market.DealClientCollateralBoundsV17(abi.PaddedPieceSize(pieceSize), dealEnd-dealStart, price)
+	balanceRequirement := big.Add(storageFee, clientCollateral)
 
-	// if this client does not have enough balance for storage fee, just skip this deal
-	if dca.expectedMarketBalance.LessThan(storageFee) {
+	// if this client does not have enough balance for storage fee and collateral, just skip this deal
+	if dca.expectedMarketBalance.LessThan(balanceRequirement) {
 		return nil
 	}
 
-	dca.expectedMarketBalance = big.Sub(dca.expectedMarketBalance, storageFee)
+	dca.expectedMarketBalance = big.Sub(dca.expectedMarketBalance, balanceRequirement)
 	label, err := market.NewLabelFromString(dca.account.String() + ":" + strconv.Itoa(dca.DealCount))
 	if err != nil {
 		return err
@@ -170,7 +172,7 @@ func (dca *DealClientAgent) createDeal(s SimState, provider DealProvider) error
 		EndEpoch:             dealEnd,
 		StoragePricePerEpoch: price,
 		ProviderCollateral:   providerCollateral,
-		ClientCollateral:     big.Zero(),
+		ClientCollateral:     clientCollateral,
 	}
 
 	paramBuf := new(bytes.Buffer)